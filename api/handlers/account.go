@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/cache"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/internal/jobs"
+)
+
+func RequestDataExport(uStorer domain.UserStorer, jStorer domain.JobStorer) gin.HandlerFunc {
+	return requestJob(uStorer, jStorer, domain.JobKindExport, domain.NewExportJob)
+}
+
+func RequestErasure(uStorer domain.UserStorer, jStorer domain.JobStorer) gin.HandlerFunc {
+	return requestJob(uStorer, jStorer, domain.JobKindErase, domain.NewEraseJob)
+}
+
+func requestJob(uStorer domain.UserStorer, jStorer domain.JobStorer, kind string, newJob func(*domain.User) *domain.Job) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		_, err := jStorer.GetPendingJobByUser(c, user.ExternalId, kind)
+		if err == nil {
+			DefaultError(c, errs.ErrJobAlreadyRequested)
+			return
+		}
+		if !errors.Is(err, errs.ErrSelectNotMatch) {
+			DefaultError(c, err)
+			return
+		}
+
+		job := newJob(user)
+		if _, err := jStorer.CreateJob(c, job); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, job)
+	}
+}
+
+func CancelErasure(uStorer domain.UserStorer, jStorer domain.JobStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		job, err := jStorer.GetPendingJobByUser(c, user.ExternalId, domain.JobKindErase)
+		if err != nil {
+			if errors.Is(err, errs.ErrSelectNotMatch) {
+				DefaultError(c, errs.ErrNotFound)
+				return
+			}
+
+			DefaultError(c, err)
+			return
+		}
+
+		if err := job.Cancel(); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		if err := jStorer.UpdateJob(c, job); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, job)
+	}
+}
+
+func GetJobStatus(uStorer domain.UserStorer, jStorer domain.JobStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		id := c.Param("id")
+		if id == "" {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+
+		job, err := jStorer.GetJobByExternalId(c, id)
+		if err != nil {
+			if errors.Is(err, errs.ErrSelectNotMatch) {
+				DefaultError(c, errs.ErrNotFound)
+				return
+			}
+
+			DefaultError(c, err)
+			return
+		}
+
+		if job.UserId != user.Id {
+			DefaultError(c, errs.ErrNotFound)
+			return
+		}
+
+		c.JSON(http.StatusOK, job)
+	}
+}
+
+func DownloadExport(jStorer domain.JobStorer, cacher cache.ConnectionStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Param("token")
+		if token == "" {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+
+		jobId, err := cacher.Get(c, jobs.ExportKey(token))
+		if err != nil {
+			if errors.Is(err, cache.ErrNil) {
+				DefaultError(c, errs.ErrExportExpired)
+				return
+			}
+
+			DefaultError(c, err)
+			return
+		}
+
+		job, err := jStorer.GetJobByExternalId(c, jobId)
+		if err != nil {
+			if errors.Is(err, errs.ErrSelectNotMatch) {
+				DefaultError(c, errs.ErrExportExpired)
+				return
+			}
+
+			DefaultError(c, err)
+			return
+		}
+
+		if job.Status != domain.JobStatusDone || job.Result == "" {
+			DefaultError(c, errs.ErrExportExpired)
+			return
+		}
+
+		c.FileAttachment(job.Result, "plant-care-tracker-export.zip")
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 200
+)
+
+// GetAuditLog lists the audit entries of the changes made to the user's
+// data, newest first. Admins see every entry and can narrow it down to one
+// user with ?userId=. Entries can be filtered by ?entityType=, ?entityId=,
+// ?action=, ?actorId=, ?from= and ?to=, and paged with ?before= set to the
+// nextBefore of the previous page.
+func GetAuditLog(uStorer domain.UserStorer, aStorer domain.AuditStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		filter := domain.AuditFilter{
+			OwnerId:    user.Id,
+			ActorId:    c.Query("actorId"),
+			Action:     c.Query("action"),
+			EntityType: c.Query("entityType"),
+			EntityId:   c.Query("entityId"),
+			Limit:      defaultAuditLimit,
+		}
+
+		if domain.HasRole(c.GetStringSlice("auth:bearer:roles"), domain.RoleAdmin) {
+			filter.OwnerId = 0
+			if id := c.Query("userId"); id != "" {
+				owner, err := uStorer.GetUserByExternalId(c.Request.Context(), id)
+				if err != nil {
+					if errors.Is(err, errs.ErrSelectNotMatch) {
+						DefaultError(c, errs.ErrNotFound)
+						return
+					}
+					DefaultError(c, err)
+					return
+				}
+				filter.OwnerId = owner.Id
+			}
+		}
+
+		var err error
+		if v := c.Query("from"); v != "" {
+			if filter.From, err = parseRangeTime(v); err != nil {
+				DefaultError(c, errs.ErrInvalidBody)
+				return
+			}
+		}
+		if v := c.Query("to"); v != "" {
+			if filter.To, err = parseRangeTime(v); err != nil {
+				DefaultError(c, errs.ErrInvalidBody)
+				return
+			}
+		}
+		if v := c.Query("before"); v != "" {
+			if filter.BeforeId, err = strconv.ParseInt(v, 10, 64); err != nil {
+				DefaultError(c, errs.ErrInvalidBody)
+				return
+			}
+		}
+		if v := c.Query("limit"); v != "" {
+			if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit < 1 || filter.Limit > maxAuditLimit {
+				DefaultError(c, errs.ErrInvalidBody)
+				return
+			}
+		}
+
+		entries, err := aStorer.GetAuditEntries(c.Request.Context(), filter)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		var nextBefore int64
+		if len(entries) == filter.Limit {
+			nextBefore = entries[len(entries)-1].Id
+		}
+
+		c.JSON(http.StatusOK, gin.H{"entries": entries, "nextBefore": nextBefore})
+	}
+}
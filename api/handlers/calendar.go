@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+// GetCalendar returns one entry per day of ?month=YYYY-MM, in the user's
+// timezone, with the cares done, overdue or scheduled on that day.
+func GetCalendar(uStorer domain.UserStorer, pStorer domain.PlantStorer, cStorer domain.CareStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		now := time.Now()
+		loc := user.Location()
+
+		month := now.In(loc)
+		if m := c.Query("month"); m != "" {
+			var err error
+			month, err = time.ParseInLocation("2006-01", m, loc)
+			if err != nil {
+				DefaultError(c, errs.ErrInvalidMonth)
+				return
+			}
+		}
+		start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, loc)
+		end := start.AddDate(0, 1, 0)
+
+		plants, err := pStorer.GetPlantsByUserID(c.Request.Context(), user.Id)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		cares, err := cStorer.GetCaresByUserID(c.Request.Context(), user.Id)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		history, err := cStorer.GetCareHistoryByUserID(c.Request.Context(), user.Id, start, end)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"month":    start.Format("2006-01"),
+			"timezone": loc.String(),
+			"days":     domain.BuildCalendar(start, now, loc, plants, cares, history),
+		})
+	}
+}
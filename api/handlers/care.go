@@ -3,71 +3,75 @@ package handlers
 import (
 	"errors"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
 	"github.com/mathehluiz/plant-care-tracker/internal/errs"
 	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
 )
 
-func CreateCare(storer domain.CareStorer) gin.HandlerFunc {
+// CreateCare adds a care to a plant the user may access. The care belongs to
+// the owner of the plant.
+func CreateCare(uStorer domain.UserStorer, cStorer domain.CareStorer, pStorer domain.PlantStorer, policy *authz.Policy) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		req := struct {
-			PlantId   int64     `json:"plantId"`
-			NextCare  time.Time `json:"nextCare"`
-			Name      string    `json:"name"`
-			Notes     string    `json:"notes"`
+			PlantId  int64       `json:"plantId"`
+			NextCare time.Time   `json:"nextCare"`
+			Name     string      `json:"name"`
+			Notes    string      `json:"notes"`
+			RRule    string      `json:"rrule"`
+			Timezone string      `json:"timezone"`
+			ExDates  []time.Time `json:"exdates"`
 		}{}
-		userId := c.GetString("auth:bearer:id")
-		parsedUserId, err := strconv.ParseInt(userId, 10, 64)
-		if err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		if err := c.ShouldBindJSON(&req); err != nil {
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
-		if err := c.ShouldBindJSON(&req); err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		if err := validate.Struct(req); err != nil {
+			DefaultError(c, err)
 			return
 		}
-		validations := validate.Struct(req)
-		if len(validations) > 0 {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+
+		plant, ok := getPlantByID(c, uStorer, pStorer, policy, req.PlantId)
+		if !ok {
 			return
 		}
 
-		care, err := domain.NewCare(req.PlantId, parsedUserId, req.NextCare, req.Name, req.Notes)
+		recurrence, err := careRecurrence(req.RRule, req.Timezone, req.ExDates)
 		if err != nil {
-			DefaultError(c, http.StatusBadRequest, err)
+			DefaultError(c, err)
 			return
 		}
 
-		id, err := storer.CreateCare(c.Request.Context(), care)
+		care, err := domain.NewCare(plant.Id, plant.UserId, req.NextCare, req.Name, req.Notes, recurrence)
 		if err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
+		id, err := cStorer.CreateCare(c.Request.Context(), care)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		setETag(c, care.Version)
 		c.JSON(http.StatusCreated, gin.H{"id": id})
 	}
 }
 
-func GetPlantCares(storer domain.CareStorer) gin.HandlerFunc {
+func GetPlantCares(uStorer domain.UserStorer, cStorer domain.CareStorer, pStorer domain.PlantStorer, policy *authz.Policy) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.Param("id")
-		if id == "" {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
-			return
-		}
-		plantId, err := strconv.ParseInt(id, 10, 64)
-		if err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		plant, ok := getPlant(c, uStorer, pStorer, policy)
+		if !ok {
 			return
 		}
 
-		cares, err := storer.GetPlantCares(c.Request.Context(), plantId)
+		cares, err := cStorer.GetPlantCares(c.Request.Context(), plant.Id)
 		if err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
@@ -75,120 +79,160 @@ func GetPlantCares(storer domain.CareStorer) gin.HandlerFunc {
 	}
 }
 
-func GetCareByID(storer domain.CareStorer) gin.HandlerFunc {
+// GetDueCares lists the cares due today or overdue, where "today" is the
+// current calendar day in the user's timezone. Cares of plants that are no
+// longer active are left out.
+func GetDueCares(uStorer domain.UserStorer, pStorer domain.PlantStorer, cStorer domain.CareStorer) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.Param("id")
-		if id == "" {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		user, ok := currentUser(c, uStorer)
+		if !ok {
 			return
 		}
-		careId, err := strconv.ParseInt(id, 10, 64)
+
+		plants, err := pStorer.GetPlantsByUserID(c.Request.Context(), user.Id, domain.PlantStatusActive)
 		if err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, err)
 			return
 		}
 
-		care, err := storer.GetCareByID(c.Request.Context(), careId)
+		active := make(map[int64]bool, len(plants))
+		for _, plant := range plants {
+			active[plant.Id] = true
+		}
+
+		cares, err := cStorer.GetCaresByUserID(c.Request.Context(), user.Id)
 		if err != nil {
-			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
-				return
+			DefaultError(c, err)
+			return
+		}
+
+		now := time.Now()
+		loc := user.Location()
+
+		due := make([]*domain.Care, 0, len(cares))
+		for _, care := range cares {
+			if active[care.PlantId] && care.IsDue(now, loc) {
+				due = append(due, care)
 			}
-			DefaultError(c, http.StatusInternalServerError, err)
+		}
+
+		today, _ := user.Today(now)
+		c.JSON(http.StatusOK, gin.H{"date": today.Format("2006-01-02"), "timezone": loc.String(), "cares": due})
+	}
+}
+
+func GetCareByID(uStorer domain.UserStorer, cStorer domain.CareStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		care, ok := getCare(c, uStorer, cStorer, policy)
+		if !ok {
 			return
 		}
 
+		setETag(c, care.Version)
 		c.JSON(http.StatusOK, care)
 	}
 }
 
-func UpdateCare(storer domain.CareStorer) gin.HandlerFunc {
+// UpdateCare applies a merge patch to a care. A care can only be moved to
+// another plant of the same owner.
+func UpdateCare(uStorer domain.UserStorer, cStorer domain.CareStorer, pStorer domain.PlantStorer, policy *authz.Policy) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		req := struct {
-			PlantId   int64     `json:"plantId"`
-			LastCare  time.Time `json:"lastCare"`
-			NextCare  time.Time `json:"nextCare"`
-			Name      string    `json:"name"`
-			Notes     string    `json:"notes"`
-		}{}
-		id := c.Param("id")
-		careId, err := strconv.ParseInt(id, 10, 64)
-		if err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		patch, ok := readMergePatch(c)
+		if !ok {
+			return
 		}
 
-		userID := c.GetString("auth:bearer:id")
-		parsedUserID, err := strconv.ParseInt(userID, 10, 64)
-		if err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		version, ok := ifMatch(c)
+		if !ok {
+			return
 		}
 
-		if err := c.ShouldBindJSON(&req); err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		care, ok := getCare(c, uStorer, cStorer, policy)
+		if !ok {
 			return
 		}
-		validations := validate.Struct(req)
-		if len(validations) > 0 {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+
+		if !checkVersion(c, version, care.Version) {
 			return
 		}
 
-		care, err := storer.GetCareByID(c.Request.Context(), careId)
-		if err != nil {
-			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
-				return
-			}
-			DefaultError(c, http.StatusInternalServerError, err)
+		plantId := care.PlantId
+		if err := care.Patch(patch); err != nil {
+			DefaultError(c, err)
+			return
 		}
 
-		err = care.Update(req.PlantId, parsedUserID, req.LastCare, req.NextCare, req.Name, req.Notes)
-		if err != nil {
-			if errors.Is(err, errs.ErrInvalidCareName) {
-				DefaultError(c, http.StatusBadRequest, err)
+		if care.PlantId != plantId {
+			plant, err := pStorer.GetPlantByID(c.Request.Context(), care.PlantId)
+			if err != nil && !errors.Is(err, errs.ErrSelectNotMatch) {
+				DefaultError(c, err)
 				return
 			}
-			if errors.Is(err, errs.ErrInvalidCareNotes) {
-				DefaultError(c, http.StatusBadRequest, err)
+			if err != nil || plant.UserId != care.UserId {
+				var fields errs.FieldErrors
+				fields.Add("plantId", errs.ErrNotFound)
+				DefaultError(c, fields)
 				return
 			}
 		}
 
-		err = storer.UpdateCare(c.Request.Context(), care)
-		if err != nil {
-			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
-				return
-			}
-			DefaultError(c, http.StatusInternalServerError, err)
+		if err := cStorer.UpdateCare(c.Request.Context(), care); err != nil {
+			versionedWriteError(c, err)
+			return
 		}
 
+		setETag(c, care.Version)
 		c.JSON(http.StatusOK, gin.H{"message": "Successfully updated"})
 	}
 }
 
-func DeleteCare(storer domain.CareStorer) gin.HandlerFunc {
+// CompleteCare records the care as done and schedules its next occurrence.
+// A care with a checklist can only be completed once every item is checked.
+func CompleteCare(uStorer domain.UserStorer, cStorer domain.CareStorer, pStorer domain.PlantStorer, policy *authz.Policy) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.Param("id")
-		if id == "" {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		care, ok := getCare(c, uStorer, cStorer, policy)
+		if !ok {
 			return
 		}
-		careId, err := strconv.ParseInt(id, 10, 64)
-		if err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+
+		plant, ok := getCarePlant(c, pStorer, care)
+		if !ok {
 			return
 		}
 
-		err = storer.DeleteCare(c.Request.Context(), careId)
+		entry, err := care.Complete(time.Now().UTC(), plant.CareFrequency)
 		if err != nil {
-			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
-				return
-			}
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
+			return
+		}
+
+		if err := cStorer.UpdateCare(c.Request.Context(), care, entry); err != nil {
+			writeError(c, err)
+			return
+		}
+
+		setETag(c, care.Version)
+		c.JSON(http.StatusOK, care)
+	}
+}
+
+func DeleteCare(uStorer domain.UserStorer, cStorer domain.CareStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version, ok := ifMatch(c)
+		if !ok {
+			return
+		}
+
+		care, ok := getCare(c, uStorer, cStorer, policy)
+		if !ok {
+			return
+		}
+
+		if err := cStorer.DeleteCare(c.Request.Context(), care.Id, version); err != nil {
+			versionedWriteError(c, err)
+			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{"message": "Successfully deleted"})
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
+)
+
+func CreateCareTemplate(uStorer domain.UserStorer, tStorer domain.CareTemplateStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := struct {
+			Name  string `json:"name" validate:"required"`
+			Items []struct {
+				Name     string `json:"name" validate:"required"`
+				Notes    string `json:"notes"`
+				Interval int    `json:"interval" validate:"required"`
+				Offset   int    `json:"offset"`
+			} `json:"items" validate:"required,dive"`
+		}{}
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+		if err := validate.Struct(req); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		items := make([]*domain.CareTemplateItem, 0, len(req.Items))
+		for _, item := range req.Items {
+			items = append(items, &domain.CareTemplateItem{
+				Name:     item.Name,
+				Notes:    item.Notes,
+				Interval: item.Interval,
+				Offset:   item.Offset,
+			})
+		}
+
+		template, err := domain.NewCareTemplate(user.Id, req.Name, items)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		id, err := tStorer.CreateCareTemplate(c.Request.Context(), template)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"id": id})
+	}
+}
+
+func GetCareTemplates(uStorer domain.UserStorer, tStorer domain.CareTemplateStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		templates, err := tStorer.GetCareTemplatesByUserID(c.Request.Context(), user.Id)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, templates)
+	}
+}
+
+func GetCareTemplateByID(uStorer domain.UserStorer, tStorer domain.CareTemplateStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		template, ok := visibleCareTemplate(c, uStorer, tStorer, c.Param("id"))
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, template)
+	}
+}
+
+func DeleteCareTemplate(uStorer domain.UserStorer, tStorer domain.CareTemplateStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		template, ok := visibleCareTemplate(c, uStorer, tStorer, c.Param("id"))
+		if !ok {
+			return
+		}
+
+		if template.BuiltIn {
+			DefaultError(c, errs.ErrBuiltInCareTemplate)
+			return
+		}
+
+		if err := tStorer.DeleteCareTemplate(c.Request.Context(), template.Id); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Care template deleted successfully"})
+	}
+}
+
+// ApplyCareTemplate creates the template cares for a plant. Items that were
+// already applied to the plant are skipped, so calling it again is a no-op.
+func ApplyCareTemplate(uStorer domain.UserStorer, pStorer domain.PlantStorer, tStorer domain.CareTemplateStorer, cStorer domain.CareStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		plant, ok := getPlant(c, uStorer, pStorer, policy)
+		if !ok {
+			return
+		}
+
+		template, ok := visibleCareTemplate(c, uStorer, tStorer, c.Param("templateId"))
+		if !ok {
+			return
+		}
+
+		ids, err := cStorer.CreateCares(c.Request.Context(), template.Instantiate(plant, time.Now()))
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		status := http.StatusOK
+		if len(ids) > 0 {
+			status = http.StatusCreated
+		}
+
+		c.JSON(status, gin.H{"ids": ids, "skipped": len(template.Items) - len(ids)})
+	}
+}
+
+func visibleCareTemplate(c *gin.Context, uStorer domain.UserStorer, tStorer domain.CareTemplateStorer, id string) (*domain.CareTemplate, bool) {
+	templateId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		DefaultError(c, errs.ErrInvalidBody)
+		return nil, false
+	}
+
+	user, ok := currentUser(c, uStorer)
+	if !ok {
+		return nil, false
+	}
+
+	template, err := tStorer.GetCareTemplateByID(c.Request.Context(), templateId)
+	if err != nil {
+		if errors.Is(err, errs.ErrSelectNotMatch) {
+			DefaultError(c, errs.ErrNotFound)
+			return nil, false
+		}
+		DefaultError(c, err)
+		return nil, false
+	}
+
+	if !template.VisibleTo(user.Id) {
+		DefaultError(c, errs.ErrNotFound)
+		return nil, false
+	}
+
+	return template, true
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
+)
+
+func GetCareChecklist(uStorer domain.UserStorer, cStorer domain.CareStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		care, ok := getCare(c, uStorer, cStorer, policy)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"complete": care.ChecklistComplete(), "items": care.Checklist})
+	}
+}
+
+func SetCareChecklist(uStorer domain.UserStorer, cStorer domain.CareStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := struct {
+			Items []string `json:"items"`
+		}{}
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+
+		care, ok := getCare(c, uStorer, cStorer, policy)
+		if !ok {
+			return
+		}
+
+		if err := care.SetChecklist(req.Items); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		if err := cStorer.SetChecklist(c.Request.Context(), care); err != nil {
+			writeError(c, err)
+			return
+		}
+
+		setETag(c, care.Version)
+		c.JSON(http.StatusOK, gin.H{"complete": care.ChecklistComplete(), "items": care.Checklist})
+	}
+}
+
+// CheckChecklistItem ticks or unticks a checklist item. Ticking the last
+// unchecked item completes the care, which schedules the next occurrence and
+// resets the checklist.
+func CheckChecklistItem(uStorer domain.UserStorer, cStorer domain.CareStorer, pStorer domain.PlantStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := struct {
+			Checked *bool `json:"checked" validate:"required"`
+		}{}
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+		if err := validate.Struct(req); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		itemId, err := strconv.ParseInt(c.Param("itemId"), 10, 64)
+		if err != nil {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+
+		care, ok := getCare(c, uStorer, cStorer, policy)
+		if !ok {
+			return
+		}
+
+		now := time.Now().UTC()
+		if err := care.CheckItem(itemId, *req.Checked, now); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		var history []*domain.CareHistoryEntry
+		if *req.Checked && care.ChecklistComplete() {
+			plant, ok := getCarePlant(c, pStorer, care)
+			if !ok {
+				return
+			}
+
+			entry, err := care.Complete(now, plant.CareFrequency)
+			if err != nil {
+				DefaultError(c, err)
+				return
+			}
+			history = append(history, entry)
+		}
+
+		if err := cStorer.UpdateCare(c.Request.Context(), care, history...); err != nil {
+			writeError(c, err)
+			return
+		}
+
+		setETag(c, care.Version)
+		c.JSON(http.StatusOK, gin.H{"completed": len(history) > 0, "care": care})
+	}
+}
+
+// getCare loads the care in the id path parameter, responding 404 when it
+// does not exist or the user may not access it.
+func getCare(c *gin.Context, uStorer domain.UserStorer, cStorer domain.CareStorer, policy *authz.Policy) (*domain.Care, bool) {
+	careId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		DefaultError(c, errs.ErrInvalidBody)
+		return nil, false
+	}
+
+	care, err := cStorer.GetCareByID(c.Request.Context(), careId)
+	if err != nil {
+		if errors.Is(err, errs.ErrSelectNotMatch) {
+			DefaultError(c, errs.ErrNotFound)
+			return nil, false
+		}
+		DefaultError(c, err)
+		return nil, false
+	}
+
+	if !authorize(c, uStorer, policy, domain.AuditEntityCare, care.Id, care.UserId) {
+		return nil, false
+	}
+
+	return care, true
+}
+
+func getCarePlant(c *gin.Context, storer domain.PlantStorer, care *domain.Care) (*domain.Plant, bool) {
+	plant, err := storer.GetPlantByID(c.Request.Context(), care.PlantId)
+	if err != nil {
+		if errors.Is(err, errs.ErrSelectNotMatch) {
+			DefaultError(c, errs.ErrNotFound)
+			return nil, false
+		}
+		DefaultError(c, err)
+		return nil, false
+	}
+
+	return plant, true
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// docsInitializer points the Swagger UI at the document served by OpenAPI.
+const docsInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "/api/v1/openapi.json",
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      SwaggerUIStandalonePreset
+    ],
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl
+    ],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// OpenAPI serves the OpenAPI document of the API.
+func OpenAPI(spec []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", spec)
+	}
+}
+
+// Docs redirects to the Swagger UI, so that its relative links resolve.
+func Docs() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/api/v1/docs/index.html")
+	}
+}
+
+// DocsFile serves the files of the Swagger UI.
+func DocsFile() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		file := c.Param("file")
+		if file == "swagger-initializer.js" {
+			c.Data(http.StatusOK, "text/javascript; charset=utf-8", []byte(docsInitializer))
+			return
+		}
+
+		data, err := fs.ReadFile(swaggerFiles.FS, file)
+		if err != nil {
+			DefaultError(c, errs.ErrNotFound)
+			return
+		}
+
+		contentType := mime.TypeByExtension(path.Ext(file))
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+		c.Data(http.StatusOK, contentType, data)
+	}
+}
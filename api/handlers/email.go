@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mathehluiz/plant-care-tracker/api/problem"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/i18n"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mailer"
+)
+
+const (
+	defaultEmailLimit = 50
+	maxEmailLimit     = 200
+)
+
+// GetEmails lists the emails sent, or waiting to be sent, to the user,
+// newest first, with their delivery status. Admins see every email and can
+// narrow it down to one address with ?to=. Emails can be filtered by
+// ?status=, one of queued, sent or dead, and paged with ?before= set to the
+// nextBefore of the previous page.
+func GetEmails(uStorer domain.UserStorer, eStorer domain.EmailStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		filter := domain.EmailFilter{
+			Recipient: user.Email,
+			Status:    c.Query("status"),
+			Limit:     defaultEmailLimit,
+		}
+
+		if domain.HasRole(c.GetStringSlice("auth:bearer:roles"), domain.RoleAdmin) {
+			filter.Recipient = c.Query("to")
+		}
+
+		switch filter.Status {
+		case "", domain.EmailQueued, domain.EmailSent, domain.EmailDead:
+		default:
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+
+		var err error
+		if v := c.Query("before"); v != "" {
+			if filter.BeforeId, err = strconv.ParseInt(v, 10, 64); err != nil {
+				DefaultError(c, errs.ErrInvalidBody)
+				return
+			}
+		}
+		if v := c.Query("limit"); v != "" {
+			if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit < 1 || filter.Limit > maxEmailLimit {
+				DefaultError(c, errs.ErrInvalidBody)
+				return
+			}
+		}
+
+		emails, err := eStorer.GetEmailDeliveries(c.Request.Context(), filter)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		var nextBefore int64
+		if len(emails) == filter.Limit {
+			nextBefore = emails[len(emails)-1].Id
+		}
+
+		c.JSON(http.StatusOK, gin.H{"emails": emails, "nextBefore": nextBefore})
+	}
+}
+
+// GetEmailByID returns the delivery status of an email sent to the user, or
+// of any email for admins.
+func GetEmailByID(uStorer domain.UserStorer, eStorer domain.EmailStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		id := c.Param("id")
+		if _, err := uuid.Parse(id); err != nil {
+			DefaultError(c, errs.ErrNotFound)
+			return
+		}
+
+		email, err := eStorer.GetEmailDeliveryByExternalId(c.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, errs.ErrSelectNotMatch) {
+				DefaultError(c, errs.ErrNotFound)
+				return
+			}
+			DefaultError(c, err)
+			return
+		}
+
+		if email.Recipient != user.Email && !domain.HasRole(c.GetStringSlice("auth:bearer:roles"), domain.RoleAdmin) {
+			DefaultError(c, errs.ErrNotFound)
+			return
+		}
+
+		c.JSON(http.StatusOK, email)
+	}
+}
+
+// PreviewEmail renders an email template with sample data, in the locale of
+// ?locale= or else of the request. ?format=html or ?format=text responds
+// with that body alone, to be viewed as is; otherwise the subject and both
+// bodies are returned as JSON. Only admins can preview emails.
+func PreviewEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !domain.HasRole(c.GetStringSlice("auth:bearer:roles"), domain.RoleAdmin) {
+			DefaultError(c, errs.ErrAdminOnly)
+			return
+		}
+
+		msg, ok := mailer.Sample(c.Param("template"))
+		if !ok {
+			DefaultError(c, errs.ErrNotFound)
+			return
+		}
+
+		locale := c.DefaultQuery("locale", problem.Locale(c))
+		if !i18n.IsSupported(locale) {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+
+		email, err := mailer.Render(locale, msg)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.Header("Content-Language", i18n.Tag(locale))
+		switch c.DefaultQuery("format", "json") {
+		case "html":
+			c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(email.HTML))
+		case "text":
+			c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(email.Text))
+		case "json":
+			c.JSON(http.StatusOK, email)
+		default:
+			DefaultError(c, errs.ErrInvalidBody)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+// setETag exposes the version of the returned plant or care as its entity
+// tag, to be sent back in If-Match when changing it.
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatch reads the version the client last saw from the If-Match header,
+// which is required. "*" matches any version and yields 0.
+func ifMatch(c *gin.Context) (int, bool) {
+	tag := strings.TrimPrefix(strings.TrimSpace(c.GetHeader("If-Match")), "W/")
+	if tag == "" {
+		DefaultError(c, errs.ErrPreconditionRequired)
+		return 0, false
+	}
+
+	if tag == "*" {
+		return 0, true
+	}
+
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		unquoted = tag
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version < 1 {
+		DefaultError(c, errs.ErrPreconditionFailed)
+		return 0, false
+	}
+
+	return version, true
+}
+
+// checkVersion fails with 412 Precondition Failed when the client expected
+// another version than the current one.
+func checkVersion(c *gin.Context, expected, current int) bool {
+	if expected != 0 && expected != current {
+		DefaultError(c, errs.ErrPreconditionFailed)
+		return false
+	}
+	return true
+}
+
+// versionedWriteError responds to a failed write of a request that sent
+// If-Match.
+func versionedWriteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errs.ErrVersionConflict):
+		DefaultError(c, errs.ErrPreconditionFailed)
+	case errors.Is(err, errs.ErrNoRowsAffected), errors.Is(err, errs.ErrSelectNotMatch):
+		DefaultError(c, errs.ErrNotFound)
+	default:
+		DefaultError(c, err)
+	}
+}
+
+// writeError responds to a failed write of a request that did not send
+// If-Match, where a version conflict means a concurrent change and is
+// reported as such with a 409.
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errs.ErrVersionConflict):
+		DefaultError(c, errs.ErrVersionConflict)
+	case errors.Is(err, errs.ErrNoRowsAffected), errors.Is(err, errs.ErrSelectNotMatch):
+		DefaultError(c, errs.ErrNotFound)
+	default:
+		DefaultError(c, err)
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		purpose     string
+		header      string
+		wantVersion int
+		wantOk      bool
+		wantStatus  int
+	}{
+		{"should read a quoted version", `"3"`, 3, true, http.StatusOK},
+		{"should read a weak tag", `W/"3"`, 3, true, http.StatusOK},
+		{"should read an unquoted version", "3", 3, true, http.StatusOK},
+		{"should match any version with a wildcard", "*", 0, true, http.StatusOK},
+		{"should require the header", "", 0, false, http.StatusPreconditionRequired},
+		{"should reject a tag that is not a version", `"abc"`, 0, false, http.StatusPreconditionFailed},
+		{"should reject a version below 1", `"0"`, 0, false, http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/plants/1", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			version, ok := ifMatch(c)
+
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantVersion, version)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, !tt.wantOk, c.IsAborted())
+		})
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		purpose    string
+		expected   int
+		current    int
+		wantOk     bool
+		wantStatus int
+	}{
+		{"should accept the current version", 3, 3, true, http.StatusOK},
+		{"should accept any version for a wildcard", 0, 3, true, http.StatusOK},
+		{"should reject a stale version", 2, 3, false, http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/plants/1", nil)
+
+			assert.Equal(t, tt.wantOk, checkVersion(c, tt.expected, tt.current))
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		purpose             string
+		err                 error
+		wantStatus          int
+		wantVersionedStatus int
+	}{
+		{"should report a version conflict", fmt.Errorf("saving plant: %w", errs.ErrVersionConflict), http.StatusConflict, http.StatusPreconditionFailed},
+		{"should report a missing row as not found", errs.ErrNoRowsAffected, http.StatusNotFound, http.StatusNotFound},
+		{"should report a missing select as not found", errs.ErrSelectNotMatch, http.StatusNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPatch, "/api/v1/plants/1", nil)
+			writeError(c, tt.err)
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/plants/1", nil)
+			versionedWriteError(c, tt.err)
+			assert.Equal(t, tt.wantVersionedStatus, w.Code)
+		})
+	}
+}
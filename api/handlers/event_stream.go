@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/live"
+)
+
+// streamHeartbeat keeps idle connections from being closed by proxies.
+var streamHeartbeat = 25 * time.Second
+
+// StreamEvents pushes the plant and care events of the user as Server-Sent
+// Events. A client that reconnects with the Last-Event-ID header, or the
+// lastEventId query parameter, first gets the events it missed that are still
+// in the replay buffer.
+func StreamEvents(uStorer domain.UserStorer, hub *live.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		// Subscribe before replaying so that nothing published in between
+		// is missed; messages seen in both are skipped below.
+		messages, unsubscribe := hub.Subscribe(user.Id)
+		defer unsubscribe()
+
+		lastId := c.GetHeader("Last-Event-ID")
+		if lastId == "" {
+			lastId = c.Query("lastEventId")
+		}
+
+		var replay []live.Message
+		if lastId != "" {
+			var err error
+			replay, err = hub.Replay(c.Request.Context(), user.Id, lastId)
+			if err != nil {
+				DefaultError(c, err)
+				return
+			}
+		}
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+
+		for _, message := range replay {
+			writeStreamMessage(c.Writer, message)
+			lastId = message.Id
+		}
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				if !live.After(message.Id, lastId) {
+					continue
+				}
+				writeStreamMessage(c.Writer, message)
+				lastId = message.Id
+			case <-heartbeat.C:
+				io.WriteString(c.Writer, ": ping\n\n")
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeStreamMessage writes one event. The data is compact JSON, so it
+// always fits on a single data line.
+func writeStreamMessage(w io.Writer, message live.Message) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", message.Id, message.Type, message.Data)
+}
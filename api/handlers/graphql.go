@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/internal/gql"
+)
+
+// GraphQL runs a GraphQL query on behalf of the authenticated user. Queries
+// are sent as a JSON body on POST, or as the query, operationName and
+// variables parameters on GET. Errors in the query itself are reported in the
+// "errors" field of a 200 response, as GraphQL clients expect.
+func GraphQL(uStorer domain.UserStorer, service *gql.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		var req gql.Request
+		if c.Request.Method == http.MethodGet {
+			if err := c.ShouldBindQuery(&req); err != nil {
+				DefaultError(c, errs.ErrInvalidBody)
+				return
+			}
+			if variables := c.Query("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+					DefaultError(c, errs.ErrInvalidBody)
+					return
+				}
+			}
+		} else if err := c.ShouldBindJSON(&req); err != nil {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+
+		if req.Query == "" {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+
+		subject := authz.Subject{UserId: user.Id, Roles: c.GetStringSlice("auth:bearer:roles")}
+
+		c.JSON(http.StatusOK, service.Do(c.Request.Context(), subject, req))
+	}
+}
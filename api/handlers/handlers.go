@@ -1,7 +1,38 @@
 package handlers
 
-import "github.com/gin-gonic/gin"
+import (
+	"errors"
 
-func DefaultError(c *gin.Context, status int, err error) {
-	c.JSON(status, gin.H{"error": err.Error()})
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/api/problem"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+// DefaultError responds with the problem describing err. Its status follows
+// from the kind of err; errors that are not an errs.Error are internal.
+func DefaultError(c *gin.Context, err error) {
+	problem.Write(c, err)
+}
+
+// currentUser loads the authenticated user. Depending on how the token was
+// issued its subject is either the user id or the external id. The user is
+// kept on the request, so later calls do not load it again.
+func currentUser(c *gin.Context, storer domain.UserStorer) (*domain.User, bool) {
+	if user, ok := c.Get("auth:bearer:user"); ok {
+		return user.(*domain.User), true
+	}
+
+	user, err := domain.GetUserBySubject(c.Request.Context(), storer, c.GetString("auth:bearer:id"))
+	if err != nil {
+		if errors.Is(err, errs.ErrSelectNotMatch) {
+			DefaultError(c, errs.ErrNotFound)
+			return nil, false
+		}
+		DefaultError(c, err)
+		return nil, false
+	}
+
+	c.Set("auth:bearer:user", user)
+	return user, true
 }
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mailer"
+)
+
+// mailboxPage lists the emails of the outbox with their plain-text bodies,
+// so that codes can be read at a glance.
+var mailboxPage = template.Must(template.New("mailbox").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Mailbox</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; margin: 24px; color: #23301f; }
+article { border: 1px solid #e3eadf; border-radius: 6px; padding: 12px 16px; margin-bottom: 16px; }
+h2 { font-size: 16px; margin: 0 0 4px; }
+small { color: #7a8776; }
+pre { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>Mailbox</h1>
+{{- range .}}
+<article>
+<h2><a href="/dev/mailbox/{{.Id}}">{{.Subject}}</a></h2>
+<small>{{.To}} &middot; {{.SentAt.Format "2006-01-02 15:04:05"}}</small>
+<pre>{{.Text}}</pre>
+</article>
+{{- else}}
+<p>No emails yet.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+// Mailbox lists the emails kept by the outbox mailer, newest first. It is
+// only served in development.
+func Mailbox(outbox *mailer.Outbox) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := mailboxPage.Execute(c.Writer, outbox.Emails()); err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+// MailboxEmail shows the HTML body of an email kept by the outbox mailer.
+func MailboxEmail(outbox *mailer.Outbox) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		email, ok := outbox.Email(c.Param("id"))
+		if !ok {
+			DefaultError(c, errs.ErrNotFound)
+			return
+		}
+
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(email.HTML))
+	}
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/markdown"
+)
+
+// GetPlantNotes returns the current notes of the plant, as Markdown and as
+// sanitized HTML. A plant without notes has an empty revision 0.
+func GetPlantNotes(uStorer domain.UserStorer, pStorer domain.PlantStorer, nStorer domain.NoteStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		plant, ok := getPlant(c, uStorer, pStorer, policy)
+		if !ok {
+			return
+		}
+
+		current, ok := getLatestNoteRevision(c, nStorer, plant.Id)
+		if !ok {
+			return
+		}
+
+		if current == nil {
+			current = &domain.NoteRevision{PlantId: plant.Id}
+		}
+
+		renderNoteRevision(c, http.StatusOK, current)
+	}
+}
+
+// SavePlantNotes stores a new revision of the plant notes. When
+// "baseRevision" is sent and is no longer the current revision the request
+// fails with a conflict, so concurrent edits are not lost.
+func SavePlantNotes(uStorer domain.UserStorer, pStorer domain.PlantStorer, nStorer domain.NoteStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := struct {
+			Body         string `json:"body"`
+			BaseRevision *int   `json:"baseRevision"`
+		}{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		plant, ok := getPlant(c, uStorer, pStorer, policy)
+		if !ok {
+			return
+		}
+
+		current, ok := getLatestNoteRevision(c, nStorer, plant.Id)
+		if !ok {
+			return
+		}
+
+		if req.BaseRevision != nil && *req.BaseRevision != noteRevisionNumber(current) {
+			DefaultError(c, errs.ErrNoteConflict)
+			return
+		}
+
+		revision, err := domain.NewNoteRevision(plant.Id, user.Id, req.Body, current)
+		if err != nil {
+			if errors.Is(err, errs.ErrNoteUnchanged) {
+				renderNoteRevision(c, http.StatusOK, current)
+				return
+			}
+			DefaultError(c, err)
+			return
+		}
+
+		createNoteRevision(c, nStorer, revision)
+	}
+}
+
+// GetNoteRevisions lists every revision of the plant notes, newest first.
+func GetNoteRevisions(uStorer domain.UserStorer, pStorer domain.PlantStorer, nStorer domain.NoteStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		plant, ok := getPlant(c, uStorer, pStorer, policy)
+		if !ok {
+			return
+		}
+
+		revisions, err := nStorer.GetNoteRevisions(c.Request.Context(), plant.Id)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, revisions)
+	}
+}
+
+func GetNoteRevision(uStorer domain.UserStorer, pStorer domain.PlantStorer, nStorer domain.NoteStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		plant, ok := getPlant(c, uStorer, pStorer, policy)
+		if !ok {
+			return
+		}
+
+		revision, ok := getNoteRevision(c, nStorer, plant.Id, c.Param("revision"))
+		if !ok {
+			return
+		}
+
+		renderNoteRevision(c, http.StatusOK, revision)
+	}
+}
+
+// DiffNoteRevisions returns a unified diff between ?from= and ?to=, which
+// defaults to the current revision.
+func DiffNoteRevisions(uStorer domain.UserStorer, pStorer domain.PlantStorer, nStorer domain.NoteStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		plant, ok := getPlant(c, uStorer, pStorer, policy)
+		if !ok {
+			return
+		}
+
+		from, ok := getNoteRevision(c, nStorer, plant.Id, c.Query("from"))
+		if !ok {
+			return
+		}
+
+		var to *domain.NoteRevision
+		if v := c.Query("to"); v != "" {
+			to, ok = getNoteRevision(c, nStorer, plant.Id, v)
+		} else {
+			to, ok = getLatestNoteRevision(c, nStorer, plant.Id)
+		}
+		if !ok {
+			return
+		}
+
+		diff, err := from.Diff(to)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"from": from.Revision, "to": to.Revision, "diff": diff})
+	}
+}
+
+// RestoreNoteRevision makes an old revision current again by saving its body
+// as a new revision, so the history is kept.
+func RestoreNoteRevision(uStorer domain.UserStorer, pStorer domain.PlantStorer, nStorer domain.NoteStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		plant, ok := getPlant(c, uStorer, pStorer, policy)
+		if !ok {
+			return
+		}
+
+		old, ok := getNoteRevision(c, nStorer, plant.Id, c.Param("revision"))
+		if !ok {
+			return
+		}
+
+		current, ok := getLatestNoteRevision(c, nStorer, plant.Id)
+		if !ok {
+			return
+		}
+
+		revision, err := old.Restore(user.Id, current)
+		if err != nil {
+			if errors.Is(err, errs.ErrNoteUnchanged) {
+				renderNoteRevision(c, http.StatusOK, current)
+				return
+			}
+			DefaultError(c, err)
+			return
+		}
+
+		createNoteRevision(c, nStorer, revision)
+	}
+}
+
+func createNoteRevision(c *gin.Context, storer domain.NoteStorer, revision *domain.NoteRevision) {
+	if _, err := storer.CreateNoteRevision(c.Request.Context(), revision); err != nil {
+		DefaultError(c, err)
+		return
+	}
+
+	renderNoteRevision(c, http.StatusCreated, revision)
+}
+
+func renderNoteRevision(c *gin.Context, status int, revision *domain.NoteRevision) {
+	html, err := markdown.Render(revision.Body)
+	if err != nil {
+		DefaultError(c, err)
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"plantId":      revision.PlantId,
+		"revision":     revision.Revision,
+		"body":         revision.Body,
+		"html":         html,
+		"restoredFrom": revision.RestoredFrom,
+		"createdAt":    revision.CreatedAt,
+	})
+}
+
+func noteRevisionNumber(revision *domain.NoteRevision) int {
+	if revision == nil {
+		return 0
+	}
+	return revision.Revision
+}
+
+// getLatestNoteRevision returns a nil revision when the plant has no notes.
+func getLatestNoteRevision(c *gin.Context, storer domain.NoteStorer, plantId int64) (*domain.NoteRevision, bool) {
+	revision, err := storer.GetLatestNoteRevision(c.Request.Context(), plantId)
+	if err != nil {
+		if errors.Is(err, errs.ErrSelectNotMatch) {
+			return nil, true
+		}
+		DefaultError(c, err)
+		return nil, false
+	}
+
+	return revision, true
+}
+
+func getNoteRevision(c *gin.Context, storer domain.NoteStorer, plantId int64, number string) (*domain.NoteRevision, bool) {
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		DefaultError(c, errs.ErrInvalidBody)
+		return nil, false
+	}
+
+	revision, err := storer.GetNoteRevision(c.Request.Context(), plantId, n)
+	if err != nil {
+		if errors.Is(err, errs.ErrSelectNotMatch) {
+			DefaultError(c, errs.ErrNotFound)
+			return nil, false
+		}
+		DefaultError(c, err)
+		return nil, false
+	}
+
+	return revision, true
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mergepatch"
+)
+
+// readMergePatch reads the request body as a JSON Merge Patch. Plain JSON is
+// accepted too, since a JSON object is also a valid merge patch.
+func readMergePatch(c *gin.Context) (mergepatch.Document, bool) {
+	switch c.ContentType() {
+	case "", mergepatch.ContentType, gin.MIMEJSON:
+	default:
+		DefaultError(c, errs.ErrUnsupportedMediaType)
+		return nil, false
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		DefaultError(c, errs.ErrInvalidBody)
+		return nil, false
+	}
+
+	patch, err := mergepatch.Parse(body)
+	if err != nil {
+		DefaultError(c, errs.ErrInvalidBody)
+		return nil, false
+	}
+	return patch, true
+}
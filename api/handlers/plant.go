@@ -4,179 +4,250 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
 	"github.com/mathehluiz/plant-care-tracker/internal/errs"
 	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
 )
 
-func CreatePlant(storer domain.PlantStorer) gin.HandlerFunc {
+func CreatePlant(uStorer domain.UserStorer, pStorer domain.PlantStorer) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		req := struct {
 			Name            string    `json:"name"`
+			Species         string    `json:"species"`
 			Location        string    `json:"location"`
 			AcquisitionDate time.Time `json:"acquisitionDate"`
 			CareFrequency   int       `json:"careFrequency"`
 		}{}
-		userId := c.GetString("auth:bearer:id")
-		parsedUserId, err := strconv.ParseInt(userId, 10, 64)
-		if err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		user, ok := currentUser(c, uStorer)
+		if !ok {
 			return
 		}
 		if err := c.ShouldBindJSON(&req); err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
-		validations := validate.Struct(req)
-		if len(validations) > 0 {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		if err := validate.Struct(req); err != nil {
+			DefaultError(c, err)
 			return
 		}
 
-		plant, err := domain.NewPlant(req.Name, req.Location, req.AcquisitionDate, req.CareFrequency, parsedUserId)
+		plant, err := domain.NewPlant(req.Name, req.Species, req.Location, req.AcquisitionDate, req.CareFrequency, user.Id)
 		if err != nil {
-			DefaultError(c, http.StatusBadRequest, err)
+			DefaultError(c, err)
 			return
 		}
 
-		id, err := storer.CreatePlant(c.Request.Context(), plant)
+		id, err := pStorer.CreatePlant(c.Request.Context(), plant)
 		if err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
+		setETag(c, plant.Version)
 		c.JSON(http.StatusCreated, gin.H{"id": id})
 	}
 }
 
-func GetPlantByID(storer domain.PlantStorer) gin.HandlerFunc {
+func GetPlantByID(uStorer domain.UserStorer, pStorer domain.PlantStorer, policy *authz.Policy) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.Param("id")
-		if id == "" {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
-			return
-		}
-		plantId, err := strconv.ParseInt(id, 10, 64)
-		if err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
-			return
-		}
-
-		plant, err := storer.GetPlantByID(c.Request.Context(), plantId)
-		if err != nil {
-			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
-				return
-			}
-			DefaultError(c, http.StatusInternalServerError, err)
+		plant, ok := getPlant(c, uStorer, pStorer, policy)
+		if !ok {
 			return
 		}
 
+		setETag(c, plant.Version)
 		c.JSON(http.StatusOK, plant)
 	}
 }
 
-func GetPlantsByUserID(storer domain.PlantStorer) gin.HandlerFunc {
+func GetPlantsByUserID(uStorer domain.UserStorer, pStorer domain.PlantStorer) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userIdStr := c.GetString("auth:bearer:id")
-		userId, err := strconv.ParseInt(userIdStr, 10, 64)
-		if err != nil {
-			DefaultError(c, http.StatusBadRequest, err)
+		user, ok := currentUser(c, uStorer)
+		if !ok {
 			return
 		}
 
-		plants, err := storer.GetPlantsByUserID(c.Request.Context(), userId)
+		statuses := []string{domain.PlantStatusActive}
+		if status := c.Query("status"); status != "" {
+			statuses = nil
+			if status != "all" {
+				statuses = strings.Split(status, ",")
+			}
+		}
+		for _, status := range statuses {
+			if !domain.IsValidPlantStatus(status) {
+				DefaultError(c, errs.ErrInvalidPlantStatus)
+				return
+			}
+		}
+
+		plants, err := pStorer.GetPlantsByUserID(c.Request.Context(), user.Id, statuses...)
 		if err != nil {
 			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
+				DefaultError(c, errs.ErrNotFound)
 				return
 			}
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
+			return
 		}
 
 		c.JSON(http.StatusOK, plants)
 	}
 }
 
-func UpdatePlant(storer domain.PlantStorer) gin.HandlerFunc {
+func UpdatePlant(uStorer domain.UserStorer, pStorer domain.PlantStorer, policy *authz.Policy) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.Param("id")
-		if id == "" {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		patch, ok := readMergePatch(c)
+		if !ok {
 			return
 		}
-		parsedID, err := strconv.ParseInt(id, 10, 64)
-		if err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+
+		version, ok := ifMatch(c)
+		if !ok {
 			return
 		}
+
+		plant, ok := getPlant(c, uStorer, pStorer, policy)
+		if !ok {
+			return
+		}
+
+		if !checkVersion(c, version, plant.Version) {
+			return
+		}
+
+		if err := plant.Patch(patch); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		if err := pStorer.UpdatePlant(c.Request.Context(), plant); err != nil {
+			versionedWriteError(c, err)
+			return
+		}
+
+		setETag(c, plant.Version)
+		c.JSON(http.StatusOK, gin.H{"message": "Plant updated successfully"})
+	}
+}
+
+// ChangePlantStatus moves a plant along its lifecycle, e.g. archiving it or
+// recording that it died. The care history is kept untouched.
+func ChangePlantStatus(uStorer domain.UserStorer, pStorer domain.PlantStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
 		req := struct {
-			Name            string    `json:"name"`
-			Location        string    `json:"location"`
-			AcquisitionDate time.Time `json:"acquisitionDate"`
-			CareFrequency   int       `json:"careFrequency"`
+			Status string     `json:"status" validate:"required"`
+			Reason string     `json:"reason"`
+			Date   *time.Time `json:"date"`
 		}{}
 
 		if err := c.ShouldBindJSON(&req); err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
-		validations := validate.Struct(req)
-		if len(validations) > 0 {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		if err := validate.Struct(req); err != nil {
+			DefaultError(c, err)
 			return
 		}
 
-		plant, err := storer.GetPlantByID(c.Request.Context(), parsedID)
-		if err != nil {
-			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
-				return
-			}
-			DefaultError(c, http.StatusInternalServerError, err)
+		plant, ok := getPlant(c, uStorer, pStorer, policy)
+		if !ok {
+			return
 		}
 
-		err = plant.Update(req.Name, req.Location, req.AcquisitionDate, req.CareFrequency)
-		if err != nil {
-			DefaultError(c, http.StatusBadRequest, err)
+		at := time.Now()
+		if req.Date != nil {
+			at = *req.Date
+		}
+
+		if err := plant.ChangeStatus(req.Status, req.Reason, at); err != nil {
+			DefaultError(c, err)
 			return
 		}
 
-		err = storer.UpdatePlant(c.Request.Context(), plant)
-		if err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+		if err := pStorer.UpdatePlant(c.Request.Context(), plant); err != nil {
+			writeError(c, err)
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"message": "Plant updated successfully"})
+
+		setETag(c, plant.Version)
+		c.JSON(http.StatusOK, plant)
 	}
 }
 
-func DeletePlant(storer domain.PlantStorer) gin.HandlerFunc {
+// GetPlantGraveyard reports survival rates of the user plants grouped by
+// species or location.
+func GetPlantGraveyard(uStorer domain.UserStorer, pStorer domain.PlantStorer) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.Param("id")
-		if id == "" {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		user, ok := currentUser(c, uStorer)
+		if !ok {
 			return
 		}
-		parsedID, err := strconv.ParseInt(id, 10, 64)
+
+		groupBy := c.DefaultQuery("groupBy", "species")
+
+		stats, err := pStorer.GetPlantSurvival(c.Request.Context(), user.Id, groupBy)
 		if err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, err)
 			return
 		}
 
-		err = storer.DeletePlant(c.Request.Context(), parsedID)
-		if err != nil {
-			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
-				return
-			}
-			DefaultError(c, http.StatusInternalServerError, err)
+		c.JSON(http.StatusOK, gin.H{"groupBy": groupBy, "groups": stats})
+	}
+}
+
+func DeletePlant(uStorer domain.UserStorer, pStorer domain.PlantStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version, ok := ifMatch(c)
+		if !ok {
+			return
+		}
+
+		plant, ok := getPlant(c, uStorer, pStorer, policy)
+		if !ok {
+			return
+		}
+
+		if err := pStorer.DeletePlant(c.Request.Context(), plant.Id, version); err != nil {
+			versionedWriteError(c, err)
 			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{"message": "Plant deleted successfully"})
 	}
-}
\ No newline at end of file
+}
+
+// getPlant loads the plant in the id path parameter, responding 404 when it
+// does not exist or the user may not access it.
+func getPlant(c *gin.Context, uStorer domain.UserStorer, pStorer domain.PlantStorer, policy *authz.Policy) (*domain.Plant, bool) {
+	plantId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		DefaultError(c, errs.ErrInvalidBody)
+		return nil, false
+	}
+
+	return getPlantByID(c, uStorer, pStorer, policy, plantId)
+}
+
+func getPlantByID(c *gin.Context, uStorer domain.UserStorer, pStorer domain.PlantStorer, policy *authz.Policy, plantId int64) (*domain.Plant, bool) {
+	plant, err := pStorer.GetPlantByID(c.Request.Context(), plantId)
+	if err != nil {
+		if errors.Is(err, errs.ErrSelectNotMatch) {
+			DefaultError(c, errs.ErrNotFound)
+			return nil, false
+		}
+		DefaultError(c, err)
+		return nil, false
+	}
+
+	if !authorize(c, uStorer, policy, domain.AuditEntityPlant, plant.Id, plant.UserId) {
+		return nil, false
+	}
+
+	return plant, true
+}
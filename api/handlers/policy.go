@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+// authorize checks that the authenticated user may access the plant or care
+// owned by ownerId. Entities the user may not access get the same 404 as
+// missing ones.
+func authorize(c *gin.Context, uStorer domain.UserStorer, policy *authz.Policy, entityType string, entityId, ownerId int64) bool {
+	user, ok := currentUser(c, uStorer)
+	if !ok {
+		return false
+	}
+
+	subject := authz.Subject{UserId: user.Id, Roles: c.GetStringSlice("auth:bearer:roles")}
+
+	if err := policy.Authorize(c.Request.Context(), subject, entityType, entityId, ownerId); err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			DefaultError(c, errs.ErrNotFound)
+			return false
+		}
+		DefaultError(c, err)
+		return false
+	}
+
+	return true
+}
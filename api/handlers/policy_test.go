@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+type policyUserStorer struct {
+	domain.UserStorer
+	user *domain.User
+}
+
+func (s *policyUserStorer) GetUserByID(ctx context.Context, id int64) (*domain.User, error) {
+	if id != s.user.Id {
+		return nil, errs.ErrSelectNotMatch
+	}
+	return s.user, nil
+}
+
+func (s *policyUserStorer) GetUserByExternalId(ctx context.Context, id string) (*domain.User, error) {
+	if id != s.user.ExternalId {
+		return nil, errs.ErrSelectNotMatch
+	}
+	return s.user, nil
+}
+
+func TestAuthorize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	uStorer := &policyUserStorer{user: &domain.User{Id: 7, ExternalId: "6f1c2b9e-7d3a-4c55-9a41-2f0e8d1b3c7a"}}
+	policy := authz.NewPolicy(nil)
+
+	cases := []struct {
+		purpose    string
+		subject    string
+		ownerId    int64
+		wantOk     bool
+		wantStatus int
+	}{
+		{"should let the owner in with a login token", "7", 7, true, http.StatusOK},
+		{"should let the owner in with a token carrying the external id", "6f1c2b9e-7d3a-4c55-9a41-2f0e8d1b3c7a", 7, true, http.StatusOK},
+		{"should hide the entities of other users", "6f1c2b9e-7d3a-4c55-9a41-2f0e8d1b3c7a", 8, false, http.StatusNotFound},
+		{"should reject a user that no longer exists", "9", 7, false, http.StatusNotFound},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/plants/1", nil)
+			c.Set("auth:bearer:id", tt.subject)
+
+			assert.Equal(t, tt.wantOk, authorize(c, uStorer, policy, domain.AuditEntityPlant, 1, tt.ownerId))
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+// maxOccurrenceRange bounds how far GetCareOccurrences expands a rule.
+const maxOccurrenceRange = 366 * 24 * time.Hour
+
+// careRecurrence builds the recurrence of a care request. An empty rule means
+// the care does not use one.
+func careRecurrence(rule, timezone string, exdates []time.Time) (*domain.Recurrence, error) {
+	if rule == "" {
+		return nil, nil
+	}
+
+	return domain.NewRecurrence(rule, timezone, exdates)
+}
+
+// GetCareOccurrences expands the care schedule between ?from= and ?to=, which
+// accept RFC 3339 timestamps or YYYY-MM-DD dates. The range defaults to the
+// next 90 days and can span at most a year.
+func GetCareOccurrences(uStorer domain.UserStorer, cStorer domain.CareStorer, pStorer domain.PlantStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		care, ok := getCare(c, uStorer, cStorer, policy)
+		if !ok {
+			return
+		}
+
+		from := time.Now().UTC()
+		if v := c.Query("from"); v != "" {
+			t, err := parseRangeTime(v)
+			if err != nil {
+				DefaultError(c, errs.ErrInvalidOccurrenceRange)
+				return
+			}
+			from = t
+		}
+
+		to := from.AddDate(0, 0, 90)
+		if v := c.Query("to"); v != "" {
+			t, err := parseRangeTime(v)
+			if err != nil {
+				DefaultError(c, errs.ErrInvalidOccurrenceRange)
+				return
+			}
+			to = t
+		}
+
+		if !to.After(from) || to.Sub(from) > maxOccurrenceRange {
+			DefaultError(c, errs.ErrInvalidOccurrenceRange)
+			return
+		}
+
+		plant, ok := getCarePlant(c, pStorer, care)
+		if !ok {
+			return
+		}
+
+		occurrences := care.Occurrences(from, to, plant.CareFrequency)
+		if occurrences == nil {
+			occurrences = []time.Time{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"from":        from,
+			"to":          to,
+			"occurrences": occurrences,
+		})
+	}
+}
+
+func parseRangeTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
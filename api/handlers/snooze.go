@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+// SkipCare gives up the current occurrence of the care with an optional
+// reason and schedules the next one. Skips do not count against adherence.
+func SkipCare(uStorer domain.UserStorer, cStorer domain.CareStorer, pStorer domain.PlantStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := struct {
+			Reason string `json:"reason"`
+		}{}
+		// The reason is optional, so an empty body is accepted.
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+
+		care, ok := getCare(c, uStorer, cStorer, policy)
+		if !ok {
+			return
+		}
+
+		plant, ok := getCarePlant(c, pStorer, care)
+		if !ok {
+			return
+		}
+
+		entry, err := care.Skip(time.Now().UTC(), req.Reason, plant.CareFrequency)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		if err := cStorer.UpdateCare(c.Request.Context(), care, entry); err != nil {
+			writeError(c, err)
+			return
+		}
+
+		setETag(c, care.Version)
+		c.JSON(http.StatusOK, care)
+	}
+}
+
+// SnoozeCare pushes the current occurrence of the care back, either to
+// "until" or by a number of "days", without changing the rest of its
+// schedule.
+func SnoozeCare(uStorer domain.UserStorer, cStorer domain.CareStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := struct {
+			Until  *time.Time `json:"until"`
+			Days   int        `json:"days"`
+			Reason string     `json:"reason"`
+		}{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+
+		now := time.Now().UTC()
+
+		var until time.Time
+		switch {
+		case req.Until != nil && req.Days == 0:
+			until = *req.Until
+		case req.Until == nil && req.Days > 0:
+			until = now.AddDate(0, 0, req.Days)
+		default:
+			DefaultError(c, errs.ErrInvalidSnooze)
+			return
+		}
+
+		care, ok := getCare(c, uStorer, cStorer, policy)
+		if !ok {
+			return
+		}
+
+		entry, err := care.Snooze(until, now, req.Reason)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		if err := cStorer.UpdateCare(c.Request.Context(), care, entry); err != nil {
+			writeError(c, err)
+			return
+		}
+
+		setETag(c, care.Version)
+		c.JSON(http.StatusOK, care)
+	}
+}
+
+// GetCareHistory lists what happened to the care, most recent first.
+func GetCareHistory(uStorer domain.UserStorer, cStorer domain.CareStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		care, ok := getCare(c, uStorer, cStorer, policy)
+		if !ok {
+			return
+		}
+
+		history, err := cStorer.GetCareHistory(c.Request.Context(), care.Id)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, history)
+	}
+}
+
+// GetCareAdherence summarizes how the user kept up with their cares over the
+// last ?days= days, 30 by default.
+func GetCareAdherence(uStorer domain.UserStorer, pStorer domain.PlantStorer, cStorer domain.CareStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		days := 30
+		if v := c.Query("days"); v != "" {
+			d, err := strconv.Atoi(v)
+			if err != nil || d < 1 || d > 366 {
+				DefaultError(c, errs.ErrInvalidBody)
+				return
+			}
+			days = d
+		}
+
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		now := time.Now()
+		loc := user.Location()
+		from := domain.StartOfDay(now, loc).AddDate(0, 0, 1-days)
+
+		history, err := cStorer.GetCareHistoryByUserID(c.Request.Context(), user.Id, from, now)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		plants, err := pStorer.GetPlantsByUserID(c.Request.Context(), user.Id, domain.PlantStatusActive)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		cares, err := cStorer.GetCaresByUserID(c.Request.Context(), user.Id)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"from":      from,
+			"to":        now,
+			"adherence": domain.ComputeAdherence(history, plants, cares, from, now, loc),
+		})
+	}
+}
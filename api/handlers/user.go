@@ -2,14 +2,13 @@ package handlers
 
 import (
 	"errors"
-	"fmt"
-	"log"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/mathehluiz/plant-care-tracker/api/problem"
 	"github.com/mathehluiz/plant-care-tracker/domain"
 	"github.com/mathehluiz/plant-care-tracker/internal/cache"
 	"github.com/mathehluiz/plant-care-tracker/internal/errs"
@@ -28,7 +27,7 @@ func Login(storer domain.UserStorer, cacher cache.ConnectionStorer) gin.HandlerF
 		}{}
 
 		if err := c.ShouldBindJSON(&req); err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
 
@@ -39,11 +38,11 @@ func Login(storer domain.UserStorer, cacher cache.ConnectionStorer) gin.HandlerF
 			user, err = storer.GetUserByEmail(c, req.Email)
 			if err != nil {
 				if errors.Is(err, errs.ErrSelectNotMatch) {
-					DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
+					DefaultError(c, errs.ErrNotFound)
 					return
 				}
 
-				DefaultError(c, http.StatusInternalServerError, err)
+				DefaultError(c, err)
 				return
 			}
 		}
@@ -52,28 +51,28 @@ func Login(storer domain.UserStorer, cacher cache.ConnectionStorer) gin.HandlerF
 			user, err = storer.GetUserByUsername(c, req.Username)
 			if err != nil {
 				if errors.Is(err, errs.ErrSelectNotMatch) {
-					DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
+					DefaultError(c, errs.ErrNotFound)
 					return
 				}
 
-				DefaultError(c, http.StatusInternalServerError, err)
+				DefaultError(c, err)
 				return
 			}
 		}
 
 		if user == nil {
-			DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
+			DefaultError(c, errs.ErrNotFound)
 			return
 		}
 
 		if err := user.VerifyPassword(req.Password); err != nil {
-			DefaultError(c, http.StatusUnauthorized, errs.ErrInvalidPassword)
+			DefaultError(c, errs.ErrInvalidCredentials)
 			return
 		}
 
-		token, err := jwt.GenerateToken(user.Id, user.Roles, user.Verified)
+		token, err := jwt.GenerateToken(user.Id, user.Roles, user.Verified, user.Locale)
 		if err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
@@ -88,43 +87,42 @@ func VerifyCode(storer domain.UserStorer, cacher cache.ConnectionStorer) gin.Han
 		}{}
 
 		if err := c.ShouldBindJSON(&req); err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
 
-		validations := validate.Struct(req)
-		if len(validations) > 0 {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		if err := validate.Struct(req); err != nil {
+			DefaultError(c, err)
 			return
 		}
 
 		userId, err := cacher.Get(c, req.Code)
 		if err != nil {
 			if errors.Is(err, cache.ErrNil) {
-				DefaultError(c, http.StatusUnauthorized, errs.ErrInvalidCode)
+				DefaultError(c, errs.ErrInvalidCode)
 				return
 			}
 
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
 		user, err := storer.GetUserByExternalId(c, userId)
 		if err != nil {
 			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
+				DefaultError(c, errs.ErrNotFound)
 				return
 			}
 
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
 		_ = cacher.Delete(c, user.ExternalId)
 
-		token, err := jwt.GenerateToken(user.ExternalId, user.Roles, user.Verified)
+		token, err := jwt.GenerateToken(user.ExternalId, user.Roles, user.Verified, user.Locale)
 		if err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
@@ -134,21 +132,14 @@ func VerifyCode(storer domain.UserStorer, cacher cache.ConnectionStorer) gin.Han
 
 func RefreshToken(storer domain.UserStorer) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userId := c.GetString("auth:bearer:id")
-
-		user, err := storer.GetUserByExternalId(c, userId)
-		if err != nil {
-			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
-			}
-
-			DefaultError(c, http.StatusInternalServerError, err)
+		user, ok := currentUser(c, storer)
+		if !ok {
 			return
 		}
 
-		token, err := jwt.GenerateToken(user.ExternalId, user.Roles, user.Verified)
+		token, err := jwt.GenerateToken(user.ExternalId, user.Roles, user.Verified, user.Locale)
 		if err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
@@ -158,15 +149,45 @@ func RefreshToken(storer domain.UserStorer) gin.HandlerFunc {
 
 func GetMe(storer domain.UserStorer) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userId := c.GetString("auth:bearer:id")
+		user, ok := currentUser(c, storer)
+		if !ok {
+			return
+		}
 
-		user, err := storer.GetUserByExternalId(c, userId)
-		if err != nil {
-			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
-			}
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+func UpdatePreferences(storer domain.UserStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := struct {
+			Timezone   string             `json:"timezone" validate:"required"`
+			Locale     string             `json:"locale" validate:"required"`
+			QuietHours *domain.QuietHours `json:"quietHours"`
+		}{}
 
-			DefaultError(c, http.StatusInternalServerError, err)
+		if err := c.ShouldBindJSON(&req); err != nil {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+
+		if err := validate.Struct(req); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		user, ok := currentUser(c, storer)
+		if !ok {
+			return
+		}
+
+		if err := user.UpdatePreferences(req.Timezone, req.Locale, req.QuietHours); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		if err := storer.UpdatePreferences(c, user); err != nil {
+			DefaultError(c, err)
 			return
 		}
 
@@ -183,78 +204,72 @@ func RegisterUser(storer domain.UserStorer, cacher cache.ConnectionStorer) gin.H
 		}{}
 
 		if err := c.ShouldBindJSON(&req); err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
 
-		validations := validate.Struct(req)
-		if len(validations) > 0 {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		if err := validate.Struct(req); err != nil {
+			DefaultError(c, err)
 			return
 		}
 
 		user, err := domain.NewUser(req.Username, req.Email, req.Password, []string{""})
 		if err != nil {
-			DefaultError(c, http.StatusBadRequest, err)
+			DefaultError(c, err)
 			return
 		}
+		if locale := c.GetString(problem.LocaleKey); locale != "" {
+			user.Locale = locale
+		}
 
 		externalId, err := storer.CreateUser(c, user)
 		if err != nil {
 			if strings.Contains(err.Error(), "pq: duplicate key value violates unique constraint") {
 				if strings.Contains(err.Error(), "users_username_key") {
-					DefaultError(c, http.StatusBadRequest, errs.ErrUsernameAlreadyExists)
+					DefaultError(c, errs.ErrUsernameAlreadyExists)
 					return
 				}
 
-				DefaultError(c, http.StatusBadRequest, errs.ErrEmailAlreadyExists)
+				DefaultError(c, errs.ErrEmailAlreadyExists)
 				return
 			}
 
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
-		token, err := jwt.GenerateToken(externalId, user.Roles, false)
+		token, err := jwt.GenerateToken(externalId, user.Roles, false, user.Locale)
 		if err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
 		code := random.GenetareRandomCode()
 
-		if err := cacher.Set(c, time.Duration(15)*time.Minute, externalId, code); err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+		if err := cacher.Set(c, domain.VerificationCodeTTL, externalId, code); err != nil {
+			DefaultError(c, err)
 			return
 		}
-		fmt.Println(code)
-
-		go func() {
-			err := mailer.SendConfirmationEmail(req.Email, code)
-			if err != nil {
-				log.Println("error sending email", err)
-				return
-			}
-		}()
 
 		c.JSON(http.StatusCreated, gin.H{"userId": externalId, "token": token})
 	}
 }
 
-func VerifyEmail(storer domain.UserStorer, cacher cache.ConnectionStorer) gin.HandlerFunc {
+// VerifyEmail checks the code sent by email after registering. When the code
+// expired, a new one is sent through a user.verification_requested event.
+func VerifyEmail(storer domain.UserStorer, oStorer domain.OutboxStorer, cacher cache.ConnectionStorer) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		req := struct {
 			Code string `json:"code" validate:"required"`
 		}{}
 
 		if err := c.ShouldBindJSON(&req); err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
 
-		validations := validate.Struct(req)
-		if len(validations) > 0 {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		if err := validate.Struct(req); err != nil {
+			DefaultError(c, err)
 			return
 		}
 
@@ -262,23 +277,23 @@ func VerifyEmail(storer domain.UserStorer, cacher cache.ConnectionStorer) gin.Ha
 
 		verified := c.GetBool("auth:bearer:verified")
 		if verified {
-			DefaultError(c, http.StatusBadRequest, errs.ErrAlreadyVerified)
+			DefaultError(c, errs.ErrAlreadyVerified)
 			return
 		}
 
 		user, err := storer.GetUserByExternalId(c, userId)
 		if err != nil {
 			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
+				DefaultError(c, errs.ErrNotFound)
 				return
 			}
 
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
 		if user.Verified {
-			DefaultError(c, http.StatusBadRequest, errs.ErrAlreadyVerified)
+			DefaultError(c, errs.ErrAlreadyVerified)
 			return
 		}
 
@@ -287,41 +302,43 @@ func VerifyEmail(storer domain.UserStorer, cacher cache.ConnectionStorer) gin.Ha
 			if errors.Is(err, cache.ErrNil) {
 				code = random.GenetareRandomCode()
 
-				if err := cacher.Set(c, time.Duration(15)*time.Minute, user.ExternalId, code); err != nil {
-					DefaultError(c, http.StatusInternalServerError, err)
+				if err := cacher.Set(c, domain.VerificationCodeTTL, user.ExternalId, code); err != nil {
+					DefaultError(c, err)
 					return
 				}
 
-				go func() {
-					err := mailer.SendConfirmationEmail(user.Email, code)
-					if err != nil {
-						log.Println("error sending email", err)
-						return
-					}
-				}()
+				event, err := domain.NewUserEvent(domain.EventUserVerificationRequested, user)
+				if err != nil {
+					DefaultError(c, err)
+					return
+				}
 
-				DefaultError(c, http.StatusBadRequest, errs.ErrCodeExpired)
+				if err := oStorer.CreateOutboxEvents(c, event); err != nil {
+					DefaultError(c, err)
+					return
+				}
+
+				DefaultError(c, errs.ErrCodeExpired)
+				return
 			}
 
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
 		if code != req.Code {
-			DefaultError(c, http.StatusUnauthorized, errs.ErrInvalidCode)
+			DefaultError(c, errs.ErrInvalidCode)
 			return
 		}
 
 		if err := storer.VerifyUser(c, userId); err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
-		_ = cacher.Delete(c, userId)
-
-		token, err := jwt.GenerateToken(user.ExternalId, user.Roles, true)
+		token, err := jwt.GenerateToken(user.ExternalId, user.Roles, true, user.Locale)
 		if err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
@@ -336,7 +353,7 @@ func SetActive(storer domain.UserStorer) gin.HandlerFunc {
 		}{}
 
 		if err := c.ShouldBindJSON(&req); err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
 
@@ -344,11 +361,11 @@ func SetActive(storer domain.UserStorer) gin.HandlerFunc {
 
 		if err := storer.UpdateActiveUserStatus(c, userId, req.Active); err != nil {
 			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
+				DefaultError(c, errs.ErrNotFound)
 				return
 			}
 
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
@@ -361,17 +378,17 @@ func DeleteUser(storer domain.UserStorer) gin.HandlerFunc {
 		userId := c.Param("id")
 
 		if userId == "" {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
 
 		if err := storer.DeleteUser(c, userId); err != nil {
 			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
+				DefaultError(c, errs.ErrNotFound)
 				return
 			}
 
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
@@ -388,13 +405,12 @@ func ChangeRoles(storer domain.UserStorer) gin.HandlerFunc {
 		}{}
 
 		if err := c.ShouldBindJSON(&req); err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
 
-		validations := validate.Struct(req)
-		if len(validations) > 0 {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		if err := validate.Struct(req); err != nil {
+			DefaultError(c, err)
 			return
 		}
 
@@ -405,17 +421,17 @@ func ChangeRoles(storer domain.UserStorer) gin.HandlerFunc {
 		case "remove":
 			err = storer.RemoveRolesFromUser(c, req.Id, req.Roles)
 		default:
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
 
 		if err != nil {
 			if errors.Is(err, errs.ErrSelectNotMatch) {
-				DefaultError(c, http.StatusNotFound, errs.ErrNotFound)
+				DefaultError(c, errs.ErrNotFound)
 				return
 			}
 
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
@@ -423,20 +439,22 @@ func ChangeRoles(storer domain.UserStorer) gin.HandlerFunc {
 	}
 }
 
-func ResetPassword(storer domain.UserStorer, cacher cache.ConnectionStorer) gin.HandlerFunc {
+// passwordResetTTL is how long a password reset code can be used.
+const passwordResetTTL = 5 * time.Minute
+
+func ResetPassword(storer domain.UserStorer, cacher cache.ConnectionStorer, m mailer.Mailer) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		req := struct {
 			Email string `json:"email" validate:"required,email"`
 		}{}
 
 		if err := c.ShouldBindJSON(&req); err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
 
-		validations := validate.Struct(req)
-		if len(validations) > 0 {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+		if err := validate.Struct(req); err != nil {
+			DefaultError(c, err)
 			return
 		}
 
@@ -447,7 +465,7 @@ func ResetPassword(storer domain.UserStorer, cacher cache.ConnectionStorer) gin.
 				return
 			}
 
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
@@ -462,14 +480,14 @@ func ResetPassword(storer domain.UserStorer, cacher cache.ConnectionStorer) gin.
 			break
 		}
 
-		if err := cacher.Set(c, time.Duration(5)*time.Minute, code, user.ExternalId); err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+		if err := cacher.Set(c, passwordResetTTL, code, user.ExternalId); err != nil {
+			DefaultError(c, err)
 			return
 		}
 
-		err = mailer.SendPasswordResetEmail(user.Email, code)
+		err = mailer.Send(c, m, user.Email, user.Locale, mailer.PasswordReset{Username: user.Username, Code: code, ValidFor: passwordResetTTL})
 		if err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
@@ -484,35 +502,35 @@ func ChangePassword(storer domain.UserStorer, cacher cache.ConnectionStorer) gin
 		}{}
 
 		if err := c.ShouldBindJSON(&req); err != nil {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
 
 		code := c.Param("id")
 		if code == "" {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
 
 		userId, err := cacher.Get(c, code)
 		if err != nil {
 			if errors.Is(err, cache.ErrNil) {
-				DefaultError(c, http.StatusUnauthorized, errs.ErrInvalidCode)
+				DefaultError(c, errs.ErrInvalidCode)
 				return
 			}
 
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
 		var user domain.User
 		if err := user.HashPass(req.Password); err != nil {
-			DefaultError(c, http.StatusBadRequest, err)
+			DefaultError(c, err)
 			return
 		}
 
 		if err := storer.UpdatePassword(c, userId, user.Password); err != nil {
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
@@ -524,18 +542,18 @@ func CheckChangePasswordStatus(cacher cache.ConnectionStorer) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		code := c.Param("id")
 		if code == "" {
-			DefaultError(c, http.StatusBadRequest, errs.ErrInvalidBody)
+			DefaultError(c, errs.ErrInvalidBody)
 			return
 		}
 
 		userId, err := cacher.Get(c, code)
 		if err != nil {
 			if errors.Is(err, cache.ErrNil) {
-				DefaultError(c, http.StatusUnauthorized, errs.ErrInvalidCode)
+				DefaultError(c, errs.ErrInvalidCode)
 				return
 			}
 
-			DefaultError(c, http.StatusInternalServerError, err)
+			DefaultError(c, err)
 			return
 		}
 
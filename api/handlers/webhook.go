@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+)
+
+// CreateWebhook registers an endpoint for the given events. The signing
+// secret is only returned here.
+func CreateWebhook(uStorer domain.UserStorer, wStorer domain.WebhookStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := struct {
+			URL    string   `json:"url" validate:"required"`
+			Events []string `json:"events" validate:"required"`
+		}{}
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+		if err := validate.Struct(req); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		webhook, err := domain.NewWebhook(user.Id, req.URL, req.Events)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		id, err := wStorer.CreateWebhook(c.Request.Context(), webhook)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"id": id, "secret": webhook.Secret})
+	}
+}
+
+func GetWebhooks(uStorer domain.UserStorer, wStorer domain.WebhookStorer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c, uStorer)
+		if !ok {
+			return
+		}
+
+		webhooks, err := wStorer.GetWebhooksByUserID(c.Request.Context(), user.Id)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, webhooks)
+	}
+}
+
+func GetWebhookByID(uStorer domain.UserStorer, wStorer domain.WebhookStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhook, ok := getWebhook(c, uStorer, wStorer, policy)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, webhook)
+	}
+}
+
+// UpdateWebhook applies a merge patch to the url, events and active fields
+// of a webhook. Deliveries queued for a webhook that is deactivated are
+// dropped when they come due.
+func UpdateWebhook(uStorer domain.UserStorer, wStorer domain.WebhookStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		patch, ok := readMergePatch(c)
+		if !ok {
+			return
+		}
+
+		webhook, ok := getWebhook(c, uStorer, wStorer, policy)
+		if !ok {
+			return
+		}
+
+		if err := webhook.Patch(patch); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		if err := wStorer.UpdateWebhook(c.Request.Context(), webhook); err != nil {
+			writeError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, webhook)
+	}
+}
+
+func DeleteWebhook(uStorer domain.UserStorer, wStorer domain.WebhookStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhook, ok := getWebhook(c, uStorer, wStorer, policy)
+		if !ok {
+			return
+		}
+
+		if err := wStorer.DeleteWebhook(c.Request.Context(), webhook.Id); err != nil {
+			writeError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+	}
+}
+
+// GetWebhookDeliveries lists the deliveries of a webhook newest first, paged
+// with ?limit= and ?before= set to the nextBefore of the previous page.
+func GetWebhookDeliveries(uStorer domain.UserStorer, wStorer domain.WebhookStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhook, ok := getWebhook(c, uStorer, wStorer, policy)
+		if !ok {
+			return
+		}
+
+		var (
+			before int64
+			limit  = defaultDeliveryLimit
+			err    error
+		)
+		if v := c.Query("before"); v != "" {
+			if before, err = strconv.ParseInt(v, 10, 64); err != nil {
+				DefaultError(c, errs.ErrInvalidBody)
+				return
+			}
+		}
+		if v := c.Query("limit"); v != "" {
+			if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxDeliveryLimit {
+				DefaultError(c, errs.ErrInvalidBody)
+				return
+			}
+		}
+
+		deliveries, err := wStorer.GetWebhookDeliveries(c.Request.Context(), webhook.Id, before, limit)
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		var nextBefore int64
+		if len(deliveries) == limit {
+			nextBefore = deliveries[len(deliveries)-1].Id
+		}
+
+		c.JSON(http.StatusOK, gin.H{"deliveries": deliveries, "nextBefore": nextBefore})
+	}
+}
+
+// RedeliverWebhookDelivery queues a finished delivery again, with the same
+// event id and payload.
+func RedeliverWebhookDelivery(uStorer domain.UserStorer, wStorer domain.WebhookStorer, policy *authz.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhook, ok := getWebhook(c, uStorer, wStorer, policy)
+		if !ok {
+			return
+		}
+
+		deliveryId, err := strconv.ParseInt(c.Param("deliveryId"), 10, 64)
+		if err != nil {
+			DefaultError(c, errs.ErrInvalidBody)
+			return
+		}
+
+		delivery, err := wStorer.GetWebhookDeliveryByID(c.Request.Context(), deliveryId)
+		if err != nil {
+			if errors.Is(err, errs.ErrSelectNotMatch) {
+				DefaultError(c, errs.ErrNotFound)
+				return
+			}
+			DefaultError(c, err)
+			return
+		}
+
+		if delivery.WebhookId != webhook.Id {
+			DefaultError(c, errs.ErrNotFound)
+			return
+		}
+
+		redelivery, err := delivery.Redeliver(time.Now().UTC())
+		if err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		if err := wStorer.CreateWebhookDeliveries(c.Request.Context(), []*domain.WebhookDelivery{redelivery}); err != nil {
+			DefaultError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, redelivery)
+	}
+}
+
+// getWebhook loads the webhook in the id path parameter, responding 404 when
+// it does not exist or the user may not access it.
+func getWebhook(c *gin.Context, uStorer domain.UserStorer, wStorer domain.WebhookStorer, policy *authz.Policy) (*domain.Webhook, bool) {
+	webhookId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		DefaultError(c, errs.ErrInvalidBody)
+		return nil, false
+	}
+
+	webhook, err := wStorer.GetWebhookByID(c.Request.Context(), webhookId)
+	if err != nil {
+		if errors.Is(err, errs.ErrSelectNotMatch) {
+			DefaultError(c, errs.ErrNotFound)
+			return nil, false
+		}
+		DefaultError(c, err)
+		return nil, false
+	}
+
+	if !authorize(c, uStorer, policy, domain.AuditEntityWebhook, webhook.Id, webhook.UserId) {
+		return nil, false
+	}
+
+	return webhook, true
+}
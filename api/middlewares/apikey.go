@@ -1,8 +1,10 @@
 package middlewares
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"github.com/gin-gonic/gin"
-	"net/http"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
 )
 
 func ValidateAPIKey(keys []string) Middleware {
@@ -11,12 +13,13 @@ func ValidateAPIKey(keys []string) Middleware {
 
 		for _, k := range keys {
 			if key == k {
+				sum := sha256.Sum256([]byte(key))
+				c.Set("auth:type", "api-key")
+				c.Set("auth:api-key:id", hex.EncodeToString(sum[:4]))
 				return nil
 			}
 		}
 
-		return &result{
-			Status: http.StatusNotFound,
-		}
+		return &result{Error: errs.ErrNotFound}
 	}
 }
@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/audit"
+)
+
+// Audit attaches the request actor to the context, so that changes made
+// while handling the request are recorded with the client IP and user agent.
+// The actor stays anonymous until an authentication middleware identifies it.
+func Audit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := &domain.AuditActor{
+			Type:      domain.AuditActorAnonymous,
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}
+
+		c.Set(audit.ActorKey, actor)
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), actor))
+
+		c.Next()
+	}
+}
+
+func identifyActor(c *gin.Context) {
+	v, ok := c.Get(audit.ActorKey)
+	if !ok {
+		return
+	}
+	actor := v.(*domain.AuditActor)
+
+	switch c.GetString("auth:type") {
+	case "token":
+		// The token subject is either the numeric or the external id of the
+		// user; entries always carry the external id.
+		actor.Type = domain.AuditActorUser
+		if user, ok := c.Get("auth:bearer:user"); ok {
+			actor.Id = user.(*domain.User).ExternalId
+		}
+	case "api-key":
+		actor.Type = domain.AuditActorAPIKey
+		actor.Id = c.GetString("auth:api-key:id")
+	}
+}
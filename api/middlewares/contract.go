@@ -0,0 +1,92 @@
+package middlewares
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/api/problem"
+	"github.com/mathehluiz/plant-care-tracker/internal/contract"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+// ValidateContract rejects requests that do not match the OpenAPI document
+// with a 400 problem listing the invalid fields. In contract.ModeAll, responses are
+// also checked and replaced by a 500 when they do not match, so that drift
+// shows up during development. Routes missing from the document are left
+// alone; the server refuses to start with any.
+func ValidateContract(spec *contract.Contract, mode contract.Mode) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if mode == contract.ModeOff {
+			c.Next()
+			return
+		}
+
+		route, ok := spec.Find(c.Request.Method, c.FullPath())
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if err := spec.ValidateRequest(c.Request, route, c.Params); err != nil {
+			problem.Write(c, err)
+			return
+		}
+
+		if mode != contract.ModeAll || route.Streams() {
+			c.Next()
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if err := spec.ValidateResponse(c.Request, route, c.Params, w.status, w.Header(), w.body.Bytes()); err != nil {
+			c.Writer.Header().Del("Content-Type")
+			p := errs.ProblemFor(errs.ErrInvalidResponse)
+			if fields, ok := err.(errs.FieldErrors); ok {
+				p.Fields = fields
+			}
+			problem.Render(c, p)
+			return
+		}
+
+		c.Writer.WriteHeader(w.status)
+		c.Writer.Write(w.body.Bytes())
+	}
+}
+
+// bufferedWriter holds a response back until it has been validated.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.body.Len() > 0
+}
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mathehluiz/plant-care-tracker/api/problem"
+)
+
+// CorrelationIdHeader carries the correlation id of a request, in both
+// directions.
+const CorrelationIdHeader = "X-Correlation-Id"
+
+var validCorrelationId = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// CorrelationId identifies the request, reusing the id sent by the client or
+// a proxy when there is a sane one. The id is echoed back in the response,
+// and logged with any internal error, so that a failure reported by a client
+// can be found in the logs.
+func CorrelationId() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(CorrelationIdHeader)
+		if !validCorrelationId.MatchString(id) {
+			id = uuid.NewString()
+		}
+
+		c.Set(problem.CorrelationIdKey, id)
+		c.Header(CorrelationIdHeader, id)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,22 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/api/problem"
+	"github.com/mathehluiz/plant-care-tracker/pkg/i18n"
+)
+
+// Locale negotiates the language of the response from the Accept-Language
+// header. Without an acceptable one, ValidateRoles falls back to the locale
+// saved by the user, and unauthenticated requests are answered in
+// i18n.Default.
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if locale, ok := i18n.Negotiate(c.GetHeader("Accept-Language")); ok {
+			c.Set(problem.LocaleKey, locale)
+		}
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		c.Next()
+	}
+}
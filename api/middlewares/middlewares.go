@@ -1,12 +1,14 @@
 package middlewares
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/api/problem"
+)
 
 type Middleware func(c *gin.Context) *result
 
 type result struct {
-	Status int
-	Error  error
+	Error error
 }
 
 func AddMiddlewares(middlewares ...Middleware) gin.HandlerFunc {
@@ -20,18 +22,11 @@ func AddMiddlewares(middlewares ...Middleware) gin.HandlerFunc {
 		}
 
 		if len(middlewares) > len(errs) {
+			identifyActor(c)
 			c.Next()
 			return
 		}
 
-		for _, res := range errs {
-			if res.Error == nil {
-				c.AbortWithStatus(res.Status)
-				return
-			}
-
-			c.AbortWithStatusJSON(res.Status, gin.H{"error": res.Error.Error()})
-			return
-		}
+		problem.Write(c, errs[0].Error)
 	}
 }
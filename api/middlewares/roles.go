@@ -2,42 +2,51 @@ package middlewares
 
 import (
 	"errors"
+	"github.com/mathehluiz/plant-care-tracker/api/problem"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
 	"github.com/mathehluiz/plant-care-tracker/pkg/jwt"
 	"github.com/gin-gonic/gin"
 	"strings"
 )
 
-func ValidateRoles() Middleware {
+// ValidateRoles authenticates the bearer token and loads its user, kept under
+// "auth:bearer:user" so that handlers and the audit log use the same user.
+func ValidateRoles(storer domain.UserStorer) Middleware {
 	return func(c *gin.Context) *result {
 		token := c.Request.Header.Get("Authorization")
 
 		token = strings.ReplaceAll(token, "Bearer ", "")
 		if token == "" {
-			return &result{
-				Status: 401,
-				Error:  errors.New("no authorization token provided"),
-			}
+			return &result{Error: errs.ErrMissingToken}
 		}
 
-		id, verified, roles, tokenErr := jwt.ValidateToken(token)
+		id, verified, roles, locale, tokenErr := jwt.ValidateToken(token)
 		if tokenErr != nil {
 			if errors.Is(jwt.ErrExpiredToken, tokenErr) {
-				return &result{
-					Status: 401,
-					Error:  errors.New("token has expired"),
-				}
+				return &result{Error: errs.ErrExpiredToken}
 			}
 
-			return &result{
-				Status: 400,
-				Error:  errors.New("invalid token format"),
+			return &result{Error: errs.ErrInvalidToken}
+		}
+
+		user, err := domain.GetUserBySubject(c.Request.Context(), storer, id)
+		if err != nil {
+			if errors.Is(err, errs.ErrSelectNotMatch) {
+				return &result{Error: errs.ErrNotFound}
 			}
+
+			return &result{Error: err}
 		}
 
 		c.Set("auth:type", "token")
 		c.Set("auth:bearer:id", id)
 		c.Set("auth:bearer:verified", verified)
 		c.Set("auth:bearer:roles", roles)
+		c.Set("auth:bearer:user", user)
+		if _, negotiated := c.Get(problem.LocaleKey); !negotiated && locale != "" {
+			c.Set(problem.LocaleKey, locale)
+		}
 
 		return nil
 	}
@@ -0,0 +1,84 @@
+// Package problem renders errors as RFC 7807 problem details.
+package problem
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/i18n"
+	l "github.com/mathehluiz/plant-care-tracker/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// ContentType is the media type of problem details.
+	ContentType = "application/problem+json"
+
+	// CorrelationIdKey holds the correlation id of the request in the Gin
+	// context.
+	CorrelationIdKey = "request:correlation-id"
+
+	// LocaleKey holds the locale negotiated for the request in the Gin
+	// context.
+	LocaleKey = "request:locale"
+)
+
+// Locale is the locale to answer the request in, i18n.Default when none was
+// negotiated.
+func Locale(c *gin.Context) string {
+	if locale := c.GetString(LocaleKey); locale != "" {
+		return locale
+	}
+	return i18n.Default
+}
+
+// Write aborts the request with the problem describing err. Internal errors
+// are logged with the correlation id of the request, which is all the client
+// gets to see of them.
+func Write(c *gin.Context, err error) {
+	p := errs.ProblemFor(err)
+	if p.Status >= 500 {
+		l.Logger.Error("request failed",
+			zap.Error(err),
+			zap.String("correlationId", c.GetString(CorrelationIdKey)),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+		)
+	}
+	Render(c, p)
+}
+
+// Render aborts the request with p, translated to the locale of the request.
+func Render(c *gin.Context, p *errs.Problem) {
+	locale := Locale(c)
+	localize(p, locale)
+	p.Instance = c.Request.URL.Path
+	p.CorrelationId = c.GetString(CorrelationIdKey)
+
+	c.Header("Content-Type", ContentType)
+	c.Header("Content-Language", i18n.Tag(locale))
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// localize translates the detail and the field messages of p. The messages
+// of *errs.Error are looked up by code, those of the validator come along
+// with the fields, and anything else is left in English.
+func localize(p *errs.Problem, locale string) {
+	if detail, ok := i18n.Lookup(locale, "errors."+p.Code); ok {
+		p.Detail = detail
+	}
+
+	if len(p.Fields) == 0 {
+		return
+	}
+
+	fields := make(errs.FieldErrors, len(p.Fields))
+	for i, f := range p.Fields {
+		if message, ok := f.Messages[locale]; ok {
+			f.Message = message
+		} else if message, ok := i18n.Lookup(locale, "errors."+f.Rule); ok && f.Rule != "" {
+			f.Message = message
+		}
+		fields[i] = f
+	}
+	p.Fields = fields
+}
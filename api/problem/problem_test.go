@@ -0,0 +1,79 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		purpose    string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+	}{
+		{"should use the status of the error", errs.ErrPreconditionRequired, http.StatusPreconditionRequired, "precondition_required", errs.ErrPreconditionRequired.Error()},
+		{"should not echo internal errors", errors.New("pq: connection refused"), http.StatusInternalServerError, "internal", "internal server error"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/plants/1", nil)
+			c.Set(CorrelationIdKey, "req-1")
+
+			Write(c, tt.err)
+
+			assert.True(t, c.IsAborted())
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+
+			var p errs.Problem
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+			assert.Equal(t, tt.wantStatus, p.Status)
+			assert.Equal(t, tt.wantCode, p.Code)
+			assert.Equal(t, tt.wantDetail, p.Detail)
+			assert.Equal(t, "/api/v1/plants/1", p.Instance)
+			assert.Equal(t, "req-1", p.CorrelationId)
+		})
+	}
+}
+
+func TestWriteTranslates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	fields := errs.FieldErrors{
+		{Field: "name", Rule: "min", Message: "name must be at least 3 characters in length", Messages: map[string]string{"pt_BR": "name deve ter pelo menos 3 caracteres"}},
+		{Field: "plantId", Rule: "not_found", Message: "not found"},
+		{Field: "body", Message: "request body has an error"},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/cares", nil)
+	c.Set(LocaleKey, "pt_BR")
+
+	Write(c, fields)
+
+	assert.Equal(t, "pt-BR", w.Header().Get("Content-Language"))
+
+	var p errs.Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Equal(t, "invalid_fields", p.Code)
+	assert.Equal(t, "campos inválidos", p.Detail)
+	assert.Equal(t, "name deve ter pelo menos 3 caracteres", p.Fields[0].Message)
+	assert.Equal(t, "não encontrado", p.Fields[1].Message)
+	assert.Equal(t, "request body has an error", p.Fields[2].Message)
+	assert.Equal(t, "name must be at least 3 characters in length", fields[0].Message)
+}
@@ -1,71 +1,199 @@
 package api
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mathehluiz/plant-care-tracker/api/handlers"
 	"github.com/mathehluiz/plant-care-tracker/api/middlewares"
+	"github.com/mathehluiz/plant-care-tracker/api/problem"
+	"github.com/mathehluiz/plant-care-tracker/docs"
 	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
 	"github.com/mathehluiz/plant-care-tracker/internal/cache"
+	"github.com/mathehluiz/plant-care-tracker/internal/contract"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/internal/gql"
+	"github.com/mathehluiz/plant-care-tracker/internal/live"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mailer"
 )
 
 type server struct {
 	uStorer domain.UserStorer
 	pStorer domain.PlantStorer
 	cStorer domain.CareStorer
+	jStorer domain.JobStorer
+	tStorer domain.CareTemplateStorer
+	nStorer domain.NoteStorer
+	aStorer domain.AuditStorer
+	wStorer domain.WebhookStorer
+	oStorer domain.OutboxStorer
+	eStorer domain.EmailStorer
+	hub     *live.Hub
 	cacher  cache.ConnectionStorer
+	mailer  mailer.Mailer
+	mailbox *mailer.Outbox
+	policy  *authz.Policy
+	graphql *gql.Service
+
+	contract     *contract.Contract
+	contractMode contract.Mode
 }
 
-func NewServer(uStorer domain.UserStorer, pStorer domain.PlantStorer, cStorer domain.CareStorer, cacher cache.ConnectionStorer) server {
+func NewServer(uStorer domain.UserStorer, pStorer domain.PlantStorer, cStorer domain.CareStorer, jStorer domain.JobStorer, tStorer domain.CareTemplateStorer, nStorer domain.NoteStorer, aStorer domain.AuditStorer, wStorer domain.WebhookStorer, oStorer domain.OutboxStorer, eStorer domain.EmailStorer, hub *live.Hub, cacher cache.ConnectionStorer, m mailer.Mailer, mailbox *mailer.Outbox) server {
+	policy := authz.NewPolicy(aStorer)
+
+	graphql, err := gql.NewService(uStorer, pStorer, cStorer, policy)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	spec, err := contract.Load(docs.OpenAPI)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	contractMode, err := contract.ParseMode(os.Getenv("OPENAPI_VALIDATION"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
 	return server{
 		uStorer: uStorer,
 		pStorer: pStorer,
 		cStorer: cStorer,
+		jStorer: jStorer,
+		tStorer: tStorer,
+		nStorer: nStorer,
+		aStorer: aStorer,
+		wStorer: wStorer,
+		oStorer: oStorer,
+		eStorer: eStorer,
+		hub:     hub,
 		cacher:  cacher,
+		mailer:  m,
+		mailbox: mailbox,
+		policy:  policy,
+		graphql: graphql,
+
+		contract:     spec,
+		contractMode: contractMode,
 	}
 }
 
 func (s server) Start() {
-	r := gin.Default()
+	r := gin.New()
+	r.Use(gin.Logger(), gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		problem.Write(c, fmt.Errorf("panic: %v", recovered))
+	}))
 	s.setupRoles(r)
 
+	if missing := s.contract.Undocumented(r.Routes()); len(missing) > 0 {
+		log.Fatalf("routes missing from docs/openapi.json: %s", strings.Join(missing, ", "))
+	}
+
 	log.Fatalln(r.Run(":8080"))
 }
 
 func (s server) setupRoles(rg *gin.Engine) {
 	keys := []string{"123", "456"}
 
-	bearerMiddleware := middlewares.AddMiddlewares(middlewares.ValidateRoles())
+	bearerMiddleware := middlewares.AddMiddlewares(middlewares.ValidateRoles(s.uStorer))
 	apiKeyMiddleware := middlewares.AddMiddlewares(middlewares.ValidateAPIKey(keys))
 
-	v1 := rg.Group("/api/v1")
+	rg.Use(middlewares.CorrelationId(), middlewares.Locale(), middlewares.ValidateContract(s.contract, s.contractMode))
+	rg.NoRoute(func(c *gin.Context) { problem.Write(c, errs.ErrNotFound) })
+
+	if s.mailbox != nil && os.Getenv("APP_ENV") == "development" {
+		rg.GET("/dev/mailbox", handlers.Mailbox(s.mailbox))
+		rg.GET("/dev/mailbox/:id", handlers.MailboxEmail(s.mailbox))
+	}
+
+	v1 := rg.Group("/api/v1", middlewares.Audit())
+
+	v1.GET("/openapi.json", handlers.OpenAPI(docs.OpenAPI))
+	v1.GET("/docs", handlers.Docs())
+	v1.GET("/docs/:file", handlers.DocsFile())
+
+	rg.POST("/api/graphql", middlewares.Audit(), bearerMiddleware, handlers.GraphQL(s.uStorer, s.graphql))
+	rg.GET("/api/graphql", middlewares.Audit(), bearerMiddleware, handlers.GraphQL(s.uStorer, s.graphql))
 
 	v1.POST("/login", handlers.Login(s.uStorer, s.cacher))
 	v1.POST("/verify-code", handlers.VerifyCode(s.uStorer, s.cacher))
 	v1.POST("/refresh-token", bearerMiddleware, handlers.RefreshToken(s.uStorer))
 	v1.GET("/me", bearerMiddleware, handlers.GetMe(s.uStorer))
+	v1.PATCH("/me/preferences", bearerMiddleware, handlers.UpdatePreferences(s.uStorer))
 
 	v1.POST("/register", handlers.RegisterUser(s.uStorer, s.cacher))
-	v1.POST("/verify-email", bearerMiddleware, handlers.VerifyEmail(s.uStorer, s.cacher))
-	v1.POST("/reset-password", handlers.ResetPassword(s.uStorer, s.cacher))
+	v1.POST("/verify-email", bearerMiddleware, handlers.VerifyEmail(s.uStorer, s.oStorer, s.cacher))
+	v1.POST("/reset-password", handlers.ResetPassword(s.uStorer, s.cacher, s.mailer))
 	v1.POST("/reset-password/:id", handlers.ChangePassword(s.uStorer, s.cacher))
 	v1.GET("/reset-password/:id", handlers.CheckChangePasswordStatus(s.cacher))
 
 	v1.PATCH("/set-active", bearerMiddleware, handlers.SetActive(s.uStorer))
 
+	v1.POST("/me/export", bearerMiddleware, handlers.RequestDataExport(s.uStorer, s.jStorer))
+	v1.POST("/me/erase", bearerMiddleware, handlers.RequestErasure(s.uStorer, s.jStorer))
+	v1.DELETE("/me/erase", bearerMiddleware, handlers.CancelErasure(s.uStorer, s.jStorer))
+	v1.GET("/jobs/:id", bearerMiddleware, handlers.GetJobStatus(s.uStorer, s.jStorer))
+	v1.GET("/emails", bearerMiddleware, handlers.GetEmails(s.uStorer, s.eStorer))
+	v1.GET("/emails/:id", bearerMiddleware, handlers.GetEmailByID(s.uStorer, s.eStorer))
+	v1.GET("/exports/:token", handlers.DownloadExport(s.jStorer, s.cacher))
+
 	v1.DELETE("/delete-user/:id", apiKeyMiddleware, handlers.DeleteUser(s.uStorer))
 	v1.POST("/change-roles", apiKeyMiddleware, handlers.ChangeRoles(s.uStorer))
+	v1.GET("/admin/emails/:template/preview", bearerMiddleware, handlers.PreviewEmail())
+
+	v1.POST("/plants", bearerMiddleware, handlers.CreatePlant(s.uStorer, s.pStorer))
+	v1.GET("/plants/graveyard", bearerMiddleware, handlers.GetPlantGraveyard(s.uStorer, s.pStorer))
+	v1.GET("/plants/:id", bearerMiddleware, handlers.GetPlantByID(s.uStorer, s.pStorer, s.policy))
+	v1.GET("/plants", bearerMiddleware, handlers.GetPlantsByUserID(s.uStorer, s.pStorer))
+	v1.PATCH("/plants/:id", bearerMiddleware, handlers.UpdatePlant(s.uStorer, s.pStorer, s.policy))
+	v1.DELETE("/plants/:id", bearerMiddleware, handlers.DeletePlant(s.uStorer, s.pStorer, s.policy))
+	v1.POST("/plants/:id/status", bearerMiddleware, handlers.ChangePlantStatus(s.uStorer, s.pStorer, s.policy))
+	v1.POST("/plants/:id/apply-template/:templateId", bearerMiddleware, handlers.ApplyCareTemplate(s.uStorer, s.pStorer, s.tStorer, s.cStorer, s.policy))
+	v1.GET("/plants/:id/notes", bearerMiddleware, handlers.GetPlantNotes(s.uStorer, s.pStorer, s.nStorer, s.policy))
+	v1.PUT("/plants/:id/notes", bearerMiddleware, handlers.SavePlantNotes(s.uStorer, s.pStorer, s.nStorer, s.policy))
+	v1.GET("/plants/:id/notes/diff", bearerMiddleware, handlers.DiffNoteRevisions(s.uStorer, s.pStorer, s.nStorer, s.policy))
+	v1.GET("/plants/:id/notes/revisions", bearerMiddleware, handlers.GetNoteRevisions(s.uStorer, s.pStorer, s.nStorer, s.policy))
+	v1.GET("/plants/:id/notes/revisions/:revision", bearerMiddleware, handlers.GetNoteRevision(s.uStorer, s.pStorer, s.nStorer, s.policy))
+	v1.POST("/plants/:id/notes/revisions/:revision/restore", bearerMiddleware, handlers.RestoreNoteRevision(s.uStorer, s.pStorer, s.nStorer, s.policy))
+
+	v1.POST("/cares", bearerMiddleware, handlers.CreateCare(s.uStorer, s.cStorer, s.pStorer, s.policy))
+	v1.GET("/cares/due", bearerMiddleware, handlers.GetDueCares(s.uStorer, s.pStorer, s.cStorer))
+	v1.GET("/cares/adherence", bearerMiddleware, handlers.GetCareAdherence(s.uStorer, s.pStorer, s.cStorer))
+	v1.GET("/cares/:id", bearerMiddleware, handlers.GetCareByID(s.uStorer, s.cStorer, s.policy))
+	v1.GET("/cares/plant/:id", bearerMiddleware, handlers.GetPlantCares(s.uStorer, s.cStorer, s.pStorer, s.policy))
+	v1.PATCH("/cares/:id", bearerMiddleware, handlers.UpdateCare(s.uStorer, s.cStorer, s.pStorer, s.policy))
+	v1.DELETE("/cares/:id", bearerMiddleware, handlers.DeleteCare(s.uStorer, s.cStorer, s.policy))
+	v1.POST("/cares/:id/complete", bearerMiddleware, handlers.CompleteCare(s.uStorer, s.cStorer, s.pStorer, s.policy))
+	v1.POST("/cares/:id/skip", bearerMiddleware, handlers.SkipCare(s.uStorer, s.cStorer, s.pStorer, s.policy))
+	v1.POST("/cares/:id/snooze", bearerMiddleware, handlers.SnoozeCare(s.uStorer, s.cStorer, s.policy))
+	v1.GET("/cares/:id/history", bearerMiddleware, handlers.GetCareHistory(s.uStorer, s.cStorer, s.policy))
+	v1.GET("/cares/:id/occurrences", bearerMiddleware, handlers.GetCareOccurrences(s.uStorer, s.cStorer, s.pStorer, s.policy))
+	v1.GET("/cares/:id/checklist", bearerMiddleware, handlers.GetCareChecklist(s.uStorer, s.cStorer, s.policy))
+	v1.PUT("/cares/:id/checklist", bearerMiddleware, handlers.SetCareChecklist(s.uStorer, s.cStorer, s.policy))
+	v1.PATCH("/cares/:id/checklist/:itemId", bearerMiddleware, handlers.CheckChecklistItem(s.uStorer, s.cStorer, s.pStorer, s.policy))
+
+	v1.GET("/events/stream", bearerMiddleware, handlers.StreamEvents(s.uStorer, s.hub))
+
+	v1.POST("/webhooks", bearerMiddleware, handlers.CreateWebhook(s.uStorer, s.wStorer))
+	v1.GET("/webhooks", bearerMiddleware, handlers.GetWebhooks(s.uStorer, s.wStorer))
+	v1.GET("/webhooks/:id", bearerMiddleware, handlers.GetWebhookByID(s.uStorer, s.wStorer, s.policy))
+	v1.PATCH("/webhooks/:id", bearerMiddleware, handlers.UpdateWebhook(s.uStorer, s.wStorer, s.policy))
+	v1.DELETE("/webhooks/:id", bearerMiddleware, handlers.DeleteWebhook(s.uStorer, s.wStorer, s.policy))
+	v1.GET("/webhooks/:id/deliveries", bearerMiddleware, handlers.GetWebhookDeliveries(s.uStorer, s.wStorer, s.policy))
+	v1.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", bearerMiddleware, handlers.RedeliverWebhookDelivery(s.uStorer, s.wStorer, s.policy))
+
+	v1.GET("/audit", bearerMiddleware, handlers.GetAuditLog(s.uStorer, s.aStorer))
+
+	v1.GET("/calendar", bearerMiddleware, handlers.GetCalendar(s.uStorer, s.pStorer, s.cStorer))
 
-	v1.POST("/plants", bearerMiddleware, handlers.CreatePlant(s.pStorer))
-	v1.GET("/plants/:id", bearerMiddleware, handlers.GetPlantByID(s.pStorer))
-	v1.GET("/plants", bearerMiddleware, handlers.GetPlantsByUserID(s.pStorer))
-	v1.PATCH("/plants/:id", bearerMiddleware, handlers.UpdatePlant(s.pStorer))
-	v1.DELETE("/plants/:id", bearerMiddleware, handlers.DeletePlant(s.pStorer))
-
-	v1.POST("/cares", bearerMiddleware, handlers.CreateCare(s.cStorer))
-	v1.GET("/cares/:id", bearerMiddleware, handlers.GetCareByID(s.cStorer))
-	v1.GET("/cares/plant/:id", bearerMiddleware, handlers.GetPlantCares(s.cStorer))
-	v1.PATCH("/cares/:id", bearerMiddleware, handlers.UpdateCare(s.cStorer))
-	v1.DELETE("/cares/:id", bearerMiddleware, handlers.DeleteCare(s.cStorer))
+	v1.POST("/care-templates", bearerMiddleware, handlers.CreateCareTemplate(s.uStorer, s.tStorer))
+	v1.GET("/care-templates", bearerMiddleware, handlers.GetCareTemplates(s.uStorer, s.tStorer))
+	v1.GET("/care-templates/:id", bearerMiddleware, handlers.GetCareTemplateByID(s.uStorer, s.tStorer))
+	v1.DELETE("/care-templates/:id", bearerMiddleware, handlers.DeleteCareTemplate(s.uStorer, s.tStorer))
 }
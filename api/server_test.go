@@ -0,0 +1,21 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/docs"
+	"github.com/mathehluiz/plant-care-tracker/internal/contract"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutesAreDocumented(t *testing.T) {
+	spec, err := contract.Load(docs.OpenAPI)
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	server{contract: spec, contractMode: contract.ModeRequests}.setupRoles(r)
+
+	assert.Empty(t, spec.Undocumented(r.Routes()), "every route should be described in docs/openapi.json")
+}
@@ -0,0 +1,10 @@
+// Package docs holds the OpenAPI description of the HTTP API.
+package docs
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI 3 document served at /api/v1/openapi.json and
+// enforced by the contract middleware.
+//
+//go:embed openapi.json
+var OpenAPI []byte
@@ -0,0 +1,64 @@
+package domain
+
+import "time"
+
+// Adherence summarizes how closely a user kept up with their cares. Skipped
+// occurrences are a deliberate choice and are left out of the rate.
+type Adherence struct {
+	Completed int     `json:"completed"`
+	OnTime    int     `json:"onTime"`
+	Late      int     `json:"late"`
+	Skipped   int     `json:"skipped"`
+	Snoozed   int     `json:"snoozed"`
+	Missed    int     `json:"missed"`
+	Rate      float64 `json:"rate"`
+}
+
+// ComputeAdherence counts the history entries and the occurrences missed
+// since from. A completion is on time when it happened by the end of the day,
+// in loc, the occurrence was due. An occurrence is missed when it is still
+// pending once its day is over: walking the schedule of a care of an active
+// plant from its pending occurrence, every occurrence due between from and
+// the start of today counts once. The rate is the share of on time
+// completions among completions and missed occurrences.
+func ComputeAdherence(history []*CareHistoryEntry, plants []*Plant, cares []*Care, from, now time.Time, loc *time.Location) *Adherence {
+	a := &Adherence{}
+
+	for _, entry := range history {
+		switch entry.Kind {
+		case CareHistoryCompleted:
+			a.Completed++
+			if entry.DueAt == nil || entry.OccurredAt.Before(StartOfDay(*entry.DueAt, loc).AddDate(0, 0, 1)) {
+				a.OnTime++
+			} else {
+				a.Late++
+			}
+		case CareHistorySkipped:
+			a.Skipped++
+		case CareHistorySnoozed:
+			a.Snoozed++
+		}
+	}
+
+	activePlants := make(map[int64]*Plant, len(plants))
+	for _, plant := range plants {
+		if plant.IsActive() {
+			activePlants[plant.Id] = plant
+		}
+	}
+
+	today := StartOfDay(now, loc)
+	for _, care := range cares {
+		plant, ok := activePlants[care.PlantId]
+		if !ok {
+			continue
+		}
+		a.Missed += len(care.Project(from, today, plant.CareFrequency))
+	}
+
+	if total := a.Completed + a.Missed; total > 0 {
+		a.Rate = float64(a.OnTime) / float64(total)
+	}
+
+	return a
+}
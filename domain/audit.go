@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+	AuditAccess = "access"
+
+	AuditEntityUser  = "user"
+	AuditEntityPlant = "plant"
+	AuditEntityCare  = "care"
+	// AuditEntityWebhook is only used for admin accesses, webhook changes
+	// are not audited.
+	AuditEntityWebhook = "webhook"
+
+	AuditActorUser      = "user"
+	AuditActorAPIKey    = "api_key"
+	AuditActorAnonymous = "anonymous"
+	AuditActorSystem    = "system"
+)
+
+// auditIgnored are fields that change on every write and would only add
+// noise to the diff.
+var auditIgnored = map[string]bool{"updatedAt": true, "version": true}
+
+// AuditActor is who performed a change and from where.
+type AuditActor struct {
+	Type      string `json:"type"`
+	Id        string `json:"id,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+}
+
+// AuditChange holds the JSON values of a field before and after a change.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditEntry records one create, update or delete, or an admin accessing
+// another user's data. OwnerId is the user that owns the entity, which may
+// differ from the actor.
+type AuditEntry struct {
+	Id         int64                  `json:"id"`
+	Actor      AuditActor             `json:"actor"`
+	Action     string                 `json:"action"`
+	EntityType string                 `json:"entityType"`
+	EntityId   string                 `json:"entityId"`
+	OwnerId    int64                  `json:"-"`
+	Changes    map[string]AuditChange `json:"changes"`
+	CreatedAt  time.Time              `json:"createdAt"`
+}
+
+// AuditFilter narrows down GetAuditEntries. Zero values match everything.
+// Entries are returned newest first, up to Limit, and BeforeId pages through
+// older ones.
+type AuditFilter struct {
+	OwnerId    int64
+	ActorId    string
+	Action     string
+	EntityType string
+	EntityId   string
+	From       time.Time
+	To         time.Time
+	BeforeId   int64
+	Limit      int
+}
+
+// NewAuditEntry diffs the JSON representation of before and after, either of
+// which is nil for creates and deletes. It returns nil when nothing changed.
+func NewAuditEntry(actor *AuditActor, action, entityType, entityId string, ownerId int64, before, after any) (*AuditEntry, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]AuditChange)
+	for field, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[field]) {
+			changes[field] = AuditChange{Before: value, After: afterFields[field]}
+		}
+	}
+	for field, value := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			changes[field] = AuditChange{After: value}
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	if actor == nil {
+		actor = &AuditActor{Type: AuditActorSystem}
+	}
+
+	return &AuditEntry{
+		Actor:      *actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityId:   entityId,
+		OwnerId:    ownerId,
+		Changes:    changes,
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// NewAuditAccessEntry records that actor accessed an entity owned by another
+// user, which only admins may do.
+func NewAuditAccessEntry(actor *AuditActor, entityType, entityId string, ownerId int64) *AuditEntry {
+	if actor == nil {
+		actor = &AuditActor{Type: AuditActorSystem}
+	}
+
+	return &AuditEntry{
+		Actor:      *actor,
+		Action:     AuditAccess,
+		EntityType: entityType,
+		EntityId:   entityId,
+		OwnerId:    ownerId,
+		Changes:    map[string]AuditChange{},
+		CreatedAt:  time.Now().UTC(),
+	}
+}
+
+func auditFields(v any) (map[string]any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	for field := range auditIgnored {
+		delete(fields, field)
+	}
+	return fields, nil
+}
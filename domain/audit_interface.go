@@ -0,0 +1,8 @@
+package domain
+
+import "context"
+
+type AuditStorer interface {
+	CreateAuditEntry(ctx context.Context, entry *AuditEntry) (int64, error)
+	GetAuditEntries(ctx context.Context, filter AuditFilter) ([]*AuditEntry, error)
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuditEntry(t *testing.T) {
+	actor := &AuditActor{Type: AuditActorUser, Id: "1", IP: "127.0.0.1"}
+
+	cases := []struct {
+		purpose     string
+		action      string
+		before      *Plant
+		after       *Plant
+		wantChanges map[string]AuditChange
+	}{
+		{
+			"should only record the fields that changed",
+			AuditUpdate,
+			&Plant{Id: 1, Name: "Monstera", CareFrequency: 7},
+			&Plant{Id: 1, Name: "Monstera", CareFrequency: 3},
+			map[string]AuditChange{"careFrequency": {Before: 7.0, After: 3.0}},
+		},
+		{
+			"should record every field on create",
+			AuditCreate,
+			nil,
+			&Plant{Id: 1, Name: "Monstera"},
+			nil,
+		},
+		{
+			"should skip updates without changes",
+			AuditUpdate,
+			&Plant{Id: 1, Name: "Monstera"},
+			&Plant{Id: 1, Name: "Monstera"},
+			map[string]AuditChange{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.purpose, func(t *testing.T) {
+			entry, err := NewAuditEntry(actor, c.action, AuditEntityPlant, "1", 1, c.before, c.after)
+			assert.NoError(t, err)
+
+			switch {
+			case len(c.wantChanges) > 0:
+				assert.Equal(t, c.wantChanges, entry.Changes)
+				assert.Equal(t, *actor, entry.Actor)
+			case c.wantChanges == nil:
+				assert.Equal(t, AuditChange{After: "Monstera"}, entry.Changes["name"])
+			default:
+				assert.Nil(t, entry)
+			}
+		})
+	}
+}
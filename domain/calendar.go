@@ -0,0 +1,134 @@
+package domain
+
+import "time"
+
+const (
+	CalendarDone      = "done"
+	CalendarOverdue   = "overdue"
+	CalendarScheduled = "scheduled"
+)
+
+type CalendarEntry struct {
+	CareId  int64     `json:"careId"`
+	PlantId int64     `json:"plantId"`
+	Name    string    `json:"name"`
+	Status  string    `json:"status"`
+	At      time.Time `json:"at"`
+}
+
+type CalendarDay struct {
+	Date     string           `json:"date"`
+	Count    int              `json:"count"`
+	Workload int              `json:"workload"`
+	Cares    []*CalendarEntry `json:"cares"`
+}
+
+// BuildCalendar lays out the month starting at month, in loc, one day per
+// entry. Days up to today show the completions, from the recorded history or,
+// for cares completed without one, their last care. Today also shows the
+// overdue cares, in place of their occurrence today, and days from today on
+// show the projected occurrences of the cares of active plants. Cares of
+// plants missing from plants, such as deleted ones, are left out. The
+// workload of a day is the number of steps to perform: one per care, or one
+// per checklist item for cares with a checklist.
+func BuildCalendar(month, now time.Time, loc *time.Location, plants []*Plant, cares []*Care, history []*CareHistoryEntry) []*CalendarDay {
+	start := StartOfDay(month, loc)
+	start = start.AddDate(0, 0, 1-start.Day())
+	end := start.AddDate(0, 1, 0)
+	today := StartOfDay(now, loc)
+
+	days := make([]*CalendarDay, 0, 31)
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, &CalendarDay{Date: d.Format("2006-01-02"), Cares: []*CalendarEntry{}})
+	}
+
+	add := func(care *Care, at time.Time, status string) {
+		i := StartOfDay(at, loc).Day() - 1
+		if at.Before(start) || !at.Before(end) || i >= len(days) {
+			return
+		}
+
+		days[i].Cares = append(days[i].Cares, &CalendarEntry{
+			CareId:  care.Id,
+			PlantId: care.PlantId,
+			Name:    care.Name,
+			Status:  status,
+			At:      at,
+		})
+		days[i].Count++
+		days[i].Workload += care.workload()
+	}
+
+	plantsById := make(map[int64]*Plant, len(plants))
+	for _, plant := range plants {
+		plantsById[plant.Id] = plant
+	}
+
+	caresById := make(map[int64]*Care, len(cares))
+	for _, care := range cares {
+		if _, ok := plantsById[care.PlantId]; ok {
+			caresById[care.Id] = care
+		}
+	}
+
+	type completion struct {
+		careId int64
+		day    time.Time
+	}
+	done := make(map[completion]bool)
+
+	tomorrow := today.AddDate(0, 0, 1)
+	for _, entry := range history {
+		care, ok := caresById[entry.CareId]
+		if !ok || entry.Kind != CareHistoryCompleted || !entry.OccurredAt.Before(tomorrow) {
+			continue
+		}
+		add(care, entry.OccurredAt, CalendarDone)
+		done[completion{care.Id, StartOfDay(entry.OccurredAt, loc)}] = true
+	}
+
+	// A care is created with its creation time as last care, which is not a
+	// completion.
+	for _, care := range cares {
+		if _, ok := caresById[care.Id]; !ok || care.LastCare.IsZero() || care.LastCare.Equal(care.CreatedAt) {
+			continue
+		}
+		if !care.LastCare.Before(tomorrow) || done[completion{care.Id, StartOfDay(care.LastCare, loc)}] {
+			continue
+		}
+		add(care, care.LastCare, CalendarDone)
+	}
+
+	from := today
+	if start.After(from) {
+		from = start
+	}
+
+	for _, care := range cares {
+		plant, ok := plantsById[care.PlantId]
+		if !ok || !plant.IsActive() {
+			continue
+		}
+
+		projectFrom := from
+		if !care.Finished() && care.NextCare.Before(today) {
+			add(care, today, CalendarOverdue)
+			if projectFrom.Before(tomorrow) {
+				projectFrom = tomorrow
+			}
+		}
+
+		for _, at := range care.Project(projectFrom, end, plant.CareFrequency) {
+			add(care, at, CalendarScheduled)
+		}
+	}
+
+	return days
+}
+
+func (c *Care) workload() int {
+	if len(c.Checklist) > 0 {
+		return len(c.Checklist)
+	}
+	return 1
+}
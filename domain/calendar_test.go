@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCalendar(t *testing.T) {
+	loc := time.UTC
+	month := time.Date(2026, 11, 1, 0, 0, 0, 0, loc)
+	now := time.Date(2026, 11, 10, 12, 0, 0, 0, loc)
+
+	plants := []*Plant{
+		{Id: 1, CareFrequency: 7, Status: PlantStatusActive},
+		{Id: 2, CareFrequency: 1, Status: PlantStatusDeceased},
+	}
+
+	cases := []struct {
+		purpose      string
+		cares        []*Care
+		history      []*CareHistoryEntry
+		wantCounts   map[int]int
+		wantWorkload map[int]int
+		wantStatus   map[int]string
+	}{
+		{
+			"should project recurring cares from today on using the plant frequency",
+			[]*Care{{Id: 1, PlantId: 1, Name: "Water", NextCare: time.Date(2026, 11, 12, 9, 0, 0, 0, loc)}},
+			nil,
+			map[int]int{12: 1, 19: 1, 26: 1, 5: 0},
+			map[int]int{12: 1},
+			map[int]string{12: CalendarScheduled},
+		},
+		{
+			"should show recorded history on past days and weigh checklists",
+			[]*Care{{Id: 1, PlantId: 1, Name: "Repot", Interval: 30, NextCare: time.Date(2026, 11, 30, 9, 0, 0, 0, loc),
+				Checklist: []*ChecklistItem{{Title: "Remove"}, {Title: "Trim"}, {Title: "Soil"}}}},
+			[]*CareHistoryEntry{{CareId: 1, Kind: CareHistoryCompleted, OccurredAt: time.Date(2026, 11, 3, 8, 0, 0, 0, loc)}},
+			map[int]int{3: 1, 30: 1},
+			map[int]int{3: 3, 30: 3},
+			map[int]string{3: CalendarDone, 30: CalendarScheduled},
+		},
+		{
+			"should show overdue cares today and skip plants that are not active",
+			[]*Care{
+				{Id: 1, PlantId: 1, Name: "Water", Interval: 30, NextCare: time.Date(2026, 11, 8, 9, 0, 0, 0, loc)},
+				{Id: 2, PlantId: 2, Name: "Water", NextCare: time.Date(2026, 11, 11, 9, 0, 0, 0, loc)},
+			},
+			nil,
+			map[int]int{8: 0, 10: 1, 11: 0},
+			map[int]int{10: 1},
+			map[int]string{10: CalendarOverdue},
+		},
+		{
+			"should show completions only recorded as last care on past days",
+			[]*Care{{Id: 1, PlantId: 1, Name: "Water", Interval: 30, CreatedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, loc),
+				LastCare: time.Date(2026, 11, 5, 8, 0, 0, 0, loc), NextCare: time.Date(2026, 12, 5, 8, 0, 0, 0, loc)}},
+			nil,
+			map[int]int{5: 1},
+			map[int]int{5: 1},
+			map[int]string{5: CalendarDone},
+		},
+		{
+			"should not show a last care recorded in the history twice",
+			[]*Care{{Id: 1, PlantId: 1, Name: "Water", Interval: 30, CreatedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, loc),
+				LastCare: time.Date(2026, 11, 3, 8, 0, 0, 0, loc), NextCare: time.Date(2026, 12, 3, 8, 0, 0, 0, loc)}},
+			[]*CareHistoryEntry{{CareId: 1, Kind: CareHistoryCompleted, OccurredAt: time.Date(2026, 11, 3, 8, 0, 0, 0, loc)}},
+			map[int]int{3: 1},
+			nil,
+			map[int]string{3: CalendarDone},
+		},
+		{
+			"should not show the creation of a care as done",
+			[]*Care{{Id: 1, PlantId: 1, Name: "Water", Interval: 30, CreatedAt: time.Date(2026, 11, 4, 9, 0, 0, 0, loc),
+				LastCare: time.Date(2026, 11, 4, 9, 0, 0, 0, loc), NextCare: time.Date(2026, 12, 4, 9, 0, 0, 0, loc)}},
+			nil,
+			map[int]int{4: 0},
+			nil,
+			nil,
+		},
+		{
+			"should show an overdue care once today",
+			[]*Care{{Id: 1, PlantId: 1, Name: "Water", Interval: 2, NextCare: time.Date(2026, 11, 8, 9, 0, 0, 0, loc)}},
+			nil,
+			map[int]int{10: 1, 12: 1},
+			nil,
+			map[int]string{10: CalendarOverdue, 12: CalendarScheduled},
+		},
+		{
+			"should leave out the cares of deleted plants",
+			[]*Care{{Id: 1, PlantId: 3, Name: "Water", Interval: 7, CreatedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, loc),
+				LastCare: time.Date(2026, 11, 4, 9, 0, 0, 0, loc), NextCare: time.Date(2026, 11, 8, 9, 0, 0, 0, loc)}},
+			[]*CareHistoryEntry{{CareId: 1, Kind: CareHistoryCompleted, OccurredAt: time.Date(2026, 11, 3, 8, 0, 0, 0, loc)}},
+			map[int]int{3: 0, 4: 0, 10: 0, 15: 0},
+			nil,
+			nil,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			days := BuildCalendar(month, now, loc, plants, tt.cares, tt.history)
+			assert.Len(t, days, 30)
+
+			for day, count := range tt.wantCounts {
+				assert.Equal(t, count, days[day-1].Count, "count of day %d", day)
+			}
+			for day, workload := range tt.wantWorkload {
+				assert.Equal(t, workload, days[day-1].Workload, "workload of day %d", day)
+			}
+			for day, status := range tt.wantStatus {
+				assert.Equal(t, status, days[day-1].Cares[0].Status, "status of day %d", day)
+			}
+		})
+	}
+}
@@ -4,65 +4,289 @@ import (
 	"time"
 
 	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mergepatch"
+	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
 )
 
 type Care struct {
-	Id          int64     `json:"id"`
-	PlantId     int64     `json:"plantId"`
-	UserId      int64     `json:"-"`
-	LastCare    time.Time `json:"lastCare"`
-	NextCare    time.Time `json:"nextCare"`
-	Name        string    `json:"name"`
-	Notes       string    `json:"notes"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	Id             int64            `json:"id"`
+	PlantId        int64            `json:"plantId" validate:"min=1"`
+	UserId         int64            `json:"-"`
+	LastCare       time.Time        `json:"lastCare"`
+	NextCare       time.Time        `json:"nextCare"`
+	Name           string           `json:"name" validate:"min=3,max=100"`
+	Notes          string           `json:"notes" validate:"omitempty,min=3,max=1000"`
+	Interval       int              `json:"interval,omitempty" validate:"omitempty,min=1,max=365"`
+	TemplateItemId int64            `json:"templateItemId,omitempty"`
+	Checklist      []*ChecklistItem `json:"checklist,omitempty"`
+	Recurrence     *Recurrence      `json:"recurrence,omitempty"`
+	SnoozedFrom    *time.Time       `json:"snoozedFrom,omitempty"`
+	Version        int              `json:"version"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	UpdatedAt      time.Time        `json:"updatedAt"`
 }
 
-func NewCare(plantId, userId int64, nextCare time.Time, name, notes string) (*Care, error) {
-	if len(name) < 3 || len(name) > 100 {
-		return nil, errs.ErrInvalidCareName
+// NewCare creates a care due at nextCare. When recurrence is set, nextCare is
+// the start of the rule and the care is due at its first upcoming occurrence.
+// Every invalid field is reported in the returned errs.FieldErrors; the
+// schedule is only checked once they are valid.
+func NewCare(plantId, userId int64, nextCare time.Time, name, notes string, recurrence *Recurrence) (*Care, error) {
+	lastCare := time.Now().UTC()
+	care := &Care{
+		PlantId:    plantId,
+		UserId:     userId,
+		LastCare:   lastCare,
+		Name:       name,
+		Notes:      notes,
+		Recurrence: recurrence,
+		Version:    1,
+		CreatedAt:  lastCare,
+		UpdatedAt:  lastCare,
 	}
 
-	if len(notes) < 3 || len(notes) > 1000 {
-		return nil, errs.ErrInvalidCareNotes
+	if err := validate.Struct(care); err != nil {
+		return nil, err
 	}
-	lastCare := time.Now()
+
+	nextCare, err := scheduleRecurrence(recurrence, nextCare, lastCare)
+	if err != nil {
+		return nil, err
+	}
+
 	if lastCare.After(nextCare) {
-		return nil, errs.ErrInvalidCareDate
-	}
-
-	return &Care{
-		PlantId:   plantId,
-		UserId:    userId,
-		LastCare:  lastCare,
-		NextCare:  nextCare,
-		Name:      name,
-		Notes:     notes,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}, nil
+		var fields errs.FieldErrors
+		fields.Add("nextCare", errs.ErrInvalidCareDate)
+		return nil, fields
+	}
+
+	care.NextCare = nextCare
+	return care, nil
 }
 
-func (c *Care) Update(plantId, userId int64, lastCare, nextCare time.Time, name, notes string) error {
-	if len(name) < 3 || len(name) > 100 {
-		return errs.ErrInvalidCareName
+// Patch applies a JSON Merge Patch to the editable fields of the care:
+// plantId, lastCare, nextCare, name, notes, interval, rrule, timezone and
+// exdates. Only the fields sent are validated and every invalid one is
+// reported in the returned errs.FieldErrors, in which case the care is left
+// unchanged. Notes, interval and the recurrence fields can be cleared with
+// null; clearing rrule removes the recurrence.
+//
+// As in NewCare, nextCare is the start of the rule of a recurring care, and
+// changing the schedule drops any snooze.
+func (c *Care) Patch(doc mergepatch.Document) error {
+	r := newPatchReader(doc)
+
+	patched := *c
+	lastCare, nextCare := c.LastCare, c.NextCare
+
+	var rule, timezone string
+	var exdates []time.Time
+	if c.Recurrence != nil {
+		rule, timezone, exdates = c.Recurrence.RRule, c.Recurrence.Timezone, c.Recurrence.ExDates
+		nextCare = c.Recurrence.Start
+	}
+
+	r.read("plantId", &patched.PlantId, false)
+	r.read("name", &patched.Name, false)
+	if _, null := r.read("notes", &patched.Notes, true); null {
+		patched.Notes = ""
+	}
+	if _, null := r.read("interval", &patched.Interval, true); null {
+		patched.Interval = 0
+	}
+	r.validate(&patched)
+
+	lastCareSent, _ := r.read("lastCare", &lastCare, false)
+	nextCareSent, _ := r.read("nextCare", &nextCare, false)
+
+	ruleSent, ruleNull := r.read("rrule", &rule, true)
+	if ruleNull {
+		rule = ""
+	}
+	timezoneSent, timezoneNull := r.read("timezone", &timezone, true)
+	if timezoneNull {
+		timezone = ""
+	}
+	exdatesSent, exdatesNull := r.read("exdates", &exdates, true)
+	if exdatesNull {
+		exdates = nil
 	}
 
-	if len(notes) < 3 || len(notes) > 1000 {
-		return errs.ErrInvalidCareNotes
+	recurrence := c.Recurrence
+	if ruleSent || timezoneSent || exdatesSent {
+		recurrence = nil
+		if rule != "" {
+			var err error
+			recurrence, err = NewRecurrence(rule, timezone, exdates)
+			r.errors.Merge("rrule", err)
+		} else if (timezoneSent && !timezoneNull) || (exdatesSent && !exdatesNull) {
+			r.check("rrule", errs.ErrInvalidCareRecurrence)
+		}
 	}
 
-	if lastCare.After(nextCare) {
-		return errs.ErrInvalidCareDate
+	rescheduled := ruleSent || timezoneSent || exdatesSent || lastCareSent || nextCareSent
+	if rescheduled && len(r.errors) == 0 {
+		if recurrence != nil {
+			if recurrence == c.Recurrence {
+				copied := *recurrence
+				recurrence = &copied
+			}
+
+			next, err := scheduleRecurrence(recurrence, nextCare, lastCare)
+			if err != nil {
+				r.check("rrule", err)
+			}
+			nextCare = next
+		}
+
+		if lastCare.After(nextCare) {
+			field := "nextCare"
+			if !nextCareSent {
+				field = "lastCare"
+			}
+			r.check(field, errs.ErrInvalidCareDate)
+		}
+	}
+
+	if err := r.err(); err != nil {
+		return err
 	}
 
-	c.PlantId = plantId
-	c.UserId = userId
-	c.LastCare = lastCare
-	c.NextCare = nextCare
-	c.Name = name
-	c.Notes = notes
-	c.UpdatedAt = time.Now()
+	c.PlantId = patched.PlantId
+	c.Name = patched.Name
+	c.Notes = patched.Notes
+	c.Interval = patched.Interval
+	if rescheduled {
+		c.LastCare = lastCare
+		c.NextCare = nextCare
+		c.Recurrence = recurrence
+		c.SnoozedFrom = nil
+	}
+	c.UpdatedAt = time.Now().UTC()
 
 	return nil
-}
\ No newline at end of file
+}
+
+// scheduleRecurrence anchors the recurrence at start and returns its first
+// occurrence after from. Without a recurrence start is returned as is.
+func scheduleRecurrence(recurrence *Recurrence, start, from time.Time) (time.Time, error) {
+	if recurrence == nil {
+		return start, nil
+	}
+
+	recurrence.Start = start
+	next, ok := recurrence.After(from)
+	if !ok {
+		return time.Time{}, errs.ErrCareRecurrenceEnded
+	}
+
+	return next.UTC(), nil
+}
+
+// Finished reports whether a recurring care had its last occurrence done and
+// has nothing left to schedule.
+func (c *Care) Finished() bool {
+	return c.Recurrence != nil && !c.LastCare.Before(c.NextCare)
+}
+
+// IsDue reports whether the care is scheduled for the current calendar day,
+// or earlier, in loc.
+func (c *Care) IsDue(now time.Time, loc *time.Location) bool {
+	if c.Finished() {
+		return false
+	}
+
+	tomorrow := StartOfDay(now, loc).AddDate(0, 0, 1)
+	return c.NextCare.Before(tomorrow)
+}
+
+// EffectiveInterval returns the care interval in days, falling back to the
+// plant care frequency for cares without their own interval.
+func (c *Care) EffectiveInterval(plantFrequency int) int {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+	return plantFrequency
+}
+
+// Project returns the scheduled occurrences in [from, to) that are still
+// ahead, starting at NextCare.
+func (c *Care) Project(from, to time.Time, plantFrequency int) []time.Time {
+	if c.Finished() {
+		return nil
+	}
+
+	var occurrences []time.Time
+	if c.SnoozedFrom != nil {
+		if !c.NextCare.Before(from) && c.NextCare.Before(to) {
+			occurrences = append(occurrences, c.NextCare)
+		}
+
+		// The snoozed occurrence was moved to NextCare, the rest of the
+		// schedule carries on from where it was.
+		if after := c.SnoozedFrom.Add(time.Nanosecond); from.Before(after) {
+			from = after
+		}
+	} else if from.Before(c.NextCare) {
+		from = c.NextCare
+	}
+
+	return append(occurrences, c.Occurrences(from, to, plantFrequency)...)
+}
+
+// Occurrences returns the occurrences in [from, to). Recurring cares expand
+// their rule; other cares repeat every EffectiveInterval days from the
+// scheduled occurrence.
+func (c *Care) Occurrences(from, to time.Time, plantFrequency int) []time.Time {
+	if c.Recurrence != nil {
+		return c.Recurrence.Between(from, to)
+	}
+
+	interval := c.EffectiveInterval(plantFrequency)
+	if interval < 1 {
+		return nil
+	}
+
+	var occurrences []time.Time
+	for next := c.scheduled(); next.Before(to); next = next.AddDate(0, 0, interval) {
+		if !next.Before(from) {
+			occurrences = append(occurrences, next)
+		}
+	}
+
+	return occurrences
+}
+
+// scheduled returns when the current occurrence was due before any snooze.
+func (c *Care) scheduled() time.Time {
+	if c.SnoozedFrom != nil {
+		return *c.SnoozedFrom
+	}
+	return c.NextCare
+}
+
+// nextOccurrence returns the first occurrence after both the scheduled
+// occurrence and now. The second result is false when a recurrence has no
+// more occurrences.
+func (c *Care) nextOccurrence(now time.Time, plantFrequency int) (time.Time, bool, error) {
+	if c.Recurrence != nil {
+		after := c.scheduled()
+		if now.After(after) {
+			after = now
+		}
+
+		next, ok := c.Recurrence.After(after)
+		return next.UTC(), ok, nil
+	}
+
+	interval := c.EffectiveInterval(plantFrequency)
+	if interval < 1 {
+		return time.Time{}, false, errs.ErrInvalidCareInterval
+	}
+
+	next := c.scheduled().AddDate(0, 0, interval)
+	for !next.After(now) {
+		next = next.AddDate(0, 0, interval)
+	}
+
+	return next, true, nil
+}
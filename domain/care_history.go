@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+const (
+	CareHistoryCompleted = "completed"
+	CareHistorySkipped   = "skipped"
+	CareHistorySnoozed   = "snoozed"
+)
+
+// CareHistoryEntry records something that happened to a care occurrence,
+// such as it being completed, skipped or snoozed.
+type CareHistoryEntry struct {
+	Id         int64      `json:"id"`
+	CareId     int64      `json:"careId"`
+	UserId     int64      `json:"-"`
+	Kind       string     `json:"kind"`
+	DueAt      *time.Time `json:"dueAt,omitempty"`
+	OccurredAt time.Time  `json:"occurredAt"`
+	Reason     string     `json:"reason,omitempty"`
+}
+
+func newCareHistoryEntry(c *Care, kind string, dueAt, occurredAt time.Time, reason string) *CareHistoryEntry {
+	return &CareHistoryEntry{
+		CareId:     c.Id,
+		UserId:     c.UserId,
+		Kind:       kind,
+		DueAt:      &dueAt,
+		OccurredAt: occurredAt,
+		Reason:     reason,
+	}
+}
@@ -2,12 +2,28 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 type CareStorer interface {
 	CreateCare(ctx context.Context, care *Care) (int64, error)
+	CreateCares(ctx context.Context, cares []*Care) ([]int64, error)
 	GetPlantCares(ctx context.Context, plantId int64) ([]*Care, error)
+	// GetCaresByPlantIDs returns the cares of every given plant in a single
+	// round trip.
+	GetCaresByPlantIDs(ctx context.Context, plantIds []int64) ([]*Care, error)
+	GetCaresByUserID(ctx context.Context, userId int64) ([]*Care, error)
 	GetCareByID(ctx context.Context, id int64) (*Care, error)
-	UpdateCare(ctx context.Context, care *Care) error
-	DeleteCare(ctx context.Context, id int64) error
-}
\ No newline at end of file
+	// GetCaresDueBetween returns the cares of active plants whose next
+	// occurrence is after from and no later than to.
+	GetCaresDueBetween(ctx context.Context, from, to time.Time) ([]*Care, error)
+	// UpdateCare saves the care along with the given history entries. It
+	// fails with errs.ErrVersionConflict when the stored care is no longer at
+	// care.Version, as does SetChecklist.
+	UpdateCare(ctx context.Context, care *Care, history ...*CareHistoryEntry) error
+	SetChecklist(ctx context.Context, care *Care) error
+	// DeleteCare checks version the same way, unless it is 0.
+	DeleteCare(ctx context.Context, id int64, version int) error
+	GetCareHistory(ctx context.Context, careId int64) ([]*CareHistoryEntry, error)
+	GetCareHistoryByUserID(ctx context.Context, userId int64, from, to time.Time) ([]*CareHistoryEntry, error)
+}
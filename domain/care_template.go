@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
+)
+
+type CareTemplate struct {
+	Id        int64               `json:"id"`
+	UserId    int64               `json:"-"`
+	Name      string              `json:"name" validate:"min=3,max=100"`
+	BuiltIn   bool                `json:"builtIn"`
+	Items     []*CareTemplateItem `json:"items" validate:"min=1,max=20,dive"`
+	CreatedAt time.Time           `json:"createdAt"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+}
+
+type CareTemplateItem struct {
+	Id       int64  `json:"id"`
+	Name     string `json:"name" validate:"min=3,max=100"`
+	Notes    string `json:"notes" validate:"min=3,max=1000"`
+	Interval int    `json:"interval" validate:"min=1,max=365"`
+	Offset   int    `json:"offset" validate:"min=0,max=365"`
+}
+
+// NewCareTemplate creates a template of the user. Every invalid field,
+// including the ones of the items, such as items.0.name, is reported in the
+// returned errs.FieldErrors.
+func NewCareTemplate(userId int64, name string, items []*CareTemplateItem) (*CareTemplate, error) {
+	template := &CareTemplate{
+		UserId:    userId,
+		Name:      name,
+		Items:     items,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := validate.Struct(template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// VisibleTo reports whether the template is built-in or owned by userId.
+func (t *CareTemplate) VisibleTo(userId int64) bool {
+	return t.BuiltIn || t.UserId == userId
+}
+
+// Instantiate builds one care per template item for the plant. The first
+// occurrence is the acquisition date plus the item offset, rolled forward by
+// the item interval until it is no longer in the past.
+func (t *CareTemplate) Instantiate(plant *Plant, now time.Time) []*Care {
+	start := plant.AcquisitionDate
+	if start.IsZero() {
+		start = now
+	}
+
+	cares := make([]*Care, 0, len(t.Items))
+	for _, item := range t.Items {
+		next := start.AddDate(0, 0, item.Offset)
+		for next.Before(now) {
+			next = next.AddDate(0, 0, item.Interval)
+		}
+
+		cares = append(cares, &Care{
+			PlantId:        plant.Id,
+			UserId:         plant.UserId,
+			LastCare:       now,
+			NextCare:       next,
+			Name:           item.Name,
+			Notes:          item.Notes,
+			Interval:       item.Interval,
+			TemplateItemId: item.Id,
+			Version:        1,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+	}
+
+	return cares
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+type CareTemplateStorer interface {
+	CreateCareTemplate(ctx context.Context, template *CareTemplate) (int64, error)
+	GetCareTemplateByID(ctx context.Context, id int64) (*CareTemplate, error)
+	GetCareTemplatesByUserID(ctx context.Context, userId int64) ([]*CareTemplate, error)
+	DeleteCareTemplate(ctx context.Context, id int64) error
+}
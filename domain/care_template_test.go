@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCareTemplateInstantiate(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	item := &CareTemplateItem{Id: 7, Name: "Water", Notes: "Soak the soil", Interval: 7}
+
+	cases := []struct {
+		purpose  string
+		acquired time.Time
+		offset   int
+		wantNext time.Time
+	}{
+		{
+			"should start the first occurrence at the acquisition date plus the offset",
+			time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC),
+			3,
+			time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			"should roll a past first occurrence forward by the interval",
+			time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+			2,
+			time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			"should start from now when the plant has no acquisition date",
+			time.Time{},
+			1,
+			time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			template := &CareTemplate{Items: []*CareTemplateItem{{
+				Id: item.Id, Name: item.Name, Notes: item.Notes, Interval: item.Interval, Offset: tt.offset,
+			}}}
+			plant := &Plant{Id: 3, UserId: 5, AcquisitionDate: tt.acquired}
+
+			cares := template.Instantiate(plant, now)
+
+			assert.Len(t, cares, 1)
+			care := cares[0]
+			assert.Equal(t, tt.wantNext, care.NextCare)
+			assert.Equal(t, int64(3), care.PlantId)
+			assert.Equal(t, int64(5), care.UserId)
+			assert.Equal(t, item.Name, care.Name)
+			assert.Equal(t, item.Notes, care.Notes)
+			assert.Equal(t, item.Interval, care.Interval)
+			assert.Equal(t, item.Id, care.TemplateItemId, "should link the care to its item, so applying again skips it")
+			assert.Equal(t, 1, care.Version)
+		})
+	}
+}
+
+func TestCareTemplateInstantiateEveryItem(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	template := &CareTemplate{Items: []*CareTemplateItem{
+		{Id: 1, Name: "Water", Interval: 7},
+		{Id: 2, Name: "Fertilize", Interval: 30, Offset: 14},
+	}}
+
+	cares := template.Instantiate(&Plant{Id: 3, AcquisitionDate: now}, now)
+
+	var names []string
+	for _, care := range cares {
+		names = append(names, care.Name)
+	}
+	assert.Equal(t, []string{"Water", "Fertilize"}, names)
+	assert.Equal(t, now.AddDate(0, 0, 14), cares[1].NextCare)
+}
+
+func TestCareTemplateVisibleTo(t *testing.T) {
+	cases := []struct {
+		purpose  string
+		template CareTemplate
+		userId   int64
+		want     bool
+	}{
+		{"should show built-in templates to everyone", CareTemplate{BuiltIn: true}, 5, true},
+		{"should show a template to its owner", CareTemplate{UserId: 5}, 5, true},
+		{"should hide a template from other users", CareTemplate{UserId: 5}, 6, false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.template.VisibleTo(tt.userId))
+		})
+	}
+}
+
+func TestNewCareTemplate(t *testing.T) {
+	cases := []struct {
+		purpose    string
+		name       string
+		items      []*CareTemplateItem
+		wantFields []string
+	}{
+		{
+			"should create a template of the user",
+			"Ferns",
+			[]*CareTemplateItem{{Name: "Water", Notes: "Soak the soil", Interval: 7}},
+			nil,
+		},
+		{
+			"should report the invalid fields of the items with their path",
+			"Ferns",
+			[]*CareTemplateItem{{Name: "Water", Notes: "Soak the soil", Interval: 7}, {Name: "x", Notes: "Soak the soil", Interval: 0}},
+			[]string{"items.1.name", "items.1.interval"},
+		},
+		{
+			"should require an item",
+			"Ferns",
+			[]*CareTemplateItem{},
+			[]string{"items"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			template, err := NewCareTemplate(5, tt.name, tt.items)
+			if tt.wantFields == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(5), template.UserId)
+				assert.False(t, template.BuiltIn)
+				return
+			}
+
+			var fields errs.FieldErrors
+			assert.ErrorAs(t, err, &fields)
+
+			var got []string
+			for _, f := range fields {
+				got = append(got, f.Field)
+			}
+			assert.Equal(t, tt.wantFields, got)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+const maxChecklistItems = 30
+
+type ChecklistItem struct {
+	Id        int64      `json:"id"`
+	Title     string     `json:"title"`
+	Checked   bool       `json:"checked"`
+	CheckedAt *time.Time `json:"checkedAt,omitempty"`
+}
+
+// SetChecklist replaces the care checklist with unchecked items in the given
+// order. An empty list removes the checklist.
+func (c *Care) SetChecklist(titles []string) error {
+	if len(titles) > maxChecklistItems {
+		return errs.ErrInvalidChecklist
+	}
+
+	items := make([]*ChecklistItem, 0, len(titles))
+	for _, title := range titles {
+		if len(title) < 1 || len(title) > 100 {
+			return errs.ErrInvalidChecklistItem
+		}
+		items = append(items, &ChecklistItem{Title: title})
+	}
+
+	c.Checklist = items
+	c.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (c *Care) CheckItem(itemId int64, checked bool, now time.Time) error {
+	for _, item := range c.Checklist {
+		if item.Id != itemId {
+			continue
+		}
+
+		item.Checked = checked
+		item.CheckedAt = nil
+		if checked {
+			item.CheckedAt = &now
+		}
+
+		return nil
+	}
+
+	return errs.ErrChecklistItemNotFound
+}
+
+// ChecklistComplete reports whether every checklist item is checked. A care
+// without a checklist is always complete.
+func (c *Care) ChecklistComplete() bool {
+	for _, item := range c.Checklist {
+		if !item.Checked {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Care) resetChecklist() {
+	for _, item := range c.Checklist {
+		item.Checked = false
+		item.CheckedAt = nil
+	}
+}
+
+// Complete records the care as done at now and schedules the next occurrence
+// after it. Recurring cares follow their rule; otherwise the care interval is
+// used when set, falling back to the plant care frequency. The checklist, if
+// any, must be fully checked and is reset for the next occurrence. A care
+// whose recurrence ended was already completed for the last time. The
+// returned entry should be stored along with the care.
+func (c *Care) Complete(now time.Time, plantFrequency int) (*CareHistoryEntry, error) {
+	if c.Finished() {
+		return nil, errs.ErrCareRecurrenceEnded
+	}
+
+	if !c.ChecklistComplete() {
+		return nil, errs.ErrChecklistIncomplete
+	}
+
+	next, ok, err := c.nextOccurrence(now, plantFrequency)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := newCareHistoryEntry(c, CareHistoryCompleted, c.NextCare, now, "")
+
+	c.LastCare = now
+	if ok {
+		c.NextCare = next
+	}
+	c.SnoozedFrom = nil
+	c.UpdatedAt = now
+	c.resetChecklist()
+
+	return entry, nil
+}
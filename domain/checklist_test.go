@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func newChecklistCare() *Care {
+	return &Care{Checklist: []*ChecklistItem{{Id: 1, Title: "Check the soil"}, {Id: 2, Title: "Water"}}}
+}
+
+func TestCareSetChecklist(t *testing.T) {
+	cases := []struct {
+		purpose    string
+		titles     []string
+		wantErr    error
+		wantTitles []string
+	}{
+		{"should replace the checklist with unchecked items", []string{"Mist", "Rotate"}, nil, []string{"Mist", "Rotate"}},
+		{"should remove the checklist", []string{}, nil, nil},
+		{"should reject an empty title", []string{"Mist", ""}, errs.ErrInvalidChecklistItem, []string{"Check the soil", "Water"}},
+		{"should reject a long title", []string{strings.Repeat("a", 101)}, errs.ErrInvalidChecklistItem, []string{"Check the soil", "Water"}},
+		{"should reject too many items", make([]string, maxChecklistItems+1), errs.ErrInvalidChecklist, []string{"Check the soil", "Water"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			care := newChecklistCare()
+			care.Checklist[0].Checked = true
+
+			err := care.SetChecklist(tt.titles)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			var titles []string
+			for _, item := range care.Checklist {
+				titles = append(titles, item.Title)
+				if err == nil {
+					assert.False(t, item.Checked)
+				}
+			}
+			assert.Equal(t, tt.wantTitles, titles)
+		})
+	}
+}
+
+func TestCareCheckItem(t *testing.T) {
+	now := time.Date(2026, 11, 10, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		purpose      string
+		itemId       int64
+		checked      bool
+		wantErr      error
+		wantChecked  []bool
+		wantComplete bool
+	}{
+		{"should check an item", 1, true, nil, []bool{true, true}, true},
+		{"should uncheck an item", 2, false, nil, []bool{false, false}, false},
+		{"should reject an item of another care", 3, true, errs.ErrChecklistItemNotFound, []bool{false, true}, false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			care := newChecklistCare()
+			checkedAt := now.Add(-time.Hour)
+			care.Checklist[1].Checked = true
+			care.Checklist[1].CheckedAt = &checkedAt
+
+			err := care.CheckItem(tt.itemId, tt.checked, now)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			var checked []bool
+			for _, item := range care.Checklist {
+				checked = append(checked, item.Checked)
+				assert.Equal(t, item.Checked, item.CheckedAt != nil, "should only time checked items")
+			}
+			assert.Equal(t, tt.wantChecked, checked)
+			assert.Equal(t, tt.wantComplete, care.ChecklistComplete())
+		})
+	}
+}
+
+func TestCareCompleteChecklist(t *testing.T) {
+	now := time.Date(2026, 11, 10, 12, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 11, 10, 9, 0, 0, 0, time.UTC)
+
+	ended, err := NewRecurrence("FREQ=DAILY;COUNT=1", "UTC", nil)
+	assert.NoError(t, err)
+	ended.Start = due
+
+	cases := []struct {
+		purpose      string
+		care         *Care
+		checkAll     bool
+		wantErr      error
+		wantNextCare time.Time
+	}{
+		{
+			"should complete a care without a checklist",
+			&Care{NextCare: due, LastCare: due.AddDate(0, 0, -7)},
+			false,
+			nil,
+			due.AddDate(0, 0, 7),
+		},
+		{
+			"should complete a care once every item is checked",
+			&Care{NextCare: due, LastCare: due.AddDate(0, 0, -7), Checklist: newChecklistCare().Checklist},
+			true,
+			nil,
+			due.AddDate(0, 0, 7),
+		},
+		{
+			"should reject a care with unchecked items",
+			&Care{NextCare: due, LastCare: due.AddDate(0, 0, -7), Checklist: newChecklistCare().Checklist},
+			false,
+			errs.ErrChecklistIncomplete,
+			due,
+		},
+		{
+			"should reject a care that was already completed for the last time",
+			&Care{NextCare: due, LastCare: due, Recurrence: ended},
+			false,
+			errs.ErrCareRecurrenceEnded,
+			due,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			if tt.checkAll {
+				for _, item := range tt.care.Checklist {
+					assert.NoError(t, tt.care.CheckItem(item.Id, true, now))
+				}
+			}
+
+			entry, err := tt.care.Complete(now, 7)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantNextCare, tt.care.NextCare)
+			if err != nil {
+				assert.Nil(t, entry)
+				return
+			}
+
+			assert.Equal(t, CareHistoryCompleted, entry.Kind)
+			assert.Equal(t, now, tt.care.LastCare)
+			for _, item := range tt.care.Checklist {
+				assert.False(t, item.Checked, "should reset the checklist for the next occurrence")
+				assert.Nil(t, item.CheckedAt)
+			}
+		})
+	}
+}
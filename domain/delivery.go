@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// DeliveryLease is how long a claimed email or webhook delivery is hidden
+// from other replicas, so that one whose sender died is retried once it
+// expires. Senders must give up on a delivery well within it.
+const DeliveryLease = 2 * time.Minute
+
+// retryBackoff is the delay before retrying a delivery after the given
+// number of attempts: base after the first one, doubling after every other
+// one, up to max. Fewer than one attempt counts as one.
+func retryBackoff(attempts int, base, max time.Duration) time.Duration {
+	if attempts < 1 {
+		return base
+	}
+	if attempts >= 20 {
+		return max
+	}
+	if b := base << (attempts - 1); b < max {
+		return b
+	}
+	return max
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	cases := []struct {
+		purpose  string
+		attempts int
+		want     time.Duration
+	}{
+		{"should wait the base delay after the first attempt", 1, time.Minute},
+		{"should double the delay after every attempt", 3, 4 * time.Minute},
+		{"should cap the delay", 8, time.Hour},
+		{"should not overflow after many attempts", 70, time.Hour},
+		{"should wait the base delay before any attempt", 0, time.Minute},
+		{"should wait the base delay for a negative count", -1, time.Minute},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			assert.Equal(t, tt.want, retryBackoff(tt.attempts, time.Minute, time.Hour))
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	EmailQueued = "queued"
+	EmailSent   = "sent"
+	EmailDead   = "dead"
+)
+
+// EmailMaxAttempts is how many times an email is tried before it is marked
+// as dead. With the backoff below the last attempt happens about 3 hours
+// after the email was queued.
+const EmailMaxAttempts = 10
+
+// emailBackoff is the delay before the first retry. It doubles after every
+// failed attempt, up to emailMaxBackoff.
+var (
+	emailBackoff    = 30 * time.Second
+	emailMaxBackoff = time.Hour
+)
+
+// EmailRateLimit is how many emails a recipient gets per EmailRateWindow at
+// most. Further emails wait in the queue.
+var (
+	EmailRateLimit  = 10
+	EmailRateWindow = time.Hour
+)
+
+// EmailDelivery is an email waiting in, or sent from, the email queue. The
+// bodies are dropped once it is sent or given up on, so that the codes it
+// carries do not outlive their use. An email with ExpiresAt, such as one
+// carrying a code, is given up on rather than sent once that time passes.
+type EmailDelivery struct {
+	Id            int64      `json:"-"`
+	ExternalId    string     `json:"id"`
+	Recipient     string     `json:"to"`
+	Template      string     `json:"template"`
+	Subject       string     `json:"subject"`
+	HTML          string     `json:"-"`
+	Text          string     `json:"-"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	Error         string     `json:"error,omitempty"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+	SentAt        *time.Time `json:"sentAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// EmailFilter narrows down the emails listed. Empty fields match every email.
+type EmailFilter struct {
+	Recipient string
+	Status    string
+	BeforeId  int64
+	Limit     int
+}
+
+// NewEmailDelivery queues an email to be sent right away.
+func NewEmailDelivery(recipient, template, subject, html, text string) *EmailDelivery {
+	now := time.Now().UTC()
+	return &EmailDelivery{
+		Recipient:     recipient,
+		Template:      template,
+		Subject:       subject,
+		HTML:          html,
+		Text:          text,
+		Status:        EmailQueued,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Succeed records that the email was handed to the provider.
+func (d *EmailDelivery) Succeed(now time.Time) {
+	d.Status = EmailSent
+	d.Error = ""
+	d.HTML = ""
+	d.Text = ""
+	d.SentAt = &now
+	d.UpdatedAt = now
+}
+
+// Fail records a failed attempt and either schedules a retry with an
+// exponential backoff or, once EmailMaxAttempts is reached, marks the email
+// as dead.
+func (d *EmailDelivery) Fail(err error, now time.Time) {
+	d.Error = err.Error()
+	d.UpdatedAt = now
+
+	if d.Attempts >= EmailMaxAttempts {
+		d.Status = EmailDead
+		d.HTML = ""
+		d.Text = ""
+		return
+	}
+
+	d.Status = EmailQueued
+	d.NextAttemptAt = now.Add(retryBackoff(d.Attempts, emailBackoff, emailMaxBackoff))
+}
+
+// Postpone puts the email back in the queue until the given time without
+// counting the attempt it was claimed for, such as when its recipient got
+// too many emails lately.
+func (d *EmailDelivery) Postpone(until, now time.Time) {
+	if d.Attempts > 0 {
+		d.Attempts--
+	}
+	d.Status = EmailQueued
+	d.NextAttemptAt = until
+	d.UpdatedAt = now
+}
+
+// Expired reports whether the email is of no use anymore at now.
+func (d *EmailDelivery) Expired(now time.Time) bool {
+	return d.ExpiresAt != nil && !now.Before(*d.ExpiresAt)
+}
+
+// Expire gives up on an email that expired while it waited in the queue.
+func (d *EmailDelivery) Expire(now time.Time) {
+	d.Status = EmailDead
+	d.Error = "expired before it could be sent"
+	d.HTML = ""
+	d.Text = ""
+	d.UpdatedAt = now
+}
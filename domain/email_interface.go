@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type EmailStorer interface {
+	// CreateEmailDelivery queues the email and fills in its ids.
+	CreateEmailDelivery(ctx context.Context, delivery *EmailDelivery) error
+	GetEmailDeliveryByExternalId(ctx context.Context, id string) (*EmailDelivery, error)
+	// GetEmailDeliveries returns the emails that match filter, newest first.
+	GetEmailDeliveries(ctx context.Context, filter EmailFilter) ([]*EmailDelivery, error)
+	// ClaimEmailDeliveries counts an attempt for up to limit queued emails
+	// that are due, and leases them until now plus lease so that no other
+	// replica sends them meanwhile. An email whose sender died is retried
+	// once the lease expires.
+	ClaimEmailDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*EmailDelivery, error)
+	UpdateEmailDelivery(ctx context.Context, delivery *EmailDelivery) error
+	// CountSentEmails counts the emails sent to recipient since the given
+	// time.
+	CountSentEmails(ctx context.Context, recipient string, since time.Time) (int, error)
+	// DeleteEmailDeliveries prunes the emails that were sent or given up on
+	// before.
+	DeleteEmailDeliveries(ctx context.Context, before time.Time) error
+}
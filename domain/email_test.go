@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailDeliveryFail(t *testing.T) {
+	now := time.Date(2026, 11, 10, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		purpose    string
+		attempts   int
+		wantStatus string
+		wantNext   time.Time
+		wantHTML   string
+	}{
+		{"should retry the first failure after the base backoff", 1, EmailQueued, now.Add(30 * time.Second), "<p>123456</p>"},
+		{"should double the backoff after every attempt", 4, EmailQueued, now.Add(4 * time.Minute), "<p>123456</p>"},
+		{"should cap the backoff", 9, EmailQueued, now.Add(time.Hour), "<p>123456</p>"},
+		{"should give up after the last attempt and drop the bodies", EmailMaxAttempts, EmailDead, time.Time{}, ""},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			d := &EmailDelivery{Attempts: tt.attempts, Status: EmailQueued, HTML: "<p>123456</p>"}
+			d.Fail(errors.New("provider unavailable"), now)
+
+			assert.Equal(t, tt.wantStatus, d.Status)
+			assert.Equal(t, tt.wantNext, d.NextAttemptAt)
+			assert.Equal(t, "provider unavailable", d.Error)
+			assert.Equal(t, tt.wantHTML, d.HTML)
+		})
+	}
+}
+
+func TestEmailDeliverySucceed(t *testing.T) {
+	now := time.Date(2026, 11, 10, 9, 0, 0, 0, time.UTC)
+
+	d := NewEmailDelivery("fern@example.com", "verification", "Confirm your email", "<p>123456</p>", "123456")
+	d.Attempts = 2
+	d.Error = "provider unavailable"
+	d.Succeed(now)
+
+	assert.Equal(t, EmailSent, d.Status)
+	assert.Equal(t, &now, d.SentAt)
+	assert.Empty(t, d.Error)
+	assert.Empty(t, d.HTML, "should drop the bodies once sent")
+	assert.Empty(t, d.Text, "should drop the bodies once sent")
+}
+
+func TestEmailDeliveryPostpone(t *testing.T) {
+	now := time.Date(2026, 11, 10, 9, 0, 0, 0, time.UTC)
+
+	d := &EmailDelivery{Attempts: 1, Status: EmailQueued}
+	d.Postpone(now.Add(time.Minute), now)
+
+	assert.Equal(t, EmailQueued, d.Status)
+	assert.Equal(t, now.Add(time.Minute), d.NextAttemptAt)
+	assert.Zero(t, d.Attempts, "should not count the attempt")
+}
+
+func TestEmailDeliveryExpire(t *testing.T) {
+	now := time.Date(2026, 11, 10, 9, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(15 * time.Minute)
+
+	cases := []struct {
+		purpose   string
+		expiresAt *time.Time
+		at        time.Time
+		want      bool
+	}{
+		{"should not expire an email without an expiry", nil, now.Add(time.Hour), false},
+		{"should not expire an email before its expiry", &expiresAt, now, false},
+		{"should expire an email at its expiry", &expiresAt, expiresAt, true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			d := &EmailDelivery{ExpiresAt: tt.expiresAt}
+			assert.Equal(t, tt.want, d.Expired(tt.at))
+		})
+	}
+
+	d := NewEmailDelivery("fern@example.com", "verification", "Confirm your email", "<p>123456</p>", "123456")
+	d.Expire(now)
+
+	assert.Equal(t, EmailDead, d.Status)
+	assert.NotEmpty(t, d.Error)
+	assert.Empty(t, d.HTML, "should drop the bodies of an expired email")
+	assert.Empty(t, d.Text, "should drop the bodies of an expired email")
+}
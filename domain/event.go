@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventUserRegistered            = "user.registered"
+	EventUserVerificationRequested = "user.verification_requested"
+	EventUserVerified              = "user.verified"
+	EventUserDeleted               = "user.deleted"
+	EventUserErased                = "user.erased"
+
+	EventPlantCreated = "plant.created"
+	EventPlantUpdated = "plant.updated"
+	EventPlantDeleted = "plant.deleted"
+
+	EventCareCreated   = "care.created"
+	EventCareUpdated   = "care.updated"
+	EventCareDeleted   = "care.deleted"
+	EventCareCompleted = "care.completed"
+	EventCareSkipped   = "care.skipped"
+	EventCareSnoozed   = "care.snoozed"
+	EventCareDue       = "care.due"
+)
+
+// careHistoryEvents maps the kind of the history entry saved along with a
+// care to the event it raises instead of care.updated.
+var careHistoryEvents = map[string]string{
+	CareHistoryCompleted: EventCareCompleted,
+	CareHistorySkipped:   EventCareSkipped,
+	CareHistorySnoozed:   EventCareSnoozed,
+}
+
+// Event is something that happened to the data of a user. Events are saved
+// to the outbox in the same transaction as the change that raised them and
+// then published to the subscribers, at least once, so subscribers use Id to
+// skip the events they have already handled.
+type Event struct {
+	Id          string          `json:"id"`
+	Type        string          `json:"type"`
+	AggregateId string          `json:"aggregateId"`
+	UserId      int64           `json:"userId"`
+	Payload     json.RawMessage `json:"payload"`
+	OccurredAt  time.Time       `json:"occurredAt"`
+}
+
+// NewEvent raises an event of the entity aggregateId, owned by userId, with
+// the JSON of payload.
+func NewEvent(eventType, aggregateId string, userId int64, payload any) (*Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		Id:          uuid.NewString(),
+		Type:        eventType,
+		AggregateId: aggregateId,
+		UserId:      userId,
+		Payload:     data,
+		OccurredAt:  time.Now().UTC(),
+	}, nil
+}
+
+// Decode unmarshals the payload of the event into v.
+func (e *Event) Decode(v any) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// UserEventPayload is the payload of the user events.
+type UserEventPayload struct {
+	User *User `json:"user"`
+}
+
+func NewUserEvent(eventType string, user *User) (*Event, error) {
+	return NewEvent(eventType, user.ExternalId, user.Id, UserEventPayload{User: user})
+}
+
+// NewUserErasedEvent raises user.erased with nothing but the ids of the
+// user, as the event outlives the data it reports the erasure of.
+func NewUserErasedEvent(user *User) (*Event, error) {
+	return NewEvent(EventUserErased, user.ExternalId, user.Id, struct{}{})
+}
+
+// PlantEventPayload is the payload of the plant events.
+type PlantEventPayload struct {
+	Plant *Plant `json:"plant"`
+}
+
+func NewPlantEvent(eventType string, plant *Plant) (*Event, error) {
+	return NewEvent(eventType, strconv.FormatInt(plant.Id, 10), plant.UserId, PlantEventPayload{Plant: plant})
+}
+
+// CareEventPayload is the payload of the care events. History is the entry
+// saved along with the change, for the completed, skipped and snoozed
+// events.
+type CareEventPayload struct {
+	Care    *Care             `json:"care"`
+	History *CareHistoryEntry `json:"history,omitempty"`
+}
+
+func NewCareEvent(eventType string, care *Care, entry *CareHistoryEntry) (*Event, error) {
+	return NewEvent(eventType, strconv.FormatInt(care.Id, 10), care.UserId, CareEventPayload{Care: care, History: entry})
+}
+
+// NewCareDueEvent raises care.due for the next occurrence of care. Its id is
+// derived from the care and its due time, so raising it again for the same
+// occurrence, e.g. from another replica, yields the same event.
+func NewCareDueEvent(care *Care) (*Event, error) {
+	event, err := NewCareEvent(EventCareDue, care, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s:%d:%d", EventCareDue, care.Id, care.NextCare.Unix())
+	event.Id = uuid.NewSHA1(uuid.NameSpaceOID, []byte(name)).String()
+	return event, nil
+}
+
+// NewCareUpdateEvents raises the events of saving care along with history:
+// one completed, skipped or snoozed event per entry of those kinds, or
+// care.updated when there is none.
+func NewCareUpdateEvents(care *Care, history ...*CareHistoryEntry) ([]*Event, error) {
+	var events []*Event
+	for _, entry := range history {
+		eventType, ok := careHistoryEvents[entry.Kind]
+		if !ok {
+			continue
+		}
+
+		event, err := NewCareEvent(eventType, care, entry)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if len(events) > 0 {
+		return events, nil
+	}
+
+	event, err := NewCareEvent(EventCareUpdated, care, nil)
+	if err != nil {
+		return nil, err
+	}
+	return []*Event{event}, nil
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+const (
+	JobKindExport = "export"
+	JobKindErase  = "erase"
+
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusDone      = "done"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+	JobStatusExpired   = "expired"
+)
+
+// EraseGracePeriod is how long an erase request waits before the account is
+// permanently removed, giving the user a chance to cancel it.
+var EraseGracePeriod = 7 * 24 * time.Hour
+
+// JobMaxAttempts is how many times a failing job is retried before it is
+// marked as failed.
+const JobMaxAttempts = 3
+
+type Job struct {
+	Id             int64      `json:"-"`
+	ExternalId     string     `json:"id"`
+	UserId         int64      `json:"-"`
+	UserExternalId string     `json:"-"`
+	Kind           string     `json:"kind"`
+	Status         string     `json:"status"`
+	RunAfter       time.Time  `json:"runAfter"`
+	Attempts       int        `json:"attempts"`
+	Result         string     `json:"-"`
+	Error          string     `json:"error,omitempty"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+func NewExportJob(user *User) *Job {
+	return newJob(user, JobKindExport, time.Now())
+}
+
+func NewEraseJob(user *User) *Job {
+	return newJob(user, JobKindErase, time.Now().Add(EraseGracePeriod))
+}
+
+func newJob(user *User, kind string, runAfter time.Time) *Job {
+	return &Job{
+		UserId:         user.Id,
+		UserExternalId: user.ExternalId,
+		Kind:           kind,
+		Status:         JobStatusPending,
+		RunAfter:       runAfter,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+}
+
+func (j *Job) Cancel() error {
+	if j.Status != JobStatusPending {
+		return errs.ErrJobNotCancellable
+	}
+
+	j.Status = JobStatusCancelled
+	j.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (j *Job) Finish(result string, expiresAt *time.Time) {
+	j.Status = JobStatusDone
+	j.Result = result
+	j.Error = ""
+	j.ExpiresAt = expiresAt
+	j.UpdatedAt = time.Now()
+}
+
+// Fail records the error and either puts the job back in the queue with a
+// linear backoff or, once JobMaxAttempts is reached, marks it as failed.
+func (j *Job) Fail(err error) {
+	j.Error = err.Error()
+	j.UpdatedAt = time.Now()
+
+	if j.Attempts >= JobMaxAttempts {
+		j.Status = JobStatusFailed
+		return
+	}
+
+	j.Status = JobStatusPending
+	j.RunAfter = time.Now().Add(time.Duration(j.Attempts) * time.Minute)
+}
+
+// Defer puts a claimed job back in the queue until runAfter without counting
+// the claim as an attempt.
+func (j *Job) Defer(runAfter time.Time) {
+	j.Status = JobStatusPending
+	j.RunAfter = runAfter
+	j.Attempts--
+	j.UpdatedAt = time.Now()
+}
+
+func (j *Job) Expire() {
+	j.Status = JobStatusExpired
+	j.Result = ""
+	j.UpdatedAt = time.Now()
+}
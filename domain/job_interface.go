@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type JobStorer interface {
+	CreateJob(ctx context.Context, job *Job) (string, error)
+	GetJobByExternalId(ctx context.Context, id string) (*Job, error)
+	GetPendingJobByUser(ctx context.Context, userExternalId, kind string) (*Job, error)
+	GetJobsByUserID(ctx context.Context, userId int64) ([]*Job, error)
+	// ClaimPendingJobs hands out the due pending jobs, and the running jobs
+	// whose lease expired because their runner died, running them until
+	// now plus lease.
+	ClaimPendingJobs(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Job, error)
+	GetExpiredJobs(ctx context.Context, now time.Time) ([]*Job, error)
+	UpdateJob(ctx context.Context, job *Job) error
+}
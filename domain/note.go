@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// MaxNoteLength bounds the size of a plant notes document, in bytes.
+const MaxNoteLength = 100000
+
+// NoteRevision is one saved version of the Markdown notes of a plant.
+// Revisions are numbered from 1 per plant and never change once stored; the
+// latest one is the current document.
+type NoteRevision struct {
+	Id           int64     `json:"id"`
+	PlantId      int64     `json:"plantId"`
+	UserId       int64     `json:"-"`
+	Revision     int       `json:"revision"`
+	Body         string    `json:"body"`
+	RestoredFrom int       `json:"restoredFrom,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// NewNoteRevision creates the revision that follows current, which is nil
+// for the first notes of a plant. Saving the same body again is rejected.
+func NewNoteRevision(plantId, userId int64, body string, current *NoteRevision) (*NoteRevision, error) {
+	if len(body) > MaxNoteLength {
+		return nil, errs.ErrInvalidNote
+	}
+
+	revision := 1
+	if current != nil {
+		if current.Body == body {
+			return nil, errs.ErrNoteUnchanged
+		}
+		revision = current.Revision + 1
+	}
+
+	return &NoteRevision{
+		PlantId:   plantId,
+		UserId:    userId,
+		Revision:  revision,
+		Body:      body,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// Restore creates a revision following current with the body of r.
+func (r *NoteRevision) Restore(userId int64, current *NoteRevision) (*NoteRevision, error) {
+	restored, err := NewNoteRevision(r.PlantId, userId, r.Body, current)
+	if err != nil {
+		return nil, err
+	}
+
+	restored.RestoredFrom = r.Revision
+	return restored, nil
+}
+
+// Diff returns a unified diff of the body of r against the body of to.
+func (r *NoteRevision) Diff(to *NoteRevision) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        noteLines(r.Body),
+		B:        noteLines(to.Body),
+		FromFile: "revision " + strconv.Itoa(r.Revision),
+		ToFile:   "revision " + strconv.Itoa(to.Revision),
+		Context:  3,
+	})
+}
+
+// noteLines splits body into lines that all end with a newline, as the diff
+// expects.
+func noteLines(body string) []string {
+	if body == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(body, "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n"
+	}
+	return lines
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "context"
+
+type NoteStorer interface {
+	// CreateNoteRevision fails with errs.ErrNoteConflict when the plant
+	// already has a revision with the same number.
+	CreateNoteRevision(ctx context.Context, revision *NoteRevision) (int64, error)
+	GetLatestNoteRevision(ctx context.Context, plantId int64) (*NoteRevision, error)
+	GetNoteRevision(ctx context.Context, plantId int64, revision int) (*NoteRevision, error)
+	GetNoteRevisions(ctx context.Context, plantId int64) ([]*NoteRevision, error)
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoteRevisions(t *testing.T) {
+	first, err := NewNoteRevision(1, 1, "Water weekly\n", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, first.Revision)
+
+	second, err := NewNoteRevision(1, 1, "Water every 10 days\n", first)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, second.Revision)
+
+	_, err = NewNoteRevision(1, 1, "Water every 10 days\n", second)
+	assert.ErrorIs(t, err, errs.ErrNoteUnchanged)
+
+	restored, err := first.Restore(2, second)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, restored.Revision)
+	assert.Equal(t, 1, restored.RestoredFrom)
+	assert.Equal(t, first.Body, restored.Body)
+
+	diff, err := first.Diff(second)
+	assert.NoError(t, err)
+	assert.Equal(t, "--- revision 1\n+++ revision 2\n@@ -1 +1 @@\n-Water weekly\n+Water every 10 days\n", diff)
+}
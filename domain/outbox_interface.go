@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type OutboxStorer interface {
+	// CreateOutboxEvents saves events that are not tied to a change of the
+	// database, e.g. a request to send the verification email again. Events
+	// whose id is already in the outbox are skipped.
+	CreateOutboxEvents(ctx context.Context, events ...*Event) error
+	// PublishOutboxEvents passes up to limit unpublished events, oldest
+	// first, to publish and marks them as published if it succeeds. Rows
+	// are locked meanwhile, so replicas publish different events. It
+	// returns how many events were published.
+	PublishOutboxEvents(ctx context.Context, limit int, publish func(ctx context.Context, events []*Event) error) (int, error)
+	// DeletePublishedOutboxEvents prunes the events published before.
+	DeletePublishedOutboxEvents(ctx context.Context, before time.Time) error
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mergepatch"
+	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
+)
+
+// patchReader decodes the members of a merge patch one by one, collecting an
+// error for each field that cannot be applied.
+type patchReader struct {
+	doc    mergepatch.Document
+	known  map[string]bool
+	set    map[string]bool
+	errors errs.FieldErrors
+}
+
+func newPatchReader(doc mergepatch.Document) *patchReader {
+	return &patchReader{doc: doc, known: make(map[string]bool), set: make(map[string]bool)}
+}
+
+// read decodes field into v when it was sent. It reports whether the field
+// was sent with a usable value, and whether that value is null, which is
+// only accepted for nullable fields.
+func (r *patchReader) read(field string, v any, nullable bool) (sent, null bool) {
+	r.known[field] = true
+
+	if !r.doc.Has(field) {
+		return false, false
+	}
+
+	if r.doc.IsNull(field) {
+		if !nullable {
+			r.errors.Add(field, errs.ErrFieldNotNullable)
+			return false, false
+		}
+		return true, true
+	}
+
+	if err := r.doc.Decode(field, v); err != nil {
+		r.errors.Add(field, errs.ErrFieldInvalidType)
+		return false, false
+	}
+
+	r.set[field] = true
+	return true, false
+}
+
+// check records err, if any, against field.
+func (r *patchReader) check(field string, err error) {
+	if err != nil {
+		r.errors.Add(field, err)
+	}
+}
+
+// validate checks v, the patched copy of an entity, against its validate
+// tags. Only the fields sent with a value are checked: the others kept their
+// current value or were cleared.
+func (r *patchReader) validate(v any) {
+	var fields errs.FieldErrors
+	fields.Merge("", validate.Struct(v))
+
+	for _, f := range fields {
+		root, _, _ := strings.Cut(f.Field, ".")
+		if r.set[root] {
+			r.errors = append(r.errors, f)
+		}
+	}
+}
+
+// err reports every field error, including the fields sent that cannot be
+// patched, sorted by field.
+func (r *patchReader) err() error {
+	for _, field := range r.doc.Fields() {
+		if !r.known[field] {
+			r.errors.Add(field, errs.ErrFieldReadOnly)
+		}
+	}
+
+	sort.SliceStable(r.errors, func(i, j int) bool { return r.errors[i].Field < r.errors[j].Field })
+	return r.errors.Err()
+}
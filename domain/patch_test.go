@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mergepatch"
+	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlantPatch(t *testing.T) {
+	acquired := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		purpose    string
+		patch      string
+		wantFields []string
+		check      func(t *testing.T, p *Plant)
+	}{
+		{
+			"should only change the fields sent",
+			`{"name":"Monstera"}`,
+			nil,
+			func(t *testing.T, p *Plant) {
+				assert.Equal(t, "Monstera", p.Name)
+				assert.Equal(t, "Living room", p.Location)
+				assert.Equal(t, acquired, p.AcquisitionDate)
+				assert.Equal(t, 7, p.CareFrequency)
+			},
+		},
+		{
+			"should clear nullable fields sent as null",
+			`{"species":null}`,
+			nil,
+			func(t *testing.T, p *Plant) {
+				assert.Empty(t, p.Species)
+				assert.Equal(t, "Fern", p.Name)
+			},
+		},
+		{
+			"should report every invalid field and leave the plant unchanged",
+			`{"name":"x","location":null,"careFrequency":"7","id":3}`,
+			[]string{"careFrequency", "id", "location", "name"},
+			func(t *testing.T, p *Plant) {
+				assert.Equal(t, "Fern", p.Name)
+				assert.Equal(t, int64(1), p.Id)
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			p := &Plant{Id: 1, Name: "Fern", Species: "Nephrolepis", Location: "Living room", AcquisitionDate: acquired, CareFrequency: 7}
+
+			doc, err := mergepatch.Parse([]byte(tt.patch))
+			assert.NoError(t, err)
+
+			err = p.Patch(doc)
+			if tt.wantFields == nil {
+				assert.NoError(t, err)
+			} else {
+				var fields errs.FieldErrors
+				assert.ErrorAs(t, err, &fields)
+
+				got := make([]string, 0, len(fields))
+				for _, f := range fields {
+					got = append(got, f.Field)
+				}
+				assert.Equal(t, tt.wantFields, got)
+			}
+			tt.check(t, p)
+		})
+	}
+}
+
+func TestCarePatch(t *testing.T) {
+	lastCare := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+	nextCare := time.Date(2026, 11, 9, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		purpose    string
+		patch      string
+		wantFields []string
+		check      func(t *testing.T, c *Care)
+	}{
+		{
+			"should keep the schedule when only the name is sent",
+			`{"name":"Mist"}`,
+			nil,
+			func(t *testing.T, c *Care) {
+				assert.Equal(t, "Mist", c.Name)
+				assert.Equal(t, nextCare, c.NextCare)
+				assert.NotNil(t, c.SnoozedFrom)
+			},
+		},
+		{
+			"should clear notes and the interval sent as null",
+			`{"notes":null,"interval":null}`,
+			nil,
+			func(t *testing.T, c *Care) {
+				assert.Empty(t, c.Notes)
+				assert.Zero(t, c.Interval)
+				assert.NoError(t, validate.Struct(c), "a care without notes should stay valid")
+			},
+		},
+		{
+			"should reject notes that are too short",
+			`{"notes":"ok"}`,
+			[]string{"notes"},
+			func(t *testing.T, c *Care) {
+				assert.Equal(t, "Rain water", c.Notes)
+			},
+		},
+		{
+			"should reschedule from a new rule and drop the snooze",
+			`{"rrule":"FREQ=WEEKLY;BYDAY=TH"}`,
+			nil,
+			func(t *testing.T, c *Care) {
+				assert.NotNil(t, c.Recurrence)
+				assert.Equal(t, time.Date(2026, 11, 12, 9, 0, 0, 0, time.UTC), c.NextCare)
+				assert.Nil(t, c.SnoozedFrom)
+			},
+		},
+		{
+			"should reject a next care before the last one",
+			`{"nextCare":"2026-11-01T09:00:00Z"}`,
+			[]string{"nextCare"},
+			func(t *testing.T, c *Care) {
+				assert.Equal(t, nextCare, c.NextCare)
+			},
+		},
+		{
+			"should reject null on fields that are not nullable",
+			`{"name":null,"plantId":null,"timezone":"Europe/Lisbon"}`,
+			[]string{"name", "plantId", "rrule"},
+			func(t *testing.T, c *Care) {
+				assert.Equal(t, "Water", c.Name)
+				assert.Nil(t, c.Recurrence)
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			snoozedFrom := time.Date(2026, 11, 7, 9, 0, 0, 0, time.UTC)
+			c := &Care{Id: 1, PlantId: 1, Name: "Water", Notes: "Rain water", Interval: 7,
+				LastCare: lastCare, NextCare: nextCare, SnoozedFrom: &snoozedFrom}
+
+			doc, err := mergepatch.Parse([]byte(tt.patch))
+			assert.NoError(t, err)
+
+			err = c.Patch(doc)
+			if tt.wantFields == nil {
+				assert.NoError(t, err)
+			} else {
+				var fields errs.FieldErrors
+				assert.ErrorAs(t, err, &fields)
+
+				got := make([]string, 0, len(fields))
+				for _, f := range fields {
+					got = append(got, f.Field)
+				}
+				assert.Equal(t, tt.wantFields, got)
+			}
+			tt.check(t, c)
+		})
+	}
+}
@@ -4,61 +4,161 @@ import (
 	"time"
 
 	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mergepatch"
+	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
 )
 
-type Plant struct {
-	Id               int64               `json:"id"`
-	Name             string              `json:"name"`
-	AcquisitionDate  time.Time           `json:"acquisitionDate"`
-	Location         string              `json:"location"`
-	CareFrequency    int                 `json:"careFrequency"`
-	UserId           int64               `json:"userId"`
-	CreatedAt        time.Time           `json:"createdAt"`
-	UpdatedAt        time.Time           `json:"updatedAt"`
-}
-
-func NewPlant(name, location string, acquisitionDate time.Time, careFrequency int, userId int64) (*Plant, error) {
-	if len(name) < 3 || len(name) > 100 {
-		return nil, errs.ErrInvalidPlantName
-	}
+const (
+	PlantStatusActive    = "active"
+	PlantStatusArchived  = "archived"
+	PlantStatusDeceased  = "deceased"
+	PlantStatusGivenAway = "given_away"
+)
 
-	if len(location) < 3 || len(location) > 100 {
-		return nil, errs.ErrInvalidPlantLocation
-	}
+// plantTransitions lists the statuses a plant may move to from each status.
+// Deceased and given away plants are final.
+var plantTransitions = map[string][]string{
+	PlantStatusActive:    {PlantStatusArchived, PlantStatusDeceased, PlantStatusGivenAway},
+	PlantStatusArchived:  {PlantStatusActive, PlantStatusDeceased, PlantStatusGivenAway},
+	PlantStatusDeceased:  {},
+	PlantStatusGivenAway: {},
+}
 
-	if careFrequency < 1 || careFrequency > 365 {
-		return nil, errs.ErrInvalidPlantCareFrequency
-	}
+type Plant struct {
+	Id              int64      `json:"id"`
+	Name            string     `json:"name" validate:"min=3,max=100"`
+	Species         string     `json:"species" validate:"max=100"`
+	AcquisitionDate time.Time  `json:"acquisitionDate"`
+	Location        string     `json:"location" validate:"min=3,max=100"`
+	CareFrequency   int        `json:"careFrequency" validate:"min=1,max=365"`
+	UserId          int64      `json:"userId"`
+	Status          string     `json:"status"`
+	StatusReason    string     `json:"statusReason,omitempty"`
+	StatusChangedAt *time.Time `json:"statusChangedAt,omitempty"`
+	Version         int        `json:"version"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
 
-	return &Plant{
+// NewPlant creates an active plant. Every invalid field is reported in the
+// returned errs.FieldErrors.
+func NewPlant(name, species, location string, acquisitionDate time.Time, careFrequency int, userId int64) (*Plant, error) {
+	plant := &Plant{
 		Name:            name,
+		Species:         species,
 		Location:        location,
 		AcquisitionDate: acquisitionDate,
 		CareFrequency:   careFrequency,
 		UserId:          userId,
+		Status:          PlantStatusActive,
+		Version:         1,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
-	}, nil
+	}
+
+	if err := validate.Struct(plant); err != nil {
+		return nil, err
+	}
+
+	return plant, nil
 }
 
-func (p *Plant) Update(name, location string, acquisitionDate time.Time, careFrequency int) error {
-	if len(name) < 3 || len(name) > 100 {
-		return errs.ErrInvalidPlantName
+// Patch applies a JSON Merge Patch to the editable fields of the plant: name,
+// species, location, acquisitionDate and careFrequency. Only the fields sent
+// are validated and every invalid one is reported in the returned
+// errs.FieldErrors, in which case the plant is left unchanged. Species is
+// the only field that can be cleared with null.
+func (p *Plant) Patch(doc mergepatch.Document) error {
+	r := newPatchReader(doc)
+
+	patched := *p
+	r.read("name", &patched.Name, false)
+	if _, null := r.read("species", &patched.Species, true); null {
+		patched.Species = ""
 	}
+	r.read("location", &patched.Location, false)
+	r.read("acquisitionDate", &patched.AcquisitionDate, false)
+	r.read("careFrequency", &patched.CareFrequency, false)
+	r.validate(&patched)
 
-	if len(location) < 3 || len(location) > 100 {
-		return errs.ErrInvalidPlantLocation
+	if err := r.err(); err != nil {
+		return err
 	}
 
-	if careFrequency < 1 || careFrequency > 365 {
-		return errs.ErrInvalidPlantCareFrequency
+	patched.UpdatedAt = time.Now()
+	*p = patched
+
+	return nil
+}
+
+// ChangeStatus moves the plant along its lifecycle. The date is when the
+// change happened, e.g. when the plant died, and cannot be in the future.
+func (p *Plant) ChangeStatus(status, reason string, at time.Time) error {
+	allowed, ok := plantTransitions[p.Status]
+	if !ok {
+		return errs.ErrInvalidPlantStatus
 	}
 
-	p.Name = name
-	p.Location = location
-	p.AcquisitionDate = acquisitionDate
-	p.CareFrequency = careFrequency
+	if _, ok := plantTransitions[status]; !ok {
+		return errs.ErrInvalidPlantStatus
+	}
+
+	valid := false
+	for _, s := range allowed {
+		if s == status {
+			valid = true
+		}
+	}
+	if !valid {
+		return errs.ErrInvalidPlantTransition
+	}
+
+	if len(reason) > 1000 {
+		return errs.ErrInvalidPlantStatusReason
+	}
+
+	if at.After(time.Now()) {
+		return errs.ErrInvalidPlantStatusDate
+	}
+
+	p.Status = status
+	p.StatusReason = reason
+	p.StatusChangedAt = &at
 	p.UpdatedAt = time.Now()
 
 	return nil
-}
\ No newline at end of file
+}
+
+// IsActive reports whether the plant still shows up in listings and care
+// schedules by default.
+func (p *Plant) IsActive() bool {
+	return p.Status == PlantStatusActive
+}
+
+// IsValidPlantStatus reports whether status is a known lifecycle status.
+func IsValidPlantStatus(status string) bool {
+	_, ok := plantTransitions[status]
+	return ok
+}
+
+// PlantSurvival summarizes how the plants of a species or location fared.
+type PlantSurvival struct {
+	Group        string  `json:"group"`
+	Total        int     `json:"total"`
+	Active       int     `json:"active"`
+	Archived     int     `json:"archived"`
+	Deceased     int     `json:"deceased"`
+	GivenAway    int     `json:"givenAway"`
+	SurvivalRate float64 `json:"survivalRate"`
+}
+
+// ComputeSurvivalRate sets the share of plants that did not die. Plants that
+// were given away count as survivors.
+func (s *PlantSurvival) ComputeSurvivalRate() {
+	if s.Total == 0 {
+		s.SurvivalRate = 0
+		return
+	}
+
+	s.SurvivalRate = float64(s.Total-s.Deceased) / float64(s.Total)
+}
@@ -5,7 +5,17 @@ import "context"
 type PlantStorer interface {
 	CreatePlant(ctx context.Context, plant *Plant) (int64, error)
 	GetPlantByID(ctx context.Context, id int64) (*Plant, error)
-	GetPlantsByUserID(ctx context.Context, userID int64) ([]*Plant, error)
+	// GetPlantsByIDs returns the plants of the given ids that were not
+	// deleted, in no particular order.
+	GetPlantsByIDs(ctx context.Context, ids []int64) ([]*Plant, error)
+	// GetPlantsByUserID returns the user plants in any of the given statuses,
+	// or every plant when no status is given.
+	GetPlantsByUserID(ctx context.Context, userID int64, statuses ...string) ([]*Plant, error)
+	// UpdatePlant fails with errs.ErrVersionConflict when the stored plant is
+	// no longer at plant.Version.
 	UpdatePlant(ctx context.Context, plant *Plant) error
-	DeletePlant(ctx context.Context, id int64) error
-}
\ No newline at end of file
+	// DeletePlant checks version the same way, unless it is 0.
+	DeletePlant(ctx context.Context, id int64, version int) error
+	// GetPlantSurvival groups the user plants by "species" or "location".
+	GetPlantSurvival(ctx context.Context, userID int64, groupBy string) ([]*PlantSurvival, error)
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPlant(t *testing.T) {
+	acquired := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		purpose       string
+		name          string
+		location      string
+		careFrequency int
+		wantFields    []string
+		wantRules     []string
+	}{
+		{
+			"should create an active plant",
+			"Fern",
+			"Living room",
+			7,
+			nil,
+			nil,
+		},
+		{
+			"should report every invalid field at once",
+			"x",
+			"",
+			400,
+			[]string{"name", "location", "careFrequency"},
+			[]string{"min", "min", "max"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			plant, err := NewPlant(tt.name, "", tt.location, acquired, tt.careFrequency, 1)
+			if tt.wantFields == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, PlantStatusActive, plant.Status)
+				return
+			}
+
+			var fields errs.FieldErrors
+			assert.ErrorAs(t, err, &fields)
+
+			var gotFields, gotRules []string
+			for _, f := range fields {
+				gotFields = append(gotFields, f.Field)
+				gotRules = append(gotRules, f.Rule)
+			}
+			assert.Equal(t, tt.wantFields, gotFields)
+			assert.Equal(t, tt.wantRules, gotRules)
+		})
+	}
+}
+
+func TestPlantChangeStatus(t *testing.T) {
+	now := time.Now()
+	at := now.Add(-time.Hour)
+
+	cases := []struct {
+		purpose    string
+		from       string
+		to         string
+		reason     string
+		at         time.Time
+		wantErr    error
+		wantStatus string
+	}{
+		{"should archive an active plant", PlantStatusActive, PlantStatusArchived, "moved to storage", at, nil, PlantStatusArchived},
+		{"should record the death of an active plant", PlantStatusActive, PlantStatusDeceased, "root rot", at, nil, PlantStatusDeceased},
+		{"should give away an active plant", PlantStatusActive, PlantStatusGivenAway, "", at, nil, PlantStatusGivenAway},
+		{"should reactivate an archived plant", PlantStatusArchived, PlantStatusActive, "", at, nil, PlantStatusActive},
+		{"should record the death of an archived plant", PlantStatusArchived, PlantStatusDeceased, "", at, nil, PlantStatusDeceased},
+		{"should not revive a deceased plant", PlantStatusDeceased, PlantStatusActive, "", at, errs.ErrInvalidPlantTransition, PlantStatusDeceased},
+		{"should not take back a plant given away", PlantStatusGivenAway, PlantStatusArchived, "", at, errs.ErrInvalidPlantTransition, PlantStatusGivenAway},
+		{"should not move a plant to its own status", PlantStatusActive, PlantStatusActive, "", at, errs.ErrInvalidPlantTransition, PlantStatusActive},
+		{"should reject an unknown status", PlantStatusActive, "sold", "", at, errs.ErrInvalidPlantStatus, PlantStatusActive},
+		{"should reject a plant with an unknown status", "sold", PlantStatusActive, "", at, errs.ErrInvalidPlantStatus, "sold"},
+		{"should reject a long reason", PlantStatusActive, PlantStatusArchived, strings.Repeat("a", 1001), at, errs.ErrInvalidPlantStatusReason, PlantStatusActive},
+		{"should reject a date in the future", PlantStatusActive, PlantStatusDeceased, "", now.Add(time.Hour), errs.ErrInvalidPlantStatusDate, PlantStatusActive},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			plant := &Plant{Status: tt.from}
+
+			err := plant.ChangeStatus(tt.to, tt.reason, tt.at)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantStatus, plant.Status)
+			if err != nil {
+				assert.Nil(t, plant.StatusChangedAt)
+				return
+			}
+
+			assert.Equal(t, tt.reason, plant.StatusReason)
+			assert.Equal(t, &tt.at, plant.StatusChangedAt)
+			assert.Equal(t, tt.to == PlantStatusActive, plant.IsActive())
+		})
+	}
+}
+
+func TestPlantSurvivalRate(t *testing.T) {
+	cases := []struct {
+		purpose  string
+		survival PlantSurvival
+		want     float64
+	}{
+		{"should count plants given away as survivors", PlantSurvival{Total: 4, Deceased: 1, GivenAway: 1}, 0.75},
+		{"should be zero without plants", PlantSurvival{}, 0},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			tt.survival.ComputeSurvivalRate()
+			assert.Equal(t, tt.want, tt.survival.SurvivalRate)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/i18n"
+	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
+)
+
+const DefaultTimezone = "UTC"
+const DefaultLocale = i18n.Default
+
+var SupportedLocales = i18n.Supported
+
+func init() {
+	validate.Register("locale", i18n.IsSupported)
+}
+
+// QuietHours is a daily window, in the user's timezone, during which no
+// notification is delivered. Start may be after End, meaning the window
+// spans midnight.
+type QuietHours struct {
+	Start string `json:"start" validate:"datetime=15:04"`
+	End   string `json:"end" validate:"datetime=15:04,nefield=Start"`
+}
+
+func NewQuietHours(start, end string) (*QuietHours, error) {
+	q := &QuietHours{Start: start, End: end}
+	if err := validate.Struct(q); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// QuietHoursFromMinutes builds quiet hours from minutes since midnight, as
+// they are stored in the database.
+func QuietHoursFromMinutes(start, end int) *QuietHours {
+	return &QuietHours{
+		Start: fmt.Sprintf("%02d:%02d", start/60, start%60),
+		End:   fmt.Sprintf("%02d:%02d", end/60, end%60),
+	}
+}
+
+// Minutes returns the window bounds as minutes since midnight.
+func (q *QuietHours) Minutes() (int, int) {
+	s, _ := parseClock(q.Start)
+	e, _ := parseClock(q.End)
+	return s, e
+}
+
+// Contains reports whether t, already converted to the user's timezone,
+// falls inside the window.
+func (q *QuietHours) Contains(t time.Time) bool {
+	start, end := q.Minutes()
+	m := t.Hour()*60 + t.Minute()
+
+	if start < end {
+		return m >= start && m < end
+	}
+
+	return m >= start || m < end
+}
+
+// end returns the first instant at or after t that is outside the window.
+func (q *QuietHours) end(t time.Time) time.Time {
+	_, end := q.Minutes()
+
+	next := time.Date(t.Year(), t.Month(), t.Day(), end/60, end%60, 0, 0, t.Location())
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return next
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, errs.ErrInvalidQuietHours
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// preferences holds the rules of the user preferences.
+type preferences struct {
+	Timezone   string      `json:"timezone" validate:"timezone"`
+	Locale     string      `json:"locale" validate:"locale"`
+	QuietHours *QuietHours `json:"quietHours"`
+}
+
+// UpdatePreferences replaces the user preferences. Every invalid field is
+// reported in the returned errs.FieldErrors, in which case the user is left
+// unchanged.
+func (u *User) UpdatePreferences(timezone, locale string, quietHours *QuietHours) error {
+	if quietHours != nil {
+		q := *quietHours
+		quietHours = &q
+	}
+
+	if err := validate.Struct(preferences{timezone, locale, quietHours}); err != nil {
+		return err
+	}
+
+	u.Timezone = timezone
+	u.Locale = locale
+	u.QuietHours = quietHours
+
+	return nil
+}
+
+// Location returns the user's timezone, falling back to UTC when it is
+// unset or unknown.
+func (u *User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// Today returns the bounds of the user's current calendar day.
+func (u *User) Today(now time.Time) (time.Time, time.Time) {
+	start := StartOfDay(now, u.Location())
+	return start, start.AddDate(0, 0, 1)
+}
+
+// MaxNotificationDelay bounds how long NextDeliveryTime may push a
+// notification back, as quiet hours never last a whole day.
+const MaxNotificationDelay = 24 * time.Hour
+
+// NextDeliveryTime returns when a notification due at t may be delivered,
+// pushing it to the end of the user's quiet hours if needed.
+func (u *User) NextDeliveryTime(t time.Time) time.Time {
+	if u.QuietHours == nil {
+		return t
+	}
+
+	local := t.In(u.Location())
+	if !u.QuietHours.Contains(local) {
+		return t
+	}
+
+	return u.QuietHours.end(local)
+}
+
+// StartOfDay returns midnight of the day t falls on in loc.
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextDeliveryTime(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	assert.NoError(t, err)
+
+	cases := []struct {
+		purpose string
+		user    *User
+		at      time.Time
+		want    time.Time
+	}{
+		{
+			"should deliver right away without quiet hours",
+			&User{Timezone: "America/Sao_Paulo"},
+			time.Date(2026, 11, 1, 2, 0, 0, 0, saoPaulo),
+			time.Date(2026, 11, 1, 2, 0, 0, 0, saoPaulo),
+		},
+		{
+			"should deliver right away outside quiet hours",
+			&User{Timezone: "America/Sao_Paulo", QuietHours: &QuietHours{Start: "22:00", End: "07:00"}},
+			time.Date(2026, 11, 1, 12, 0, 0, 0, saoPaulo),
+			time.Date(2026, 11, 1, 12, 0, 0, 0, saoPaulo),
+		},
+		{
+			"should wait until the morning when the window spans midnight",
+			&User{Timezone: "America/Sao_Paulo", QuietHours: &QuietHours{Start: "22:00", End: "07:00"}},
+			time.Date(2026, 11, 1, 23, 30, 0, 0, saoPaulo),
+			time.Date(2026, 11, 2, 7, 0, 0, 0, saoPaulo),
+		},
+		{
+			"should use the user's timezone rather than the instant's",
+			&User{Timezone: "America/Sao_Paulo", QuietHours: &QuietHours{Start: "22:00", End: "07:00"}},
+			time.Date(2026, 11, 1, 4, 0, 0, 0, time.UTC),
+			time.Date(2026, 11, 1, 7, 0, 0, 0, saoPaulo),
+		},
+		{
+			"should wait until the end of a same-day window",
+			&User{Timezone: "UTC", QuietHours: &QuietHours{Start: "13:00", End: "15:00"}},
+			time.Date(2026, 11, 1, 14, 0, 0, 0, time.UTC),
+			time.Date(2026, 11, 1, 15, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			got := tt.user.NextDeliveryTime(tt.at)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	cases := []struct {
+		purpose    string
+		timezone   string
+		locale     string
+		quietHours *QuietHours
+		wantFields []string
+	}{
+		{
+			"should update the preferences",
+			"America/Sao_Paulo",
+			"pt_BR",
+			&QuietHours{Start: "22:00", End: "07:00"},
+			nil,
+		},
+		{
+			"should report every invalid preference",
+			"Mars/Olympus",
+			"fr",
+			&QuietHours{Start: "22:00", End: "25:00"},
+			[]string{"timezone", "locale", "quietHours.end"},
+		},
+		{
+			"should reject an empty quiet hours window",
+			"UTC",
+			"en",
+			&QuietHours{Start: "22:00", End: "22:00"},
+			[]string{"quietHours.end"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			u := &User{Timezone: DefaultTimezone, Locale: DefaultLocale}
+
+			err := u.UpdatePreferences(tt.timezone, tt.locale, tt.quietHours)
+			if tt.wantFields == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.timezone, u.Timezone)
+				assert.Equal(t, tt.quietHours, u.QuietHours)
+				return
+			}
+
+			var fields errs.FieldErrors
+			assert.ErrorAs(t, err, &fields)
+
+			got := make([]string, 0, len(fields))
+			for _, f := range fields {
+				got = append(got, f.Field)
+			}
+			assert.Equal(t, tt.wantFields, got)
+			assert.Equal(t, DefaultTimezone, u.Timezone)
+		})
+	}
+}
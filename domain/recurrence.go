@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/pkg/rrule"
+	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
+)
+
+// Recurrence is an RFC 5545 RRULE anchored at Start and expanded in
+// Timezone, so that "every Monday at 9:00" stays at 9:00 local time across
+// daylight saving changes. ExDates are occurrences that were skipped.
+type Recurrence struct {
+	RRule    string      `json:"rrule" validate:"rrule"`
+	Start    time.Time   `json:"start"`
+	Timezone string      `json:"timezone" validate:"timezone"`
+	ExDates  []time.Time `json:"exdates,omitempty"`
+
+	rule *rrule.Rule
+}
+
+func init() {
+	validate.Register("rrule", func(value string) bool {
+		_, err := rrule.Parse(value)
+		return err == nil
+	})
+}
+
+// NewRecurrence checks the rule and timezone, reporting both in the returned
+// errs.FieldErrors when invalid. An empty timezone means DefaultTimezone.
+func NewRecurrence(rule, timezone string, exdates []time.Time) (*Recurrence, error) {
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+
+	r := &Recurrence{RRule: rule, Timezone: timezone, ExDates: exdates}
+	if err := validate.Struct(r); err != nil {
+		return nil, err
+	}
+
+	r.rule, _ = rrule.Parse(rule)
+	r.RRule = r.rule.String()
+
+	return r, nil
+}
+
+func (r *Recurrence) parsed() (*rrule.Rule, *time.Location) {
+	if r.rule == nil {
+		r.rule, _ = rrule.Parse(r.RRule)
+	}
+
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return r.rule, loc
+}
+
+// Between returns the occurrences in [from, to) that were not excluded.
+func (r *Recurrence) Between(from, to time.Time) []time.Time {
+	rule, loc := r.parsed()
+	if rule == nil {
+		return nil
+	}
+
+	return rule.Between(r.Start.In(loc), from, to, r.ExDates)
+}
+
+// After returns the first occurrence strictly after t. The second result is
+// false when the rule has no more occurrences.
+func (r *Recurrence) After(t time.Time) (time.Time, bool) {
+	rule, loc := r.parsed()
+	if rule == nil {
+		return time.Time{}, false
+	}
+
+	return rule.After(r.Start.In(loc), t, r.ExDates)
+}
+
+// Exclude adds an EXDATE for the occurrence at t.
+func (r *Recurrence) Exclude(t time.Time) {
+	r.ExDates = append(r.ExDates, t)
+}
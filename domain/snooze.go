@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+// MaxSnooze is how far a care occurrence can be pushed back.
+var MaxSnooze = 30 * 24 * time.Hour
+
+func validateCareReason(reason string) error {
+	if len(reason) > 500 {
+		return errs.ErrInvalidCareReason
+	}
+	return nil
+}
+
+// Skip gives up the current occurrence on purpose, for instance because the
+// soil is still wet, and schedules the next one. LastCare is left untouched
+// and the checklist, if any, is reset. The skipped occurrence of a recurrence
+// is excluded, so that it does not come back when the schedule is computed
+// again from the rule. The returned entry should be stored along with the
+// care.
+func (c *Care) Skip(now time.Time, reason string, plantFrequency int) (*CareHistoryEntry, error) {
+	if err := validateCareReason(reason); err != nil {
+		return nil, err
+	}
+
+	next, ok, err := c.nextOccurrence(now, plantFrequency)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, errs.ErrCareRecurrenceEnded
+	}
+
+	entry := newCareHistoryEntry(c, CareHistorySkipped, c.NextCare, now, reason)
+
+	if c.Recurrence != nil {
+		c.Recurrence.Exclude(c.scheduled())
+	}
+	c.NextCare = next
+	c.SnoozedFrom = nil
+	c.UpdatedAt = now
+	c.resetChecklist()
+
+	return entry, nil
+}
+
+// Snooze moves the current occurrence to until, which must be ahead of now
+// and within MaxSnooze. The rest of the schedule is kept as it was: once the
+// snoozed occurrence is done or skipped, the care continues from its
+// original date. The returned entry should be stored along with the care.
+func (c *Care) Snooze(until, now time.Time, reason string) (*CareHistoryEntry, error) {
+	if err := validateCareReason(reason); err != nil {
+		return nil, err
+	}
+
+	if !until.After(now) || until.Sub(now) > MaxSnooze {
+		return nil, errs.ErrInvalidSnooze
+	}
+
+	if c.Finished() {
+		return nil, errs.ErrCareRecurrenceEnded
+	}
+
+	entry := newCareHistoryEntry(c, CareHistorySnoozed, c.NextCare, now, reason)
+
+	if c.SnoozedFrom == nil {
+		scheduled := c.NextCare
+		c.SnoozedFrom = &scheduled
+	}
+	c.NextCare = until.UTC()
+	c.UpdatedAt = now
+
+	return entry, nil
+}
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCareSkipAndSnooze(t *testing.T) {
+	now := time.Date(2026, 11, 10, 12, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 11, 10, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		purpose      string
+		act          func(care *Care) (*CareHistoryEntry, error)
+		wantErr      error
+		wantKind     string
+		wantNextCare time.Time
+		wantLastCare time.Time
+	}{
+		{
+			"should skip to the next occurrence without touching last care",
+			func(care *Care) (*CareHistoryEntry, error) { return care.Skip(now, "soil still wet", 7) },
+			nil,
+			CareHistorySkipped,
+			due.AddDate(0, 0, 7),
+			due.AddDate(0, 0, -7),
+		},
+		{
+			"should snooze the current occurrence",
+			func(care *Care) (*CareHistoryEntry, error) { return care.Snooze(now.AddDate(0, 0, 2), now, "") },
+			nil,
+			CareHistorySnoozed,
+			now.AddDate(0, 0, 2),
+			due.AddDate(0, 0, -7),
+		},
+		{
+			"should keep the schedule when completing a snoozed occurrence",
+			func(care *Care) (*CareHistoryEntry, error) {
+				if _, err := care.Snooze(now.AddDate(0, 0, 2), now, ""); err != nil {
+					return nil, err
+				}
+				return care.Complete(now.AddDate(0, 0, 2), 7)
+			},
+			nil,
+			CareHistoryCompleted,
+			due.AddDate(0, 0, 7),
+			now.AddDate(0, 0, 2),
+		},
+		{
+			"should reject a snooze in the past",
+			func(care *Care) (*CareHistoryEntry, error) { return care.Snooze(now.Add(-time.Hour), now, "") },
+			errs.ErrInvalidSnooze,
+			"",
+			due,
+			due.AddDate(0, 0, -7),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.purpose, func(t *testing.T) {
+			care := &Care{Id: 1, LastCare: due.AddDate(0, 0, -7), NextCare: due}
+
+			entry, err := c.act(care)
+			assert.ErrorIs(t, err, c.wantErr)
+			if c.wantErr == nil {
+				assert.Equal(t, c.wantKind, entry.Kind)
+			}
+			assert.Equal(t, c.wantNextCare, care.NextCare)
+			assert.Equal(t, c.wantLastCare, care.LastCare)
+		})
+	}
+}
+
+func TestCareSkipExcludesRecurrence(t *testing.T) {
+	now := time.Date(2026, 11, 10, 12, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 11, 10, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		purpose     string
+		snoozeUntil time.Time
+	}{
+		{"should exclude the skipped occurrence", time.Time{}},
+		{"should exclude the scheduled date of a snoozed occurrence", now.AddDate(0, 0, 1)},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			recurrence, err := NewRecurrence("FREQ=WEEKLY;BYDAY=TU", "UTC", nil)
+			assert.NoError(t, err)
+			recurrence.Start = due.AddDate(0, 0, -7)
+			care := &Care{Id: 1, NextCare: due, Recurrence: recurrence}
+
+			if !tt.snoozeUntil.IsZero() {
+				_, err := care.Snooze(tt.snoozeUntil, now, "")
+				assert.NoError(t, err)
+			}
+
+			_, err = care.Skip(now, "soil still wet", 0)
+			assert.NoError(t, err)
+			assert.Equal(t, []time.Time{due}, care.Recurrence.ExDates)
+			assert.Equal(t, due.AddDate(0, 0, 7), care.NextCare)
+
+			next, ok := care.Recurrence.After(due.AddDate(0, 0, -1))
+			assert.True(t, ok)
+			assert.Equal(t, due.AddDate(0, 0, 7), next.UTC(), "should not bring the skipped date back")
+		})
+	}
+}
+
+func TestComputeAdherence(t *testing.T) {
+	now := time.Date(2026, 11, 10, 12, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 11, 5, 9, 0, 0, 0, time.UTC)
+	snoozedFrom := now.AddDate(0, 0, -3)
+
+	history := []*CareHistoryEntry{
+		{Kind: CareHistoryCompleted, DueAt: &due, OccurredAt: due.Add(time.Hour)},
+		{Kind: CareHistoryCompleted, DueAt: &due, OccurredAt: due.AddDate(0, 0, 2)},
+		{Kind: CareHistorySkipped, DueAt: &due, OccurredAt: due},
+		{Kind: CareHistorySnoozed, DueAt: &due, OccurredAt: due},
+	}
+	plants := []*Plant{
+		{Id: 1, CareFrequency: 7, Status: PlantStatusActive},
+		{Id: 2, CareFrequency: 1, Status: PlantStatusDeceased},
+	}
+
+	cases := []struct {
+		purpose    string
+		cares      []*Care
+		wantMissed int
+	}{
+		{
+			"should count an overdue care",
+			[]*Care{{PlantId: 1, NextCare: now.AddDate(0, 0, -2)}},
+			1,
+		},
+		{
+			"should count every occurrence missed since the overdue one",
+			[]*Care{{PlantId: 1, Interval: 1, NextCare: now.AddDate(0, 0, -3)}},
+			3,
+		},
+		{
+			"should not count the occurrence due today nor upcoming ones",
+			[]*Care{
+				{PlantId: 1, Interval: 1, NextCare: now.Add(-time.Hour)},
+				{PlantId: 1, NextCare: now.AddDate(0, 0, 2)},
+			},
+			0,
+		},
+		{
+			"should only count the occurrences since from",
+			[]*Care{{PlantId: 1, NextCare: from.AddDate(0, 0, -10)}},
+			4,
+		},
+		{
+			"should count a snoozed occurrence once",
+			[]*Care{{PlantId: 1, Interval: 30, NextCare: now.AddDate(0, 0, -1), SnoozedFrom: &snoozedFrom}},
+			1,
+		},
+		{
+			"should leave out the cares of plants that are not active",
+			[]*Care{{PlantId: 2, NextCare: now.AddDate(0, 0, -3)}, {PlantId: 3, NextCare: now.AddDate(0, 0, -3)}},
+			0,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			a := ComputeAdherence(history, plants, tt.cares, from, now, time.UTC)
+
+			assert.Equal(t, 2, a.Completed)
+			assert.Equal(t, 1, a.OnTime)
+			assert.Equal(t, 1, a.Late)
+			assert.Equal(t, 1, a.Skipped)
+			assert.Equal(t, 1, a.Snoozed)
+			assert.Equal(t, tt.wantMissed, a.Missed)
+			assert.Equal(t, 1.0/float64(2+tt.wantMissed), a.Rate)
+		})
+	}
+}
@@ -1,41 +1,65 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// RoleAdmin grants access to every user's data.
+const RoleAdmin = "admin"
+
+// VerificationCodeTTL is how long the code that verifies the email of a user
+// can be used.
+const VerificationCodeTTL = 15 * time.Minute
+
 type User struct {
 	Id         int64  `json:"-"`
 	ExternalId string `json:"external_id"`
 
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	Username string `json:"username" validate:"min=4,max=20"`
+	Email    string `json:"email" validate:"min=4,max=100"`
 	Password string `json:"-"`
 
 	Active   bool `json:"active"`
 	Verified bool `json:"verified"`
 
 	Roles []string `json:"roles"`
+
+	Timezone   string      `json:"timezone"`
+	Locale     string      `json:"locale"`
+	QuietHours *QuietHours `json:"quietHours"`
 }
 
+// plainPassword holds the rules of a password, checked before it is hashed.
+type plainPassword struct {
+	Password string `json:"password" validate:"min=8,max=32"`
+}
+
+// NewUser creates an active user with the default preferences. Every invalid
+// field, including the password, is reported in the returned
+// errs.FieldErrors.
 func NewUser(username, email, password string, roles []string) (*User, error) {
 	m := &User{
 		Email:    email,
 		Username: username,
 		Active:   true,
 		Roles:    roles,
+		Timezone: DefaultTimezone,
+		Locale:   DefaultLocale,
 	}
 
-	if len(m.Username) < 4 || len(m.Username) > 20 {
-		return nil, errs.ErrInvalidUsername
-	}
-
-	if len(m.Email) < 4 || len(m.Email) > 100 {
-		return nil, errs.ErrInvalidEmail
+	var fields errs.FieldErrors
+	fields.Merge("", validate.Struct(m))
+	fields.Merge("", validate.Struct(plainPassword{password}))
+	if err := fields.Err(); err != nil {
+		return nil, err
 	}
 
-	if err := m.HashPass(password); err != nil {
+	m.Password = password
+	if err := m.hashPassword(); err != nil {
 		return nil, err
 	}
 
@@ -43,8 +67,8 @@ func NewUser(username, email, password string, roles []string) (*User, error) {
 }
 
 func (u *User) HashPass(str string) error {
-	if len(str) < 8 || len(str) > 32 {
-		return errs.ErrInvalidPassword
+	if err := validate.Struct(plainPassword{str}); err != nil {
+		return err
 	}
 
 	u.Password = str
@@ -67,3 +91,13 @@ func (u *User) VerifyPassword(providedPassword string) error {
 	}
 	return nil
 }
+
+// HasRole reports whether roles contains role.
+func HasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
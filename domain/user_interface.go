@@ -1,16 +1,31 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"strconv"
+)
 
 type UserStorer interface {
 	CreateUser(ctx context.Context, user *User) (string, error)
 	GetUserByUsername(ctx context.Context, username string) (*User, error)
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
 	GetUserByExternalId(ctx context.Context, id string) (*User, error)
+	GetUserByID(ctx context.Context, id int64) (*User, error)
 	AddRolesToUser(ctx context.Context, id string, roles []string) error
 	RemoveRolesFromUser(ctx context.Context, id string, roles []string) error
 	VerifyUser(ctx context.Context, id string) error
 	UpdateActiveUserStatus(ctx context.Context, id string, active bool) error
 	DeleteUser(ctx context.Context, id string) error
 	UpdatePassword(ctx context.Context, id, password string) error
+	UpdatePreferences(ctx context.Context, user *User) error
+	EraseUser(ctx context.Context, id int64) error
+}
+
+// GetUserBySubject loads the user a token was issued to. Tokens carry the
+// numeric id of the user when issued at login, and the external id otherwise.
+func GetUserBySubject(ctx context.Context, storer UserStorer, subject string) (*User, error) {
+	if id, err := strconv.ParseInt(subject, 10, 64); err == nil {
+		return storer.GetUserByID(ctx, id)
+	}
+	return storer.GetUserByExternalId(ctx, subject)
 }
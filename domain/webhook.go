@@ -0,0 +1,296 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mergepatch"
+	"github.com/mathehluiz/plant-care-tracker/pkg/validate"
+)
+
+const (
+	WebhookPlantCreated  = EventPlantCreated
+	WebhookPlantUpdated  = EventPlantUpdated
+	WebhookPlantDeleted  = EventPlantDeleted
+	WebhookCareCreated   = EventCareCreated
+	WebhookCareUpdated   = EventCareUpdated
+	WebhookCareDeleted   = EventCareDeleted
+	WebhookCareCompleted = EventCareCompleted
+	WebhookCareSkipped   = EventCareSkipped
+	WebhookCareSnoozed   = EventCareSnoozed
+	WebhookCareDue       = EventCareDue
+
+	// WebhookAllEvents subscribes a webhook to every event, including the
+	// ones added later.
+	WebhookAllEvents = "*"
+
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookEvents lists the events a webhook can subscribe to.
+var WebhookEvents = []string{
+	WebhookPlantCreated, WebhookPlantUpdated, WebhookPlantDeleted,
+	WebhookCareCreated, WebhookCareUpdated, WebhookCareDeleted,
+	WebhookCareCompleted, WebhookCareSkipped, WebhookCareSnoozed, WebhookCareDue,
+}
+
+// WebhookMaxAttempts is how many times a delivery is tried before it is
+// marked as failed. With the backoff below the last attempt happens about 20
+// hours after the event.
+const WebhookMaxAttempts = 12
+
+// webhookBackoff is the delay before the first retry. It doubles after every
+// failed attempt, up to webhookMaxBackoff.
+var (
+	webhookBackoff    = time.Minute
+	webhookMaxBackoff = 6 * time.Hour
+)
+
+// Webhook is an endpoint that receives the events it subscribed to. Every
+// delivery is signed with Secret, which is only shown when the webhook is
+// created.
+type Webhook struct {
+	Id        int64     `json:"id"`
+	UserId    int64     `json:"-"`
+	URL       string    `json:"url" validate:"max=2000,http_url"`
+	Events    []string  `json:"events" validate:"min=1,dive,webhook_event"`
+	Secret    string    `json:"-"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func init() {
+	validate.Register("webhook_event", isWebhookEvent)
+}
+
+// NewWebhook creates an active webhook with a new secret. Every invalid field
+// is reported in the returned errs.FieldErrors.
+func NewWebhook(userId int64, endpoint string, events []string) (*Webhook, error) {
+	now := time.Now().UTC()
+	webhook := &Webhook{
+		UserId:    userId,
+		URL:       endpoint,
+		Events:    events,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := validate.Struct(webhook); err != nil {
+		return nil, err
+	}
+	webhook.Events = uniqueWebhookEvents(events)
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = secret
+
+	return webhook, nil
+}
+
+// Patch applies a JSON Merge Patch to the url, events and active fields of
+// the webhook, in the same way as Plant.Patch.
+func (w *Webhook) Patch(doc mergepatch.Document) error {
+	r := newPatchReader(doc)
+
+	patched := *w
+	r.read("url", &patched.URL, false)
+	var events []string
+	if sent, _ := r.read("events", &events, false); sent {
+		patched.Events = events
+	}
+	r.read("active", &patched.Active, false)
+	r.validate(&patched)
+
+	if err := r.err(); err != nil {
+		return err
+	}
+
+	w.URL = patched.URL
+	w.Events = uniqueWebhookEvents(patched.Events)
+	w.Active = patched.Active
+	w.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+// Subscribed reports whether the webhook wants to receive event.
+func (w *Webhook) Subscribed(event string) bool {
+	for _, e := range w.Events {
+		if e == event || e == WebhookAllEvents {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueWebhookEvents drops the duplicated events.
+func uniqueWebhookEvents(events []string) []string {
+	seen := make(map[string]bool, len(events))
+	unique := make([]string, 0, len(events))
+	for _, event := range events {
+		if !seen[event] {
+			seen[event] = true
+			unique = append(unique, event)
+		}
+	}
+	return unique
+}
+
+func isWebhookEvent(event string) bool {
+	if event == WebhookAllEvents {
+		return true
+	}
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// SignWebhookPayload returns the signature sent along with a delivery: the
+// hex HMAC-SHA256, keyed with the webhook secret, of the Unix timestamp of
+// the attempt, a dot and the payload. Receivers should compare it in
+// constant time and reject old timestamps to prevent replays.
+func SignWebhookPayload(secret string, timestamp time.Time, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookEvent is the payload of every delivery. Id is the same for every
+// delivery, and redelivery, of the event, so receivers can skip the ones
+// they have already handled.
+type WebhookEvent struct {
+	Id        string    `json:"id"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// WebhookDelivery is one event queued for, or sent to, a webhook.
+// RedeliveryOf is set on deliveries created by Redeliver.
+type WebhookDelivery struct {
+	Id             int64           `json:"id"`
+	WebhookId      int64           `json:"webhookId"`
+	EventId        string          `json:"eventId"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	ResponseStatus int             `json:"responseStatus,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	RedeliveryOf   int64           `json:"redeliveryOf,omitempty"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+}
+
+// NewWebhookDeliveries queues event for every webhook subscribed to it.
+func NewWebhookDeliveries(webhooks []*Webhook, event WebhookEvent) ([]*WebhookDelivery, error) {
+	var deliveries []*WebhookDelivery
+	var payload []byte
+
+	for _, webhook := range webhooks {
+		if !webhook.Active || !webhook.Subscribed(event.Event) {
+			continue
+		}
+
+		if payload == nil {
+			var err error
+			if payload, err = json.Marshal(event); err != nil {
+				return nil, err
+			}
+		}
+
+		deliveries = append(deliveries, &WebhookDelivery{
+			WebhookId:     webhook.Id,
+			EventId:       event.Id,
+			Event:         event.Event,
+			Payload:       payload,
+			Status:        WebhookDeliveryPending,
+			NextAttemptAt: event.CreatedAt,
+			CreatedAt:     event.CreatedAt,
+			UpdatedAt:     event.CreatedAt,
+		})
+	}
+
+	return deliveries, nil
+}
+
+// Succeed records that the webhook accepted the delivery.
+func (d *WebhookDelivery) Succeed(status int, now time.Time) {
+	d.Status = WebhookDeliverySucceeded
+	d.ResponseStatus = status
+	d.Error = ""
+	d.DeliveredAt = &now
+	d.UpdatedAt = now
+}
+
+// Fail records a failed attempt, with the response status if there was a
+// response, and either schedules a retry with an exponential backoff or, once
+// WebhookMaxAttempts is reached, marks the delivery as failed.
+func (d *WebhookDelivery) Fail(status int, err error, now time.Time) {
+	d.ResponseStatus = status
+	d.Error = err.Error()
+	d.UpdatedAt = now
+
+	if d.Attempts >= WebhookMaxAttempts {
+		d.Status = WebhookDeliveryFailed
+		return
+	}
+
+	d.Status = WebhookDeliveryPending
+	d.NextAttemptAt = now.Add(retryBackoff(d.Attempts, webhookBackoff, webhookMaxBackoff))
+}
+
+// Abandon marks the delivery as failed without retrying it, e.g. when its
+// webhook was disabled.
+func (d *WebhookDelivery) Abandon(err error, now time.Time) {
+	d.Status = WebhookDeliveryFailed
+	d.Error = err.Error()
+	d.UpdatedAt = now
+}
+
+// Redeliver queues the event of a finished delivery again, keeping its event
+// id and payload.
+func (d *WebhookDelivery) Redeliver(now time.Time) (*WebhookDelivery, error) {
+	if d.Status == WebhookDeliveryPending {
+		return nil, errs.ErrWebhookNotRetryable
+	}
+
+	return &WebhookDelivery{
+		WebhookId:     d.WebhookId,
+		EventId:       d.EventId,
+		Event:         d.Event,
+		Payload:       d.Payload,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: now,
+		RedeliveryOf:  d.Id,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
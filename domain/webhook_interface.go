@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type WebhookStorer interface {
+	CreateWebhook(ctx context.Context, webhook *Webhook) (int64, error)
+	GetWebhookByID(ctx context.Context, id int64) (*Webhook, error)
+	GetWebhooksByUserID(ctx context.Context, userId int64) ([]*Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *Webhook) error
+	DeleteWebhook(ctx context.Context, id int64) error
+
+	// CreateWebhookDeliveries queues the deliveries, skipping the ones for an
+	// event the webhook already has a delivery of, unless they are
+	// redeliveries. It fills in the ids of the deliveries inserted.
+	CreateWebhookDeliveries(ctx context.Context, deliveries []*WebhookDelivery) error
+	GetWebhookDeliveryByID(ctx context.Context, id int64) (*WebhookDelivery, error)
+	// GetWebhookDeliveries returns the deliveries of the webhook newest
+	// first, up to limit, older than beforeId when it is not 0.
+	GetWebhookDeliveries(ctx context.Context, webhookId, beforeId int64, limit int) ([]*WebhookDelivery, error)
+	// ClaimWebhookDeliveries counts an attempt for up to limit pending
+	// deliveries that are due, and leases them until now plus lease so that
+	// no other replica sends them meanwhile. A delivery whose sender died is
+	// retried once the lease expires.
+	ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery *WebhookDelivery) error
+}
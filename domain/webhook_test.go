@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWebhook(t *testing.T) {
+	cases := []struct {
+		purpose    string
+		url        string
+		events     []string
+		wantFields []string
+		wantEvents []string
+	}{
+		{
+			"should create a webhook and drop duplicated events",
+			"https://example.com/hooks",
+			[]string{WebhookCareDue, WebhookCareDue, WebhookPlantCreated},
+			nil,
+			[]string{WebhookCareDue, WebhookPlantCreated},
+		},
+		{
+			"should report every invalid field",
+			"mailto:hooks@example.com",
+			[]string{"plant.watered"},
+			[]string{"url", "events.0"},
+			nil,
+		},
+		{
+			"should reject urls that are not http",
+			"ftp://example.com/hooks",
+			[]string{WebhookCareDue},
+			[]string{"url"},
+			nil,
+		},
+		{
+			"should reject unknown events",
+			"http://homeassistant.local:8123/api/webhook/plants",
+			[]string{WebhookCareDue, "plant.watered"},
+			[]string{"events.1"},
+			nil,
+		},
+		{
+			"should require at least one event",
+			"https://example.com/hooks",
+			nil,
+			[]string{"events"},
+			nil,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			webhook, err := NewWebhook(1, tt.url, tt.events)
+			if tt.wantFields != nil {
+				var fields errs.FieldErrors
+				assert.ErrorAs(t, err, &fields)
+
+				got := make([]string, 0, len(fields))
+				for _, f := range fields {
+					got = append(got, f.Field)
+				}
+				assert.Equal(t, tt.wantFields, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantEvents, webhook.Events)
+			assert.Len(t, webhook.Secret, 70)
+			assert.True(t, webhook.Active)
+		})
+	}
+}
+
+func TestNewWebhookDeliveries(t *testing.T) {
+	now := time.Date(2026, 11, 10, 9, 0, 0, 0, time.UTC)
+	webhooks := []*Webhook{
+		{Id: 1, Events: []string{WebhookCareDue}, Active: true},
+		{Id: 2, Events: []string{WebhookAllEvents}, Active: true},
+		{Id: 3, Events: []string{WebhookCareDue}, Active: false},
+		{Id: 4, Events: []string{WebhookPlantCreated}, Active: true},
+	}
+
+	deliveries, err := NewWebhookDeliveries(webhooks, WebhookEvent{Id: "e1", Event: WebhookCareDue, CreatedAt: now, Data: map[string]int{"care": 1}})
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+
+	for _, d := range deliveries {
+		assert.Equal(t, "e1", d.EventId)
+		assert.Equal(t, WebhookDeliveryPending, d.Status)
+		assert.Equal(t, now, d.NextAttemptAt)
+		assert.JSONEq(t, `{"id":"e1","event":"care.due","createdAt":"2026-11-10T09:00:00Z","data":{"care":1}}`, string(d.Payload))
+	}
+}
+
+func TestWebhookDeliveryFail(t *testing.T) {
+	now := time.Date(2026, 11, 10, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		purpose    string
+		attempts   int
+		wantStatus string
+		wantNext   time.Time
+	}{
+		{"should retry the first failure after the base backoff", 1, WebhookDeliveryPending, now.Add(time.Minute)},
+		{"should double the backoff after every attempt", 4, WebhookDeliveryPending, now.Add(8 * time.Minute)},
+		{"should cap the backoff", 10, WebhookDeliveryPending, now.Add(6 * time.Hour)},
+		{"should give up after the last attempt", WebhookMaxAttempts, WebhookDeliveryFailed, time.Time{}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			d := &WebhookDelivery{Attempts: tt.attempts, Status: WebhookDeliveryPending}
+			d.Fail(503, errors.New("unexpected response status 503"), now)
+
+			assert.Equal(t, tt.wantStatus, d.Status)
+			assert.Equal(t, tt.wantNext, d.NextAttemptAt)
+			assert.Equal(t, 503, d.ResponseStatus)
+		})
+	}
+}
+
+func TestWebhookDeliveryRedeliver(t *testing.T) {
+	now := time.Date(2026, 11, 10, 9, 0, 0, 0, time.UTC)
+
+	_, err := (&WebhookDelivery{Id: 1, Status: WebhookDeliveryPending}).Redeliver(now)
+	assert.Equal(t, errs.ErrWebhookNotRetryable, err)
+
+	d := &WebhookDelivery{Id: 1, WebhookId: 2, EventId: "e1", Event: WebhookCareDue, Payload: []byte(`{}`), Status: WebhookDeliveryFailed, Attempts: WebhookMaxAttempts}
+	redelivery, err := d.Redeliver(now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), redelivery.RedeliveryOf)
+	assert.Equal(t, "e1", redelivery.EventId)
+	assert.Equal(t, WebhookDeliveryPending, redelivery.Status)
+	assert.Zero(t, redelivery.Attempts)
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+
+	signature := SignWebhookPayload("whsec_test", at, []byte(`{"id":"e1"}`))
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.Len(t, signature, 7+64)
+	assert.Equal(t, signature, SignWebhookPayload("whsec_test", at, []byte(`{"id":"e1"}`)))
+	assert.NotEqual(t, signature, SignWebhookPayload("whsec_test", at.Add(time.Second), []byte(`{"id":"e1"}`)))
+	assert.NotEqual(t, signature, SignWebhookPayload("whsec_other", at, []byte(`{"id":"e1"}`)))
+}
@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+)
+
+// ActorKey is the gin context key the request actor is stored under, so it
+// can be found both from a *gin.Context and from its request context.
+const ActorKey = "audit:actor"
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying actor.
+func WithActor(ctx context.Context, actor *domain.AuditActor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor of the request ctx belongs to, or nil
+// outside of a request, such as in background jobs.
+func ActorFromContext(ctx context.Context) *domain.AuditActor {
+	if actor, ok := ctx.Value(actorKey{}).(*domain.AuditActor); ok {
+		return actor
+	}
+	if actor, ok := ctx.Value(ActorKey).(*domain.AuditActor); ok {
+		return actor
+	}
+	return nil
+}
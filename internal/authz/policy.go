@@ -0,0 +1,53 @@
+// Package authz decides who may access plants, cares and the data attached
+// to them.
+package authz
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/audit"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+// Subject is the authenticated user a request is made on behalf of.
+type Subject struct {
+	UserId int64
+	Roles  []string
+}
+
+// IsAdmin reports whether the subject may access every user's data.
+func (s Subject) IsAdmin() bool {
+	return domain.HasRole(s.Roles, domain.RoleAdmin)
+}
+
+// Policy only lets users access what they own. Admins may access anything,
+// but every access to another user's data is recorded in the audit log.
+type Policy struct {
+	log domain.AuditStorer
+}
+
+func NewPolicy(log domain.AuditStorer) *Policy {
+	return &Policy{log: log}
+}
+
+// Authorize checks that subject may access the entity owned by ownerId. It
+// returns errs.ErrNotFound when not, so that callers respond as if the entity
+// did not exist instead of revealing it does. An admin bypass that cannot be
+// recorded is refused.
+func (p *Policy) Authorize(ctx context.Context, subject Subject, entityType string, entityId, ownerId int64) error {
+	if subject.UserId == ownerId {
+		return nil
+	}
+
+	if !subject.IsAdmin() {
+		return errs.ErrNotFound
+	}
+
+	entry := domain.NewAuditAccessEntry(audit.ActorFromContext(ctx), entityType, strconv.FormatInt(entityId, 10), ownerId)
+	if _, err := p.log.CreateAuditEntry(ctx, entry); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+type auditLog struct {
+	entries []*domain.AuditEntry
+	err     error
+}
+
+func (l *auditLog) CreateAuditEntry(ctx context.Context, entry *domain.AuditEntry) (int64, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	l.entries = append(l.entries, entry)
+	return int64(len(l.entries)), nil
+}
+
+func (l *auditLog) GetAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	return l.entries, nil
+}
+
+func TestPolicyAuthorize(t *testing.T) {
+	cases := []struct {
+		purpose     string
+		subject     Subject
+		logErr      error
+		wantErr     error
+		wantEntries int
+	}{
+		{
+			"should let owners access their data without auditing it",
+			Subject{UserId: 1},
+			nil,
+			nil,
+			0,
+		},
+		{
+			"should hide data owned by other users",
+			Subject{UserId: 2, Roles: []string{"user"}},
+			nil,
+			errs.ErrNotFound,
+			0,
+		},
+		{
+			"should let admins bypass ownership and audit it",
+			Subject{UserId: 2, Roles: []string{domain.RoleAdmin}},
+			nil,
+			nil,
+			1,
+		},
+		{
+			"should refuse an admin bypass that cannot be audited",
+			Subject{UserId: 2, Roles: []string{domain.RoleAdmin}},
+			errors.New("connection refused"),
+			errors.New("connection refused"),
+			0,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			log := &auditLog{err: tt.logErr}
+
+			err := NewPolicy(log).Authorize(context.Background(), tt.subject, domain.AuditEntityPlant, 10, 1)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Len(t, log.entries, tt.wantEntries)
+
+			for _, entry := range log.entries {
+				assert.Equal(t, domain.AuditAccess, entry.Action)
+				assert.Equal(t, "10", entry.EntityId)
+				assert.Equal(t, int64(1), entry.OwnerId)
+			}
+		})
+	}
+}
@@ -83,6 +83,10 @@ func (r *rdm) GetIncludingKey(ctx context.Context, mustInclude string) (string,
 	return result + "]", nil
 }
 
+func (r *rdm) Client() *redis.Client {
+	return r.client
+}
+
 func (r *rdm) Close() error {
 	return r.client.Close()
 }
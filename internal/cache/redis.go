@@ -22,6 +22,9 @@ type ConnectionStorer interface {
 	Set(ctx context.Context, duration time.Duration, key string, value string) error
 	GetKeys(ctx context.Context, mustInclude string) ([]string, error)
 	GetIncludingKey(ctx context.Context, mustInclude string) (string, error)
+	// Client is the underlying connection, for the features the methods
+	// above do not cover, such as streams.
+	Client() *redis.Client
 }
 
 var (
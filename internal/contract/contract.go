@@ -0,0 +1,245 @@
+// Package contract checks HTTP traffic against the OpenAPI document of the API.
+package contract
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+// Mode selects what the contract middleware validates.
+type Mode string
+
+const (
+	// ModeOff disables validation.
+	ModeOff Mode = "off"
+	// ModeRequests rejects requests that do not match the document.
+	ModeRequests Mode = "requests"
+	// ModeAll also checks responses. It buffers every response, so it is
+	// meant for development and tests.
+	ModeAll Mode = "all"
+)
+
+var ErrInvalidMode = errors.New("invalid OpenAPI validation mode, expected off, requests or all")
+
+// ParseMode reads a Mode, defaulting to ModeRequests when s is empty.
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(s)); mode {
+	case "":
+		return ModeRequests, nil
+	case ModeOff, ModeRequests, ModeAll:
+		return mode, nil
+	default:
+		return "", ErrInvalidMode
+	}
+}
+
+func init() {
+	// Merge patches are JSON documents, decode them as such.
+	openapi3filter.RegisterBodyDecoder("application/merge-patch+json", openapi3filter.RegisteredBodyDecoder("application/json"))
+}
+
+// Contract is a loaded and validated OpenAPI document.
+type Contract struct {
+	doc     *openapi3.T
+	options *openapi3filter.Options
+}
+
+// Load parses and validates an OpenAPI document.
+func Load(data []byte) (*Contract, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return &Contract{
+		doc: doc,
+		options: &openapi3filter.Options{
+			MultiError:          true,
+			SkipSettingDefaults: true,
+			// Authentication is enforced by the route middlewares.
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}, nil
+}
+
+var ginParam = regexp.MustCompile(`[:*]([^/]+)`)
+
+// Route is the operation documented for a Gin route.
+type Route struct {
+	route *routers.Route
+}
+
+// Find returns the operation documented for method and the Gin route path,
+// such as /api/v1/plants/:id.
+func (c *Contract) Find(method, path string) (*Route, bool) {
+	path = ginParam.ReplaceAllString(path, "{$1}")
+
+	item := c.doc.Paths[path]
+	if item == nil {
+		return nil, false
+	}
+	operation := item.GetOperation(method)
+	if operation == nil {
+		return nil, false
+	}
+
+	return &Route{route: &routers.Route{
+		Spec:      c.doc,
+		Path:      path,
+		PathItem:  item,
+		Method:    method,
+		Operation: operation,
+	}}, true
+}
+
+// Undocumented lists the Gin routes missing from the document.
+func (c *Contract) Undocumented(routes gin.RoutesInfo) []string {
+	var missing []string
+	for _, r := range routes {
+		if _, ok := c.Find(r.Method, r.Path); !ok {
+			missing = append(missing, r.Method+" "+r.Path)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// ValidateRequest checks r against the operation of route. The body of r is
+// left unread. Violations are returned as errs.FieldErrors.
+func (c *Contract) ValidateRequest(r *http.Request, route *Route, params gin.Params) error {
+	// Validate a copy so that the handler still reads the body as sent;
+	// like the handlers, treat bodies without a content type as JSON.
+	req := r.Clone(r.Context())
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = nil
+		if len(body) > 0 && req.Header.Get("Content-Type") == "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+
+	err := openapi3filter.ValidateRequest(r.Context(), c.requestInput(req, route, params))
+	if err == nil {
+		return nil
+	}
+	return toFieldErrors(err)
+}
+
+// ValidateResponse checks a response of route against the document.
+// Violations are returned as errs.FieldErrors.
+func (c *Contract) ValidateResponse(r *http.Request, route *Route, params gin.Params, status int, header http.Header, body []byte) error {
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: c.requestInput(r, route, params),
+		Status:                 status,
+		Header:                 header,
+		Options:                &openapi3filter.Options{IncludeResponseStatus: true, MultiError: true},
+	}
+	input.SetBodyBytes(body)
+
+	err := openapi3filter.ValidateResponse(r.Context(), input)
+	if err == nil {
+		return nil
+	}
+	return toFieldErrors(err)
+}
+
+func (c *Contract) requestInput(r *http.Request, route *Route, params gin.Params) *openapi3filter.RequestValidationInput {
+	pathParams := make(map[string]string, len(params))
+	for _, p := range params {
+		pathParams[p.Key] = strings.TrimPrefix(p.Value, "/")
+	}
+
+	return &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route:      route.route,
+		Options:    c.options,
+	}
+}
+
+// toFieldErrors flattens the errors of kin-openapi into one FieldError per
+// violation. Body fields are named by their path, such as items.0.name, and
+// schema violations by the keyword that failed, such as minLength.
+func toFieldErrors(err error) errs.FieldErrors {
+	var fields errs.FieldErrors
+
+	var walk func(field string, err error)
+	walk = func(field string, err error) {
+		switch e := err.(type) {
+		case openapi3.MultiError:
+			for _, err := range e {
+				walk(field, err)
+			}
+		case *openapi3filter.RequestError:
+			switch {
+			case e.Parameter != nil:
+				field = e.Parameter.Name
+			case e.RequestBody != nil:
+				field = "body"
+			}
+			switch e.Err.(type) {
+			case openapi3.MultiError, *openapi3.SchemaError:
+				walk(field, e.Err)
+			default:
+				if e.Reason != "" || e.Err == nil {
+					fields = append(fields, errs.FieldError{Field: field, Message: e.Reason})
+					return
+				}
+				walk(field, e.Err)
+			}
+		case *openapi3filter.ResponseError:
+			if e.Err == nil {
+				fields = append(fields, errs.FieldError{Field: "response", Message: e.Reason})
+				return
+			}
+			walk("response", e.Err)
+		case *openapi3.SchemaError:
+			if path := e.JSONPointer(); len(path) > 0 {
+				if field == "body" || field == "" {
+					field = strings.Join(path, ".")
+				} else {
+					field = fmt.Sprintf("%s.%s", field, strings.Join(path, "."))
+				}
+			}
+			fields = append(fields, errs.FieldError{Field: field, Rule: e.SchemaField, Message: e.Reason})
+		default:
+			fields = append(fields, errs.FieldError{Field: field, Message: err.Error()})
+		}
+	}
+	walk("", err)
+
+	return fields
+}
+
+// Streams reports whether the operation answers with a stream of events,
+// whose response cannot be buffered for validation.
+func (r *Route) Streams() bool {
+	for _, response := range r.route.Operation.Responses {
+		if response.Value != nil && response.Value.Content.Get("text/event-stream") != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package contract
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mathehluiz/plant-care-tracker/docs"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRequest(t *testing.T) {
+	spec, err := Load(docs.OpenAPI)
+	assert.NoError(t, err)
+
+	cases := []struct {
+		purpose     string
+		method      string
+		route       string
+		target      string
+		params      gin.Params
+		contentType string
+		body        string
+		wantFields  []string
+	}{
+		{
+			purpose:     "should accept a valid body",
+			method:      http.MethodPost,
+			route:       "/api/v1/register",
+			target:      "/api/v1/register",
+			contentType: "application/json",
+			body:        `{"username": "alice", "email": "alice@example.com", "password": "secret"}`,
+		},
+		{
+			purpose:     "should report every missing field",
+			method:      http.MethodPost,
+			route:       "/api/v1/register",
+			target:      "/api/v1/register",
+			contentType: "application/json",
+			body:        `{"username": "al"}`,
+			wantFields:  []string{"username", "email", "password"},
+		},
+		{
+			purpose:    "should name nested fields by their path",
+			method:     http.MethodPost,
+			route:      "/api/v1/care-templates",
+			target:     "/api/v1/care-templates",
+			body:       `{"name": "Succulents", "items": [{"name": "Water", "interval": 7}, {"interval": "weekly"}]}`,
+			wantFields: []string{"items.1.name", "items.1.interval"},
+		},
+		{
+			purpose:    "should reject a missing body",
+			method:     http.MethodPost,
+			route:      "/api/v1/webhooks",
+			target:     "/api/v1/webhooks",
+			wantFields: []string{"body"},
+		},
+		{
+			purpose:     "should accept merge patches",
+			method:      http.MethodPatch,
+			route:       "/api/v1/plants/:id",
+			target:      "/api/v1/plants/1",
+			params:      gin.Params{{Key: "id", Value: "1"}},
+			contentType: "application/merge-patch+json",
+			body:        `{"location": null}`,
+		},
+		{
+			purpose:    "should reject path parameters of the wrong type",
+			method:     http.MethodGet,
+			route:      "/api/v1/plants/:id",
+			target:     "/api/v1/plants/fern",
+			params:     gin.Params{{Key: "id", Value: "fern"}},
+			wantFields: []string{"id"},
+		},
+		{
+			purpose:    "should reject invalid query parameters",
+			method:     http.MethodGet,
+			route:      "/api/v1/audit",
+			target:     "/api/v1/audit?limit=0",
+			wantFields: []string{"limit"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			route, ok := spec.Find(tt.method, tt.route)
+			assert.True(t, ok)
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			err := spec.ValidateRequest(req, route, tt.params)
+			if tt.wantFields == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var fields errs.FieldErrors
+			assert.ErrorAs(t, err, &fields)
+
+			var names []string
+			for _, f := range fields {
+				names = append(names, f.Field)
+			}
+			assert.ElementsMatch(t, tt.wantFields, names)
+		})
+	}
+}
+
+func TestValidateRequestKeepsBody(t *testing.T) {
+	spec, err := Load(docs.OpenAPI)
+	assert.NoError(t, err)
+
+	route, ok := spec.Find(http.MethodPost, "/api/v1/login")
+	assert.True(t, ok)
+
+	body := `{"username": "alice", "password": "secret"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/login", strings.NewReader(body))
+	assert.NoError(t, spec.ValidateRequest(req, route, nil))
+
+	read, err := io.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(read), "the handler should read the body as sent")
+}
+
+func TestValidateResponse(t *testing.T) {
+	spec, err := Load(docs.OpenAPI)
+	assert.NoError(t, err)
+
+	route, ok := spec.Find(http.MethodGet, "/api/v1/plants/:id")
+	assert.True(t, ok)
+
+	params := gin.Params{{Key: "id", Value: "1"}}
+
+	cases := []struct {
+		purpose     string
+		status      int
+		contentType string
+		body        string
+		wantFields  []string
+	}{
+		{
+			"should accept a documented response",
+			http.StatusOK,
+			"application/json; charset=utf-8",
+			`{"id": 1, "name": "Fern", "version": 1}`,
+			nil,
+		},
+		{
+			"should accept problems",
+			http.StatusNotFound,
+			"application/problem+json",
+			`{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "not found", "code": "not_found"}`,
+			nil,
+		},
+		{
+			"should report fields of the wrong type",
+			http.StatusOK,
+			"application/json; charset=utf-8",
+			`{"id": "1", "name": "Fern"}`,
+			[]string{"response.id"},
+		},
+		{
+			"should report errors that are not problems",
+			http.StatusNotFound,
+			"application/json; charset=utf-8",
+			`{"error": "not found"}`,
+			[]string{"response"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/plants/1", nil)
+			header := http.Header{"Content-Type": []string{tt.contentType}}
+			err := spec.ValidateResponse(req, route, params, tt.status, header, []byte(tt.body))
+			if tt.wantFields == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var fields errs.FieldErrors
+			assert.ErrorAs(t, err, &fields)
+
+			var names []string
+			for _, f := range fields {
+				names = append(names, f.Field)
+			}
+			assert.ElementsMatch(t, tt.wantFields, names)
+		})
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	cases := []struct {
+		purpose string
+		value   string
+		want    Mode
+		wantErr error
+	}{
+		{"should validate requests by default", "", ModeRequests, nil},
+		{"should accept known modes", "ALL", ModeAll, nil},
+		{"should reject unknown modes", "strict", "", ErrInvalidMode},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			mode, err := ParseMode(tt.value)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, mode)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package drivers
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// TimeList stores a list of timestamps as a JSON array.
+type TimeList []time.Time
+
+func (l TimeList) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+
+	b, err := json.Marshal([]time.Time(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (l *TimeList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, (*[]time.Time)(l))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]time.Time)(l))
+	default:
+		return errors.New("unsupported Scan, storing driver.Value into type *drivers.TimeList")
+	}
+}
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS user_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    external_id UUID NOT NULL DEFAULT uuid_generate_v1() UNIQUE,
+    user_id BIGINT NOT NULL,
+    user_external_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    run_after TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    attempts INT NOT NULL DEFAULT 0,
+    result TEXT,
+    error TEXT,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS user_jobs_status_run_after_idx ON user_jobs (status, run_after);
+CREATE INDEX IF NOT EXISTS user_jobs_user_external_id_idx ON user_jobs (user_external_id);
+
+CREATE TRIGGER set_timestamp
+    BEFORE UPDATE ON user_jobs
+    FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_timestamp();
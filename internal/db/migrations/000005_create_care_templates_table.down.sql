@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS cares_plant_template_item_idx;
+
+ALTER TABLE cares
+    DROP COLUMN IF EXISTS template_item_id,
+    DROP COLUMN IF EXISTS interval_days;
+
+DROP TABLE IF EXISTS care_template_items;
+DROP TABLE IF EXISTS care_templates;
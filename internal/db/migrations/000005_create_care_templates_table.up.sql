@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS care_templates (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS care_template_items (
+    id BIGSERIAL PRIMARY KEY,
+    template_id BIGINT NOT NULL,
+    position INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    notes TEXT,
+    interval_days INT NOT NULL,
+    offset_days INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (template_id) REFERENCES care_templates(id) ON DELETE CASCADE
+);
+
+ALTER TABLE cares
+    ADD COLUMN IF NOT EXISTS interval_days INT,
+    ADD COLUMN IF NOT EXISTS template_item_id BIGINT REFERENCES care_template_items(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS cares_plant_template_item_idx ON cares (plant_id, template_item_id)
+    WHERE template_item_id IS NOT NULL;
+
+-- Built-in templates have no owner and are visible to every user.
+WITH succulent AS (
+    INSERT INTO care_templates (name) VALUES ('Succulent basics') RETURNING id
+), tropical AS (
+    INSERT INTO care_templates (name) VALUES ('Tropical houseplant') RETURNING id
+), herbs AS (
+    INSERT INTO care_templates (name) VALUES ('Kitchen herbs') RETURNING id
+)
+INSERT INTO care_template_items (template_id, position, name, notes, interval_days, offset_days)
+SELECT id, 0, 'Water', 'Soak the soil and let it dry out completely before watering again.', 14, 7 FROM succulent
+UNION ALL SELECT id, 1, 'Fertilize', 'Use a diluted cactus fertilizer during the growing season.', 30, 30 FROM succulent
+UNION ALL SELECT id, 2, 'Rotate', 'Turn the pot a quarter so the plant grows evenly.', 7, 7 FROM succulent
+UNION ALL SELECT id, 0, 'Water', 'Keep the soil lightly moist but never soggy.', 4, 2 FROM tropical
+UNION ALL SELECT id, 1, 'Mist leaves', 'Mist the leaves in the morning to raise humidity.', 2, 1 FROM tropical
+UNION ALL SELECT id, 2, 'Fertilize', 'Feed with a balanced liquid fertilizer at half strength.', 30, 14 FROM tropical
+UNION ALL SELECT id, 0, 'Water', 'Water when the top centimetre of soil is dry.', 2, 1 FROM herbs
+UNION ALL SELECT id, 1, 'Prune', 'Pinch off flower buds and the top leaves to keep it bushy.', 10, 10 FROM herbs;
@@ -0,0 +1,24 @@
+ALTER TABLE user_jobs
+    ALTER COLUMN run_after TYPE TIMESTAMP USING run_after AT TIME ZONE 'UTC',
+    ALTER COLUMN expires_at TYPE TIMESTAMP USING expires_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'UTC';
+
+ALTER TABLE cares
+    ALTER COLUMN last_care TYPE TIMESTAMP USING last_care AT TIME ZONE 'UTC',
+    ALTER COLUMN next_care TYPE TIMESTAMP USING next_care AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN deleted_at TYPE TIMESTAMP USING deleted_at AT TIME ZONE 'UTC';
+
+ALTER TABLE plants
+    ALTER COLUMN acquisition_date TYPE TIMESTAMP USING acquisition_date AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN deleted_at TYPE TIMESTAMP USING deleted_at AT TIME ZONE 'UTC';
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS quiet_hours_end,
+    DROP COLUMN IF EXISTS quiet_hours_start,
+    DROP COLUMN IF EXISTS locale,
+    DROP COLUMN IF EXISTS timezone;
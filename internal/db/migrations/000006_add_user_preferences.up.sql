@@ -0,0 +1,26 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    ADD COLUMN IF NOT EXISTS locale VARCHAR(10) NOT NULL DEFAULT 'en',
+    ADD COLUMN IF NOT EXISTS quiet_hours_start SMALLINT,
+    ADD COLUMN IF NOT EXISTS quiet_hours_end SMALLINT;
+
+-- Existing values were written by servers running in UTC, so they are
+-- reinterpreted as UTC instants.
+ALTER TABLE plants
+    ALTER COLUMN acquisition_date TYPE TIMESTAMPTZ USING acquisition_date AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE 'UTC';
+
+ALTER TABLE cares
+    ALTER COLUMN last_care TYPE TIMESTAMPTZ USING last_care AT TIME ZONE 'UTC',
+    ALTER COLUMN next_care TYPE TIMESTAMPTZ USING next_care AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN deleted_at TYPE TIMESTAMPTZ USING deleted_at AT TIME ZONE 'UTC';
+
+ALTER TABLE user_jobs
+    ALTER COLUMN run_after TYPE TIMESTAMPTZ USING run_after AT TIME ZONE 'UTC',
+    ALTER COLUMN expires_at TYPE TIMESTAMPTZ USING expires_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC';
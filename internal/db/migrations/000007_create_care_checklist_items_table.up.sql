@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS care_checklist_items (
+    id BIGSERIAL PRIMARY KEY,
+    care_id BIGINT NOT NULL,
+    position INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    checked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (care_id) REFERENCES cares(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS care_checklist_items_care_id_idx ON care_checklist_items (care_id, position);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS plants_user_id_status_idx;
+
+ALTER TABLE plants
+    DROP COLUMN IF EXISTS status_changed_at,
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS species;
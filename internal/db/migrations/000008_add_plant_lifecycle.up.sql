@@ -0,0 +1,7 @@
+ALTER TABLE plants
+    ADD COLUMN IF NOT EXISTS species VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active',
+    ADD COLUMN IF NOT EXISTS status_reason TEXT,
+    ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS plants_user_id_status_idx ON plants (user_id, status);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS care_history;
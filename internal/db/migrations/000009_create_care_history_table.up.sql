@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS care_history (
+    id BIGSERIAL PRIMARY KEY,
+    care_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    due_at TIMESTAMPTZ,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (care_id) REFERENCES cares(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS care_history_care_id_idx ON care_history (care_id, occurred_at);
+CREATE INDEX IF NOT EXISTS care_history_user_id_idx ON care_history (user_id, occurred_at);
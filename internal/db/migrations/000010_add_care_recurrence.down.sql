@@ -0,0 +1,5 @@
+ALTER TABLE cares
+    DROP COLUMN IF EXISTS exdates,
+    DROP COLUMN IF EXISTS rrule_tz,
+    DROP COLUMN IF EXISTS rrule_start,
+    DROP COLUMN IF EXISTS rrule;
@@ -0,0 +1,5 @@
+ALTER TABLE cares
+    ADD COLUMN IF NOT EXISTS rrule TEXT,
+    ADD COLUMN IF NOT EXISTS rrule_start TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS rrule_tz VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS exdates JSONB;
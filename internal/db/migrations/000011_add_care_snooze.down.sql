@@ -0,0 +1 @@
+ALTER TABLE cares DROP COLUMN IF EXISTS snoozed_from;
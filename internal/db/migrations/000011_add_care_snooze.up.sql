@@ -0,0 +1 @@
+ALTER TABLE cares ADD COLUMN IF NOT EXISTS snoozed_from TIMESTAMPTZ;
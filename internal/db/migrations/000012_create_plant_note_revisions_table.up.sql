@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS plant_note_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    plant_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    revision INT NOT NULL,
+    body TEXT NOT NULL,
+    restored_from INT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (plant_id) REFERENCES plants(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (plant_id, revision)
+);
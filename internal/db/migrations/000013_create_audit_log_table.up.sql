@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(64),
+    ip VARCHAR(45),
+    user_agent TEXT,
+    action VARCHAR(20) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id VARCHAR(64) NOT NULL,
+    owner_id BIGINT,
+    changes JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_owner_id_idx ON audit_log (owner_id, id);
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON audit_log (entity_type, entity_id);
+
+-- The audit log is append-only: rows can be inserted but never changed or
+-- removed, not even by the application.
+CREATE OR REPLACE FUNCTION trigger_audit_log_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW
+EXECUTE PROCEDURE trigger_audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT
+EXECUTE PROCEDURE trigger_audit_log_append_only();
+
+REVOKE UPDATE, DELETE, TRUNCATE ON audit_log FROM PUBLIC;
@@ -0,0 +1,2 @@
+ALTER TABLE cares DROP COLUMN IF EXISTS version;
+ALTER TABLE plants DROP COLUMN IF EXISTS version;
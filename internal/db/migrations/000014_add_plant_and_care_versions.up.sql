@@ -0,0 +1,2 @@
+ALTER TABLE plants ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE cares ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS email_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    external_id UUID NOT NULL DEFAULT uuid_generate_v1() UNIQUE,
+    recipient VARCHAR(255) NOT NULL,
+    template VARCHAR(50) NOT NULL,
+    subject TEXT NOT NULL,
+    html TEXT NOT NULL,
+    text TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    error TEXT,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS email_deliveries_queued_idx ON email_deliveries (next_attempt_at)
+    WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS email_deliveries_recipient_idx ON email_deliveries (recipient, id);
+-- Backs the per-recipient rate limit.
+CREATE INDEX IF NOT EXISTS email_deliveries_sent_idx ON email_deliveries (recipient, sent_at)
+    WHERE status = 'sent';
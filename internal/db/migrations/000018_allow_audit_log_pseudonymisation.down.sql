@@ -0,0 +1,6 @@
+CREATE OR REPLACE FUNCTION trigger_audit_log_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,22 @@
+-- Erasing a user pseudonymises their audit entries. While the
+-- audit_log.pseudonymise setting is on, which the erasure only sets for its
+-- own transaction, updates may replace the actor id, IP, user agent and
+-- changes of an entry. Everything else stays append-only.
+CREATE OR REPLACE FUNCTION trigger_audit_log_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'UPDATE' AND current_setting('audit_log.pseudonymise', true) = 'on' THEN
+    IF NEW.id = OLD.id
+       AND NEW.actor_type = OLD.actor_type
+       AND NEW.action = OLD.action
+       AND NEW.entity_type = OLD.entity_type
+       AND NEW.entity_id = OLD.entity_id
+       AND NEW.owner_id IS NOT DISTINCT FROM OLD.owner_id
+       AND NEW.created_at = OLD.created_at THEN
+      RETURN NEW;
+    END IF;
+  END IF;
+
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
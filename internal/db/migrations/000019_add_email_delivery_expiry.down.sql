@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS email_deliveries_updated_idx;
+ALTER TABLE email_deliveries DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,8 @@
+ALTER TABLE email_deliveries ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+-- Dead emails no longer keep the codes they carried.
+UPDATE email_deliveries SET html = '', text = '' WHERE status = 'dead';
+
+-- Backs the pruning of the emails that left the queue.
+CREATE INDEX IF NOT EXISTS email_deliveries_updated_idx ON email_deliveries (updated_at)
+    WHERE status <> 'queued';
@@ -0,0 +1,31 @@
+DROP FUNCTION IF EXISTS pseudonymise_audit_log(BIGINT, TEXT[], TEXT);
+
+CREATE OR REPLACE FUNCTION trigger_audit_log_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'UPDATE' AND current_setting('audit_log.pseudonymise', true) = 'on' THEN
+    IF NEW.id = OLD.id
+       AND NEW.actor_type = OLD.actor_type
+       AND NEW.action = OLD.action
+       AND NEW.entity_type = OLD.entity_type
+       AND NEW.entity_id = OLD.entity_id
+       AND NEW.owner_id IS NOT DISTINCT FROM OLD.owner_id
+       AND NEW.created_at = OLD.created_at THEN
+      RETURN NEW;
+    END IF;
+  END IF;
+
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+GRANT UPDATE ON audit_log TO CURRENT_USER;
+REVOKE ALL ON audit_log FROM audit_log_admin;
+
+DO $$
+BEGIN
+  EXECUTE format('REVOKE USAGE ON SCHEMA %I FROM audit_log_admin', current_schema());
+END
+$$;
+
+DROP ROLE IF EXISTS audit_log_admin;
@@ -0,0 +1,63 @@
+-- Pseudonymising the entries of an erased user is the only change the audit
+-- log allows. It is done by pseudonymise_audit_log, which runs as the
+-- audit_log_admin role, and the append-only trigger only lets the updates of
+-- that role through, so that no other session can rewrite the log, whatever
+-- settings it changes. The application may call the function but no longer
+-- update audit_log itself.
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'audit_log_admin') THEN
+    CREATE ROLE audit_log_admin NOLOGIN;
+  END IF;
+  EXECUTE format('GRANT USAGE ON SCHEMA %I TO audit_log_admin', current_schema());
+END
+$$;
+
+GRANT SELECT, UPDATE ON audit_log TO audit_log_admin;
+REVOKE UPDATE ON audit_log FROM CURRENT_USER;
+
+CREATE OR REPLACE FUNCTION trigger_audit_log_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'UPDATE' AND current_user = 'audit_log_admin' THEN
+    IF NEW.id = OLD.id
+       AND NEW.actor_type = OLD.actor_type
+       AND NEW.action = OLD.action
+       AND NEW.entity_type = OLD.entity_type
+       AND NEW.entity_id = OLD.entity_id
+       AND NEW.owner_id IS NOT DISTINCT FROM OLD.owner_id
+       AND NEW.created_at = OLD.created_at THEN
+      RETURN NEW;
+    END IF;
+  END IF;
+
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+-- pseudonymise_audit_log drops the values of the changes to the data the
+-- erased user owned, keeping the changed fields, and replaces the id, IP and
+-- user agent of the entries they made with pseudonym.
+CREATE OR REPLACE FUNCTION pseudonymise_audit_log(erased_owner_id BIGINT, erased_actor_ids TEXT[], pseudonym TEXT)
+RETURNS VOID
+SECURITY DEFINER
+SET search_path FROM CURRENT
+AS $$
+BEGIN
+  UPDATE audit_log SET changes = COALESCE(
+    (SELECT jsonb_object_agg(field, '{"before": null, "after": null}'::jsonb) FROM jsonb_object_keys(changes) AS field),
+    '{}'::jsonb)
+  WHERE owner_id = erased_owner_id;
+
+  UPDATE audit_log SET actor_id = pseudonym, ip = NULL, user_agent = NULL
+  WHERE actor_type = 'user' AND actor_id = ANY (erased_actor_ids);
+END;
+$$ LANGUAGE plpgsql;
+
+-- Handing the function over takes a membership of the role, which is given
+-- back right away so that the application cannot act as it.
+GRANT audit_log_admin TO CURRENT_USER;
+ALTER FUNCTION pseudonymise_audit_log(BIGINT, TEXT[], TEXT) OWNER TO audit_log_admin;
+REVOKE ALL ON FUNCTION pseudonymise_audit_log(BIGINT, TEXT[], TEXT) FROM PUBLIC;
+GRANT EXECUTE ON FUNCTION pseudonymise_audit_log(BIGINT, TEXT[], TEXT) TO CURRENT_USER;
+REVOKE audit_log_admin FROM CURRENT_USER;
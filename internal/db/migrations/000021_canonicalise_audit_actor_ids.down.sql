@@ -0,0 +1,4 @@
+-- The rewritten actor ids are still valid external ids, and the entries that
+-- carried a numeric one cannot be told apart anymore, so there is nothing to
+-- undo.
+SELECT 1;
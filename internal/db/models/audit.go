@@ -0,0 +1,45 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+)
+
+type PGAuditEntry struct {
+	Id         int64          `db:"id"`
+	ActorType  string         `db:"actor_type"`
+	ActorId    sql.NullString `db:"actor_id"`
+	IP         sql.NullString `db:"ip"`
+	UserAgent  sql.NullString `db:"user_agent"`
+	Action     string         `db:"action"`
+	EntityType string         `db:"entity_type"`
+	EntityId   string         `db:"entity_id"`
+	OwnerId    sql.NullInt64  `db:"owner_id"`
+	Changes    []byte         `db:"changes"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func PGAuditEntryToDomainAuditEntry(entry PGAuditEntry) (*domain.AuditEntry, error) {
+	e := &domain.AuditEntry{
+		Id: entry.Id,
+		Actor: domain.AuditActor{
+			Type:      entry.ActorType,
+			Id:        entry.ActorId.String,
+			IP:        entry.IP.String,
+			UserAgent: entry.UserAgent.String,
+		},
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityId:   entry.EntityId,
+		OwnerId:    entry.OwnerId.Int64,
+		CreatedAt:  entry.CreatedAt,
+	}
+
+	if err := json.Unmarshal(entry.Changes, &e.Changes); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -5,21 +5,60 @@ import (
 	"time"
 
 	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/db/drivers"
 )
 
 type PGCare struct {
-	Id          int64     `db:"id"`
-	PlantId     int64     `db:"plant_id"`
-	UserId      int64     `db:"user_id"`
-	LastCare    time.Time `db:"last_care"`
-	NextCare    time.Time `db:"next_care"`
-	Name        string    `db:"name"`
-	Notes       string    `db:"notes"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
-	DeletedAt   sql.NullTime `db:"deleted_at"`
-	Plant       *PGPlant `db:"-"`
-	User 	  	*PGUser `db:"-"`
+	Id             int64            `db:"id"`
+	PlantId        int64            `db:"plant_id"`
+	UserId         int64            `db:"user_id"`
+	LastCare       time.Time        `db:"last_care"`
+	NextCare       time.Time        `db:"next_care"`
+	Name           string           `db:"name"`
+	Notes          string           `db:"notes"`
+	IntervalDays   sql.NullInt64    `db:"interval_days"`
+	TemplateItemId sql.NullInt64    `db:"template_item_id"`
+	RRule          sql.NullString   `db:"rrule"`
+	RRuleStart     sql.NullTime     `db:"rrule_start"`
+	RRuleTimezone  sql.NullString   `db:"rrule_tz"`
+	ExDates        drivers.TimeList `db:"exdates"`
+	SnoozedFrom    sql.NullTime     `db:"snoozed_from"`
+	Version        int              `db:"version"`
+	CreatedAt      time.Time        `db:"created_at"`
+	UpdatedAt      time.Time        `db:"updated_at"`
+	DeletedAt      sql.NullTime     `db:"deleted_at"`
+	Plant          *PGPlant         `db:"-"`
+	User           *PGUser          `db:"-"`
+}
+
+func PGCareToDomainCare(care *PGCare) *domain.Care {
+	c := &domain.Care{
+		Id:             care.Id,
+		PlantId:        care.PlantId,
+		UserId:         care.UserId,
+		LastCare:       care.LastCare,
+		NextCare:       care.NextCare,
+		Name:           care.Name,
+		Notes:          care.Notes,
+		Interval:       int(care.IntervalDays.Int64),
+		TemplateItemId: care.TemplateItemId.Int64,
+		Version:        care.Version,
+		CreatedAt:      care.CreatedAt,
+		UpdatedAt:      care.UpdatedAt,
+	}
+	if care.SnoozedFrom.Valid {
+		snoozedFrom := care.SnoozedFrom.Time
+		c.SnoozedFrom = &snoozedFrom
+	}
+	if care.RRule.Valid {
+		c.Recurrence = &domain.Recurrence{
+			RRule:    care.RRule.String,
+			Start:    care.RRuleStart.Time,
+			Timezone: care.RRuleTimezone.String,
+			ExDates:  care.ExDates,
+		}
+	}
+	return c
 }
 
 func PGCaresToDomainCares(cares []*PGCare) []*domain.Care {
@@ -28,17 +67,62 @@ func PGCaresToDomainCares(cares []*PGCare) []*domain.Care {
 		return []*domain.Care{}
 	}
 	for _, care := range cares {
-		domainCares = append(domainCares, &domain.Care{
-			Id:        care.Id,
-			PlantId:   care.PlantId,
-			UserId:    care.UserId,
-			LastCare:  care.LastCare,
-			NextCare:  care.NextCare,
-			Name:      care.Name,
-			Notes:     care.Notes,
-			CreatedAt: care.CreatedAt,
-			UpdatedAt: care.UpdatedAt,
-		})
+		domainCares = append(domainCares, PGCareToDomainCare(care))
 	}
 	return domainCares
-}
\ No newline at end of file
+}
+
+type PGChecklistItem struct {
+	Id        int64        `db:"id"`
+	CareId    int64        `db:"care_id"`
+	Position  int          `db:"position"`
+	Title     string       `db:"title"`
+	CheckedAt sql.NullTime `db:"checked_at"`
+}
+
+func PGChecklistItemsToDomainChecklist(items []PGChecklistItem) []*domain.ChecklistItem {
+	checklist := make([]*domain.ChecklistItem, 0, len(items))
+	for _, item := range items {
+		i := &domain.ChecklistItem{
+			Id:      item.Id,
+			Title:   item.Title,
+			Checked: item.CheckedAt.Valid,
+		}
+		if item.CheckedAt.Valid {
+			checkedAt := item.CheckedAt.Time
+			i.CheckedAt = &checkedAt
+		}
+		checklist = append(checklist, i)
+	}
+	return checklist
+}
+
+type PGCareHistoryEntry struct {
+	Id         int64          `db:"id"`
+	CareId     int64          `db:"care_id"`
+	UserId     int64          `db:"user_id"`
+	Kind       string         `db:"kind"`
+	DueAt      sql.NullTime   `db:"due_at"`
+	OccurredAt time.Time      `db:"occurred_at"`
+	Reason     sql.NullString `db:"reason"`
+}
+
+func PGCareHistoryToDomainCareHistory(entries []PGCareHistoryEntry) []*domain.CareHistoryEntry {
+	history := make([]*domain.CareHistoryEntry, 0, len(entries))
+	for _, entry := range entries {
+		e := &domain.CareHistoryEntry{
+			Id:         entry.Id,
+			CareId:     entry.CareId,
+			UserId:     entry.UserId,
+			Kind:       entry.Kind,
+			OccurredAt: entry.OccurredAt,
+			Reason:     entry.Reason.String,
+		}
+		if entry.DueAt.Valid {
+			dueAt := entry.DueAt.Time
+			e.DueAt = &dueAt
+		}
+		history = append(history, e)
+	}
+	return history
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+)
+
+type PGCareTemplate struct {
+	Id        int64         `db:"id"`
+	UserId    sql.NullInt64 `db:"user_id"`
+	Name      string        `db:"name"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+}
+
+type PGCareTemplateItem struct {
+	Id           int64          `db:"id"`
+	TemplateId   int64          `db:"template_id"`
+	Position     int            `db:"position"`
+	Name         string         `db:"name"`
+	Notes        sql.NullString `db:"notes"`
+	IntervalDays int            `db:"interval_days"`
+	OffsetDays   int            `db:"offset_days"`
+}
+
+func PGCareTemplateToDomainCareTemplate(template PGCareTemplate, items []PGCareTemplateItem) *domain.CareTemplate {
+	t := &domain.CareTemplate{
+		Id:        template.Id,
+		UserId:    template.UserId.Int64,
+		Name:      template.Name,
+		BuiltIn:   !template.UserId.Valid,
+		Items:     []*domain.CareTemplateItem{},
+		CreatedAt: template.CreatedAt,
+		UpdatedAt: template.UpdatedAt,
+	}
+
+	for _, item := range items {
+		if item.TemplateId != template.Id {
+			continue
+		}
+
+		t.Items = append(t.Items, &domain.CareTemplateItem{
+			Id:       item.Id,
+			Name:     item.Name,
+			Notes:    item.Notes.String,
+			Interval: item.IntervalDays,
+			Offset:   item.OffsetDays,
+		})
+	}
+
+	return t
+}
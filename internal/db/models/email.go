@@ -0,0 +1,64 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+)
+
+type PGEmailDelivery struct {
+	Id            int64          `db:"id"`
+	ExternalId    string         `db:"external_id"`
+	Recipient     string         `db:"recipient"`
+	Template      string         `db:"template"`
+	Subject       string         `db:"subject"`
+	HTML          string         `db:"html"`
+	Text          string         `db:"text"`
+	Status        string         `db:"status"`
+	Attempts      int            `db:"attempts"`
+	NextAttemptAt time.Time      `db:"next_attempt_at"`
+	Error         sql.NullString `db:"error"`
+	ExpiresAt     sql.NullTime   `db:"expires_at"`
+	SentAt        sql.NullTime   `db:"sent_at"`
+	CreatedAt     time.Time      `db:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at"`
+}
+
+func PGEmailDeliveryToDomainEmailDelivery(delivery PGEmailDelivery) *domain.EmailDelivery {
+	d := &domain.EmailDelivery{
+		Id:            delivery.Id,
+		ExternalId:    delivery.ExternalId,
+		Recipient:     delivery.Recipient,
+		Template:      delivery.Template,
+		Subject:       delivery.Subject,
+		HTML:          delivery.HTML,
+		Text:          delivery.Text,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: delivery.NextAttemptAt,
+		Error:         delivery.Error.String,
+		CreatedAt:     delivery.CreatedAt,
+		UpdatedAt:     delivery.UpdatedAt,
+	}
+
+	if delivery.ExpiresAt.Valid {
+		expiresAt := delivery.ExpiresAt.Time
+		d.ExpiresAt = &expiresAt
+	}
+
+	if delivery.SentAt.Valid {
+		sentAt := delivery.SentAt.Time
+		d.SentAt = &sentAt
+	}
+
+	return d
+}
+
+func PGEmailDeliveriesToDomainEmailDeliveries(deliveries []PGEmailDelivery) []*domain.EmailDelivery {
+	domainDeliveries := make([]*domain.EmailDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		domainDeliveries = append(domainDeliveries, PGEmailDeliveryToDomainEmailDelivery(delivery))
+	}
+	return domainDeliveries
+}
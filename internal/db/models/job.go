@@ -0,0 +1,56 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+)
+
+type PGJob struct {
+	Id             int64          `db:"id"`
+	ExternalId     string         `db:"external_id"`
+	UserId         int64          `db:"user_id"`
+	UserExternalId string         `db:"user_external_id"`
+	Kind           string         `db:"kind"`
+	Status         string         `db:"status"`
+	RunAfter       time.Time      `db:"run_after"`
+	Attempts       int            `db:"attempts"`
+	Result         sql.NullString `db:"result"`
+	Error          sql.NullString `db:"error"`
+	ExpiresAt      sql.NullTime   `db:"expires_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+}
+
+func PGJobToDomainJob(job PGJob) *domain.Job {
+	j := &domain.Job{
+		Id:             job.Id,
+		ExternalId:     job.ExternalId,
+		UserId:         job.UserId,
+		UserExternalId: job.UserExternalId,
+		Kind:           job.Kind,
+		Status:         job.Status,
+		RunAfter:       job.RunAfter,
+		Attempts:       job.Attempts,
+		Result:         job.Result.String,
+		Error:          job.Error.String,
+		CreatedAt:      job.CreatedAt,
+		UpdatedAt:      job.UpdatedAt,
+	}
+
+	if job.ExpiresAt.Valid {
+		expiresAt := job.ExpiresAt.Time
+		j.ExpiresAt = &expiresAt
+	}
+
+	return j
+}
+
+func PGJobsToDomainJobs(jobs []PGJob) []*domain.Job {
+	domainJobs := make([]*domain.Job, 0, len(jobs))
+	for _, job := range jobs {
+		domainJobs = append(domainJobs, PGJobToDomainJob(job))
+	}
+	return domainJobs
+}
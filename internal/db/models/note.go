@@ -0,0 +1,38 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+)
+
+type PGNoteRevision struct {
+	Id           int64         `db:"id"`
+	PlantId      int64         `db:"plant_id"`
+	UserId       int64         `db:"user_id"`
+	Revision     int           `db:"revision"`
+	Body         string        `db:"body"`
+	RestoredFrom sql.NullInt64 `db:"restored_from"`
+	CreatedAt    time.Time     `db:"created_at"`
+}
+
+func PGNoteRevisionToDomainNoteRevision(revision PGNoteRevision) *domain.NoteRevision {
+	return &domain.NoteRevision{
+		Id:           revision.Id,
+		PlantId:      revision.PlantId,
+		UserId:       revision.UserId,
+		Revision:     revision.Revision,
+		Body:         revision.Body,
+		RestoredFrom: int(revision.RestoredFrom.Int64),
+		CreatedAt:    revision.CreatedAt,
+	}
+}
+
+func PGNoteRevisionsToDomainNoteRevisions(revisions []PGNoteRevision) []*domain.NoteRevision {
+	domainRevisions := make([]*domain.NoteRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		domainRevisions = append(domainRevisions, PGNoteRevisionToDomainNoteRevision(revision))
+	}
+	return domainRevisions
+}
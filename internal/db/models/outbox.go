@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+)
+
+type PGOutboxEvent struct {
+	Id          int64     `db:"id"`
+	EventId     string    `db:"event_id"`
+	Type        string    `db:"type"`
+	AggregateId string    `db:"aggregate_id"`
+	UserId      int64     `db:"user_id"`
+	Payload     []byte    `db:"payload"`
+	OccurredAt  time.Time `db:"occurred_at"`
+}
+
+func PGOutboxEventsToDomainEvents(events []PGOutboxEvent) []*domain.Event {
+	domainEvents := make([]*domain.Event, 0, len(events))
+	for _, event := range events {
+		domainEvents = append(domainEvents, &domain.Event{
+			Id:          event.EventId,
+			Type:        event.Type,
+			AggregateId: event.AggregateId,
+			UserId:      event.UserId,
+			Payload:     event.Payload,
+			OccurredAt:  event.OccurredAt,
+		})
+	}
+	return domainEvents
+}
@@ -3,17 +3,57 @@ package models
 import (
 	"database/sql"
 	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
 )
 
 type PGPlant struct {
-	Id               int64               `db:"id"`
-	Name             string              `db:"name"`
-	AcquisitionDate  time.Time           `db:"acquisition_date"`
-	Location         string              `db:"location"`
-	CareFrequency    int                 `db:"care_frequency"`
-	UserId           int64               `db:"user_id"`
-	CreatedAt        time.Time           `db:"created_at"`
-	UpdatedAt        time.Time           `db:"updated_at"`
-	DeletedAt        sql.NullTime        `db:"deleted_at"`
-	User             *PGUser             `db:"-"`
-}
\ No newline at end of file
+	Id              int64          `db:"id"`
+	Name            string         `db:"name"`
+	Species         sql.NullString `db:"species"`
+	AcquisitionDate time.Time      `db:"acquisition_date"`
+	Location        string         `db:"location"`
+	CareFrequency   int            `db:"care_frequency"`
+	UserId          int64          `db:"user_id"`
+	Status          string         `db:"status"`
+	StatusReason    sql.NullString `db:"status_reason"`
+	StatusChangedAt sql.NullTime   `db:"status_changed_at"`
+	Version         int            `db:"version"`
+	CreatedAt       time.Time      `db:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at"`
+	DeletedAt       sql.NullTime   `db:"deleted_at"`
+	User            *PGUser        `db:"-"`
+}
+
+func PGPlantToDomainPlant(plant PGPlant) *domain.Plant {
+	p := &domain.Plant{
+		Id:              plant.Id,
+		Name:            plant.Name,
+		Species:         plant.Species.String,
+		AcquisitionDate: plant.AcquisitionDate,
+		Location:        plant.Location,
+		CareFrequency:   plant.CareFrequency,
+		UserId:          plant.UserId,
+		Status:          plant.Status,
+		StatusReason:    plant.StatusReason.String,
+		Version:         plant.Version,
+		CreatedAt:       plant.CreatedAt,
+		UpdatedAt:       plant.UpdatedAt,
+	}
+
+	if plant.StatusChangedAt.Valid {
+		changedAt := plant.StatusChangedAt.Time
+		p.StatusChangedAt = &changedAt
+	}
+
+	return p
+}
+
+type PGPlantSurvival struct {
+	Group     string `db:"grp"`
+	Total     int    `db:"total"`
+	Active    int    `db:"active"`
+	Archived  int    `db:"archived"`
+	Deceased  int    `db:"deceased"`
+	GivenAway int    `db:"given_away"`
+}
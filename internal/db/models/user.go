@@ -15,6 +15,10 @@ type PGUser struct {
 	Active     bool                `db:"active"`
 	Verified   bool                `db:"verified"`
 	ExternalId string              `db:"external_id"`
+	Timezone   string              `db:"timezone"`
+	Locale     string              `db:"locale"`
+	QuietStart sql.NullInt16       `db:"quiet_hours_start"`
+	QuietEnd   sql.NullInt16       `db:"quiet_hours_end"`
 	CreatedAt  time.Time           `db:"created_at"`
 	UpdatedAt  time.Time           `db:"updated_at"`
 	DeletedAt  sql.NullTime        `db:"deleted_at"`
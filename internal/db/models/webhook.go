@@ -0,0 +1,92 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+)
+
+type PGWebhook struct {
+	Id        int64          `db:"id"`
+	UserId    int64          `db:"user_id"`
+	URL       string         `db:"url"`
+	Events    pq.StringArray `db:"events"`
+	Secret    string         `db:"secret"`
+	Active    bool           `db:"active"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+func PGWebhookToDomainWebhook(webhook PGWebhook) *domain.Webhook {
+	return &domain.Webhook{
+		Id:        webhook.Id,
+		UserId:    webhook.UserId,
+		URL:       webhook.URL,
+		Events:    []string(webhook.Events),
+		Secret:    webhook.Secret,
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func PGWebhooksToDomainWebhooks(webhooks []PGWebhook) []*domain.Webhook {
+	domainWebhooks := make([]*domain.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		domainWebhooks = append(domainWebhooks, PGWebhookToDomainWebhook(webhook))
+	}
+	return domainWebhooks
+}
+
+type PGWebhookDelivery struct {
+	Id             int64          `db:"id"`
+	WebhookId      int64          `db:"webhook_id"`
+	EventId        string         `db:"event_id"`
+	Event          string         `db:"event"`
+	Payload        []byte         `db:"payload"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  time.Time      `db:"next_attempt_at"`
+	ResponseStatus sql.NullInt64  `db:"response_status"`
+	Error          sql.NullString `db:"error"`
+	RedeliveryOf   sql.NullInt64  `db:"redelivery_of"`
+	DeliveredAt    sql.NullTime   `db:"delivered_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+}
+
+func PGWebhookDeliveryToDomainWebhookDelivery(delivery PGWebhookDelivery) *domain.WebhookDelivery {
+	d := &domain.WebhookDelivery{
+		Id:             delivery.Id,
+		WebhookId:      delivery.WebhookId,
+		EventId:        delivery.EventId,
+		Event:          delivery.Event,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		ResponseStatus: int(delivery.ResponseStatus.Int64),
+		Error:          delivery.Error.String,
+		RedeliveryOf:   delivery.RedeliveryOf.Int64,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+	}
+
+	if delivery.DeliveredAt.Valid {
+		deliveredAt := delivery.DeliveredAt.Time
+		d.DeliveredAt = &deliveredAt
+	}
+
+	return d
+}
+
+func PGWebhookDeliveriesToDomainWebhookDeliveries(deliveries []PGWebhookDelivery) []*domain.WebhookDelivery {
+	domainDeliveries := make([]*domain.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		domainDeliveries = append(domainDeliveries, PGWebhookDeliveryToDomainWebhookDelivery(delivery))
+	}
+	return domainDeliveries
+}
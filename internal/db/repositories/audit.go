@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/audit"
+	"github.com/mathehluiz/plant-care-tracker/internal/db/models"
+)
+
+var _ = (domain.AuditStorer)((*auditRepository)(nil))
+
+type auditRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepository(db *sqlx.DB) *auditRepository {
+	return &auditRepository{db}
+}
+
+func (r *auditRepository) CreateAuditEntry(ctx context.Context, entry *domain.AuditEntry) (int64, error) {
+	if err := insertAuditEntry(ctx, r.db, entry); err != nil {
+		return 0, err
+	}
+
+	return entry.Id, nil
+}
+
+// insertAuditEntry saves entry on db, which may be a transaction, and fills
+// in its id.
+func insertAuditEntry(ctx context.Context, db sqlx.QueryerContext, entry *domain.AuditEntry) error {
+	query := `INSERT INTO audit_log (actor_type, actor_id, ip, user_agent, action, entity_type, entity_id, owner_id, changes, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+
+	return db.QueryRowxContext(ctx, query, entry.Actor.Type, nullString(entry.Actor.Id), nullString(entry.Actor.IP),
+		nullString(entry.Actor.UserAgent), entry.Action, entry.EntityType, entry.EntityId, nullInt64(entry.OwnerId),
+		string(changes), entry.CreatedAt).Scan(&entry.Id)
+}
+
+// recordChange saves the audit entry of a change made by the actor of ctx,
+// from before to after, in tx. Repositories call it with the transaction of
+// the change, so that a change is never committed without its entry; the
+// before state is read from the row locked by that transaction.
+func recordChange(ctx context.Context, tx *sqlx.Tx, action, entityType, entityId string, ownerId int64, before, after any) error {
+	entry, err := domain.NewAuditEntry(audit.ActorFromContext(ctx), action, entityType, entityId, ownerId, before, after)
+	if err != nil || entry == nil {
+		return err
+	}
+
+	return insertAuditEntry(ctx, tx, entry)
+}
+
+func (r *auditRepository) GetAuditEntries(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	var (
+		where []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		where = append(where, strings.ReplaceAll(cond, "?", "$"+strconv.Itoa(len(args))))
+	}
+
+	if filter.OwnerId != 0 {
+		add("owner_id = ?", filter.OwnerId)
+	}
+	if filter.ActorId != "" {
+		add("actor_id = ?", filter.ActorId)
+	}
+	if filter.Action != "" {
+		add("action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		add("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityId != "" {
+		add("entity_id = ?", filter.EntityId)
+	}
+	if !filter.From.IsZero() {
+		add("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("created_at < ?", filter.To)
+	}
+	if filter.BeforeId != 0 {
+		add("id < ?", filter.BeforeId)
+	}
+
+	query := `SELECT id, actor_type, actor_id, ip, user_agent, action, entity_type, entity_id, owner_id, changes, created_at
+	FROM audit_log`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += " ORDER BY id DESC LIMIT $" + strconv.Itoa(len(args))
+
+	var entries []models.PGAuditEntry
+	if err := r.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		e, err := models.PGAuditEntryToDomainAuditEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, e)
+	}
+
+	return result, nil
+}
+
+// pseudonymiseAuditEntries redacts the audit entries of an erased user, in
+// the transaction that erases it: the changes to the data they owned lose
+// their values but keep the changed fields, and the entries they made get a
+// pseudonym, the same for all of them, in place of their id, IP and user
+// agent. audit_log is append-only for the application, so the entries are
+// rewritten by pseudonymise_audit_log, which runs as the only role the
+// append-only trigger lets update them.
+func pseudonymiseAuditEntries(ctx context.Context, tx *sqlx.Tx, user *domain.User) error {
+	actorIds := []string{user.ExternalId}
+
+	_, err := tx.ExecContext(ctx, `SELECT pseudonymise_audit_log($1, $2, $3)`,
+		user.Id, pq.Array(actorIds), "erased:"+uuid.NewString())
+	return err
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/audit"
+	"github.com/stretchr/testify/assert"
+)
+
+// openTestDB connects to the migrated database at TEST_DB_DSN, and skips the
+// test when it is not set.
+func openTestDB(t *testing.T) *sqlx.DB {
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DB_DSN is not set")
+	}
+
+	db, err := sqlx.Connect("postgres", dsn)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestAuditLogAppendOnly(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	cases := []struct {
+		purpose string
+		setup   string
+		query   string
+	}{
+		{"should reject an update", "", `UPDATE audit_log SET actor_id = 'forged' WHERE id = $1`},
+		{
+			"should reject an update with pseudonymisation turned on",
+			`SELECT set_config('audit_log.pseudonymise', 'on', true)`,
+			`UPDATE audit_log SET actor_id = 'forged' WHERE id = $1`,
+		},
+		{"should reject a delete", "", `DELETE FROM audit_log WHERE id = $1`},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			tx, err := db.BeginTxx(ctx, nil)
+			assert.NoError(t, err)
+			defer tx.Rollback()
+
+			id := insertTestAuditEntry(t, tx, 1, uuid.NewString())
+			if tt.setup != "" {
+				_, err = tx.ExecContext(ctx, tt.setup)
+				assert.NoError(t, err)
+			}
+
+			_, err = tx.ExecContext(ctx, tt.query, id)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPseudonymiseAuditEntries(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	tx, err := db.BeginTxx(ctx, nil)
+	assert.NoError(t, err)
+	defer tx.Rollback()
+
+	user := &domain.User{Id: -1, ExternalId: uuid.NewString()}
+	id := insertTestAuditEntry(t, tx, user.Id, user.ExternalId)
+
+	assert.NoError(t, pseudonymiseAuditEntries(ctx, tx, user))
+
+	var entry struct {
+		ActorId string `db:"actor_id"`
+		Changes string `db:"changes"`
+	}
+	assert.NoError(t, tx.GetContext(ctx, &entry, `SELECT actor_id, changes FROM audit_log WHERE id = $1`, id))
+	assert.Contains(t, entry.ActorId, "erased:")
+	assert.JSONEq(t, `{"name": {"before": null, "after": null}}`, entry.Changes)
+}
+
+func TestRecordChange(t *testing.T) {
+	db := openTestDB(t)
+	actor := &domain.AuditActor{Type: domain.AuditActorUser, Id: uuid.NewString()}
+	ctx := audit.WithActor(context.Background(), actor)
+
+	cases := []struct {
+		purpose string
+		before  *domain.Plant
+		after   *domain.Plant
+		want    int
+	}{
+		{"should record a change in the transaction", &domain.Plant{Name: "Fern"}, &domain.Plant{Name: "Ivy"}, 1},
+		{"should record nothing when nothing changed", &domain.Plant{Name: "Fern"}, &domain.Plant{Name: "Fern"}, 0},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			tx, err := db.BeginTxx(ctx, nil)
+			assert.NoError(t, err)
+			defer tx.Rollback()
+
+			assert.NoError(t, recordChange(ctx, tx, domain.AuditUpdate, domain.AuditEntityPlant, "1", -1, tt.before, tt.after))
+
+			var count int
+			assert.NoError(t, tx.GetContext(ctx, &count, `SELECT COUNT(*) FROM audit_log WHERE actor_id = $1`, actor.Id))
+			assert.Equal(t, tt.want, count)
+		})
+	}
+}
+
+// insertTestAuditEntry records that actorId renamed a plant of ownerId.
+func insertTestAuditEntry(t *testing.T, tx *sqlx.Tx, ownerId int64, actorId string) int64 {
+	var id int64
+	err := tx.GetContext(context.Background(), &id, `INSERT INTO audit_log (actor_type, actor_id, action, entity_type, entity_id, owner_id, changes)
+	VALUES ('user', $1, 'update', 'plant', '1', $2, '{"name": {"before": "Fern", "after": "Ivy"}}') RETURNING id`, actorId, ownerId)
+	assert.NoError(t, err)
+	return id
+}
@@ -2,15 +2,26 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/db/drivers"
 	"github.com/mathehluiz/plant-care-tracker/internal/db/models"
 	"github.com/mathehluiz/plant-care-tracker/internal/errs"
 )
 
 var _ = (domain.CareStorer)((*careRepository)(nil))
 
+const careColumns = `id, plant_id, user_id, last_care, next_care, name, notes, interval_days, template_item_id,
+	rrule, rrule_start, rrule_tz, exdates, snoozed_from, version, created_at, updated_at`
+
+const careExistsQuery = `SELECT EXISTS (SELECT 1 FROM cares WHERE id = $1)`
+
 type careRepository struct {
 	db *sqlx.DB
 }
@@ -20,21 +31,90 @@ func NewCareRepository(db *sqlx.DB) *careRepository {
 }
 
 func (r *careRepository) CreateCare(ctx context.Context, care *domain.Care) (int64, error) {
-	query := `INSERT INTO cares (plant_id, user_id, last_care, next_care, name, notes, created_at, updated_at)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	query := `INSERT INTO cares (plant_id, user_id, last_care, next_care, name, notes, interval_days,
+	rrule, rrule_start, rrule_tz, exdates, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id`
+
+	rule, start, tz, exdates := recurrenceColumns(care.Recurrence)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
 
 	var id int64
-	err := r.db.QueryRowContext(ctx, query, care.PlantId, care.UserId, care.LastCare, care.NextCare, care.Name, care.Notes, care.CreatedAt, care.UpdatedAt).Scan(&id)
+	err = tx.QueryRowContext(ctx, query, care.PlantId, care.UserId, care.LastCare, care.NextCare, care.Name, care.Notes, nullInt(care.Interval),
+		rule, start, tz, exdates, care.CreatedAt, care.UpdatedAt).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
 
+	created := *care
+	created.Id = id
+	if err := insertCareEvent(ctx, tx, domain.EventCareCreated, &created); err != nil {
+		return 0, err
+	}
+
+	if err := recordChange(ctx, tx, domain.AuditCreate, domain.AuditEntityCare, strconv.FormatInt(id, 10), care.UserId, nil, &created); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
 	return id, nil
 }
 
+// CreateCares inserts the cares in a single transaction. Cares created from a
+// template item that the plant already has are skipped, so only the ids of
+// the rows actually inserted are returned.
+func (r *careRepository) CreateCares(ctx context.Context, cares []*domain.Care) ([]int64, error) {
+	query := `INSERT INTO cares (plant_id, user_id, last_care, next_care, name, notes, interval_days, template_item_id, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	ON CONFLICT (plant_id, template_item_id) WHERE template_item_id IS NOT NULL DO NOTHING
+	RETURNING id`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ids := make([]int64, 0, len(cares))
+	for _, care := range cares {
+		var id int64
+		err := tx.QueryRowContext(ctx, query, care.PlantId, care.UserId, care.LastCare, care.NextCare, care.Name, care.Notes,
+			nullInt(care.Interval), nullInt64(care.TemplateItemId), care.CreatedAt, care.UpdatedAt).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		care.Id = id
+		ids = append(ids, id)
+
+		if err := insertCareEvent(ctx, tx, domain.EventCareCreated, care); err != nil {
+			return nil, err
+		}
+
+		if err := recordChange(ctx, tx, domain.AuditCreate, domain.AuditEntityCare, strconv.FormatInt(id, 10), care.UserId, nil, care); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
 func (r *careRepository) GetPlantCares(ctx context.Context, plantId int64) ([]*domain.Care, error) {
-	query := `SELECT id, plant_id, user_id, last_care, next_care, name, notes, created_at, updated_at
-	FROM cares WHERE plant_id = $1`
+	query := `SELECT ` + careColumns + ` FROM cares WHERE plant_id = $1`
 
 	var cares []*models.PGCare
 	err := r.db.SelectContext(ctx, &cares, query, plantId)
@@ -42,15 +122,66 @@ func (r *careRepository) GetPlantCares(ctx context.Context, plantId int64) ([]*d
 		return nil, err
 	}
 
-	return models.PGCaresToDomainCares(cares), nil
+	return withChecklists(ctx, r.db, models.PGCaresToDomainCares(cares))
+}
+
+func (r *careRepository) GetCaresByPlantIDs(ctx context.Context, plantIds []int64) ([]*domain.Care, error) {
+	query := `SELECT ` + careColumns + ` FROM cares WHERE plant_id = ANY($1) ORDER BY id`
+
+	var cares []*models.PGCare
+	err := r.db.SelectContext(ctx, &cares, query, pq.Array(plantIds))
+	if err != nil {
+		return nil, err
+	}
+
+	return withChecklists(ctx, r.db, models.PGCaresToDomainCares(cares))
+}
+
+func (r *careRepository) GetCaresByUserID(ctx context.Context, userId int64) ([]*domain.Care, error) {
+	query := `SELECT ` + careColumns + ` FROM cares WHERE user_id = $1`
+
+	var cares []*models.PGCare
+	err := r.db.SelectContext(ctx, &cares, query, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return withChecklists(ctx, r.db, models.PGCaresToDomainCares(cares))
+}
+
+func (r *careRepository) GetCaresDueBetween(ctx context.Context, from, to time.Time) ([]*domain.Care, error) {
+	query := `SELECT ` + careColumns + ` FROM cares
+	WHERE next_care > $1 AND next_care <= $2
+	AND plant_id IN (SELECT id FROM plants WHERE status = 'active' AND deleted_at IS NULL)
+	ORDER BY next_care`
+
+	var cares []*models.PGCare
+	if err := r.db.SelectContext(ctx, &cares, query, from, to); err != nil {
+		return nil, err
+	}
+
+	return withChecklists(ctx, r.db, models.PGCaresToDomainCares(cares))
 }
 
 func (r *careRepository) GetCareByID(ctx context.Context, id int64) (*domain.Care, error) {
-	query := `SELECT id, plant_id, user_id, last_care, next_care, name, notes, created_at, updated_at
-	FROM cares WHERE id = $1`
+	return getCare(ctx, r.db, `SELECT `+careColumns+` FROM cares WHERE id = $1`, id)
+}
+
+// lockCare loads the care and locks it until the end of tx. A care that is
+// gone is reported as errs.ErrNoRowsAffected, like a write that missed.
+func lockCare(ctx context.Context, tx *sqlx.Tx, id int64) (*domain.Care, error) {
+	care, err := getCare(ctx, tx, `SELECT `+careColumns+` FROM cares WHERE id = $1 FOR UPDATE`, id)
+	if errors.Is(err, errs.ErrSelectNotMatch) {
+		return nil, errs.ErrNoRowsAffected
+	}
+
+	return care, err
+}
 
-	var care[] models.PGCare
-	if err := r.db.SelectContext(ctx, &care, query, id); err != nil {
+// getCare runs a SELECT of one care on db, which may be a transaction.
+func getCare(ctx context.Context, db sqlx.QueryerContext, query string, id int64) (*domain.Care, error) {
+	var care []models.PGCare
+	if err := sqlx.SelectContext(ctx, db, &care, query, id); err != nil {
 		return nil, err
 	}
 
@@ -62,28 +193,249 @@ func (r *careRepository) GetCareByID(ctx context.Context, id int64) (*domain.Car
 		return nil, errs.ErtSelectMultipleMatch
 	}
 
-	return &domain.Care{
-		Id:       care[0].Id,
-		PlantId:  care[0].PlantId,
-		UserId:   care[0].UserId,
-		LastCare: care[0].LastCare,
-		NextCare: care[0].NextCare,
-		Name:     care[0].Name,
-		Notes:    care[0].Notes,
-		CreatedAt: care[0].CreatedAt,
-		UpdatedAt: care[0].UpdatedAt,
-	}, nil
+	cares, err := withChecklists(ctx, db, []*domain.Care{models.PGCareToDomainCare(&care[0])})
+	if err != nil {
+		return nil, err
+	}
+
+	return cares[0], nil
 }
 
-func (r *careRepository) UpdateCare(ctx context.Context, care *domain.Care) error {
-	query := `UPDATE cares SET plant_id = $1, user_id = $2, last_care = $3, next_care = $4, name = $5, notes = $6, updated_at = $7
-	WHERE id = $8`
+// withChecklists loads the checklist items of every care with one query on
+// db, which may be a transaction.
+func withChecklists(ctx context.Context, db sqlx.QueryerContext, cares []*domain.Care) ([]*domain.Care, error) {
+	if len(cares) == 0 {
+		return cares, nil
+	}
+
+	ids := make([]int64, 0, len(cares))
+	for _, care := range cares {
+		ids = append(ids, care.Id)
+	}
+
+	query := `SELECT id, care_id, position, title, checked_at
+	FROM care_checklist_items WHERE care_id = ANY($1) ORDER BY care_id, position`
+
+	var items []models.PGChecklistItem
+	if err := sqlx.SelectContext(ctx, db, &items, query, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+
+	byCare := make(map[int64][]models.PGChecklistItem, len(cares))
+	for _, item := range items {
+		byCare[item.CareId] = append(byCare[item.CareId], item)
+	}
+
+	for _, care := range cares {
+		if items, ok := byCare[care.Id]; ok {
+			care.Checklist = models.PGChecklistItemsToDomainChecklist(items)
+		}
+	}
 
-	return RunUpdateExec(ctx, r.db, query, care.PlantId, care.UserId, care.LastCare, care.NextCare, care.Name, care.Notes, care.UpdatedAt, care.Id)
+	return cares, nil
 }
 
-func (r *careRepository) DeleteCare(ctx context.Context, id int64) error {
-	query := `DELETE FROM cares WHERE id = $1`
+// UpdateCare saves the care, the checked state of its checklist items and
+// the history entries in a single transaction, if the care is still at
+// care.Version, which is then bumped.
+func (r *careRepository) UpdateCare(ctx context.Context, care *domain.Care, history ...*domain.CareHistoryEntry) error {
+	query := `UPDATE cares SET plant_id = $1, user_id = $2, last_care = $3, next_care = $4, name = $5, notes = $6,
+	rrule = $7, rrule_start = $8, rrule_tz = $9, exdates = $10, snoozed_from = $11, updated_at = $12, version = version + 1
+	WHERE id = $13 AND version = $14`
+
+	rule, start, tz, exdates := recurrenceColumns(care.Recurrence)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := lockCare(ctx, tx, care.Id)
+	if err != nil {
+		return err
+	}
 
-	return RunUpdateExec(ctx, r.db, query, id)
-}
\ No newline at end of file
+	err = RunVersionedUpdateExec(ctx, tx, careExistsQuery, care.Id, query, care.PlantId, care.UserId, care.LastCare, care.NextCare, care.Name, care.Notes,
+		rule, start, tz, exdates, care.SnoozedFrom, care.UpdatedAt, care.Id, care.Version)
+	if err != nil {
+		return err
+	}
+
+	itemQuery := `UPDATE care_checklist_items SET checked_at = $1 WHERE id = $2 AND care_id = $3`
+	for _, item := range care.Checklist {
+		if _, err := tx.ExecContext(ctx, itemQuery, item.CheckedAt, item.Id, care.Id); err != nil {
+			return err
+		}
+	}
+
+	historyQuery := `INSERT INTO care_history (care_id, user_id, kind, due_at, occurred_at, reason)
+	VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	for _, entry := range history {
+		err := tx.QueryRowContext(ctx, historyQuery, entry.CareId, entry.UserId, entry.Kind, entry.DueAt, entry.OccurredAt, nullString(entry.Reason)).
+			Scan(&entry.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	updated := *care
+	updated.Version++
+	events, err := domain.NewCareUpdateEvents(&updated, history...)
+	if err != nil {
+		return err
+	}
+
+	if err := insertOutboxEvents(ctx, tx, events...); err != nil {
+		return err
+	}
+
+	if err := recordCareUpdate(ctx, tx, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	care.Version++
+	return nil
+}
+
+func (r *careRepository) GetCareHistoryByUserID(ctx context.Context, userId int64, from, to time.Time) ([]*domain.CareHistoryEntry, error) {
+	query := `SELECT id, care_id, user_id, kind, due_at, occurred_at, reason
+	FROM care_history WHERE user_id = $1 AND occurred_at >= $2 AND occurred_at < $3
+	ORDER BY occurred_at`
+
+	var entries []models.PGCareHistoryEntry
+	if err := r.db.SelectContext(ctx, &entries, query, userId, from, to); err != nil {
+		return nil, err
+	}
+
+	return models.PGCareHistoryToDomainCareHistory(entries), nil
+}
+
+func (r *careRepository) GetCareHistory(ctx context.Context, careId int64) ([]*domain.CareHistoryEntry, error) {
+	query := `SELECT id, care_id, user_id, kind, due_at, occurred_at, reason
+	FROM care_history WHERE care_id = $1
+	ORDER BY occurred_at DESC`
+
+	var entries []models.PGCareHistoryEntry
+	if err := r.db.SelectContext(ctx, &entries, query, careId); err != nil {
+		return nil, err
+	}
+
+	return models.PGCareHistoryToDomainCareHistory(entries), nil
+}
+
+// SetChecklist replaces every checklist item of the care with care.Checklist,
+// filling in the ids of the new items. Like UpdateCare, it checks and bumps
+// care.Version.
+func (r *careRepository) SetChecklist(ctx context.Context, care *domain.Care) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := lockCare(ctx, tx, care.Id)
+	if err != nil {
+		return err
+	}
+
+	err = RunVersionedUpdateExec(ctx, tx, careExistsQuery, care.Id,
+		`UPDATE cares SET version = version + 1, updated_at = $1 WHERE id = $2 AND version = $3`, time.Now().UTC(), care.Id, care.Version)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM care_checklist_items WHERE care_id = $1`, care.Id); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO care_checklist_items (care_id, position, title, checked_at)
+	VALUES ($1, $2, $3, $4) RETURNING id`
+
+	for i, item := range care.Checklist {
+		if err := tx.QueryRowContext(ctx, query, care.Id, i, item.Title, item.CheckedAt).Scan(&item.Id); err != nil {
+			return err
+		}
+	}
+
+	updated := *care
+	updated.Version++
+	if err := insertCareEvent(ctx, tx, domain.EventCareUpdated, &updated); err != nil {
+		return err
+	}
+
+	if err := recordCareUpdate(ctx, tx, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	care.Version++
+	return nil
+}
+
+// recordCareUpdate records the change from before to the care as it now is
+// in tx.
+func recordCareUpdate(ctx context.Context, tx *sqlx.Tx, before *domain.Care) error {
+	after, err := getCare(ctx, tx, `SELECT `+careColumns+` FROM cares WHERE id = $1`, before.Id)
+	if err != nil {
+		return err
+	}
+
+	return recordChange(ctx, tx, domain.AuditUpdate, domain.AuditEntityCare, strconv.FormatInt(before.Id, 10), before.UserId, before, after)
+}
+
+func recurrenceColumns(recurrence *domain.Recurrence) (sql.NullString, sql.NullTime, sql.NullString, drivers.TimeList) {
+	if recurrence == nil {
+		return sql.NullString{}, sql.NullTime{}, sql.NullString{}, nil
+	}
+
+	return nullString(recurrence.RRule), sql.NullTime{Time: recurrence.Start, Valid: true},
+		nullString(recurrence.Timezone), drivers.TimeList(recurrence.ExDates)
+}
+
+// DeleteCare deletes the care if it is still at version, or whatever its
+// version when version is 0.
+func (r *careRepository) DeleteCare(ctx context.Context, id int64, version int) error {
+	query := `DELETE FROM cares WHERE id = $1 AND ($2 = 0 OR version = $2)`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	care, err := lockCare(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := RunVersionedUpdateExec(ctx, tx, careExistsQuery, id, query, id, version); err != nil {
+		return err
+	}
+
+	if err := insertCareEvent(ctx, tx, domain.EventCareDeleted, care); err != nil {
+		return err
+	}
+
+	if err := recordChange(ctx, tx, domain.AuditDelete, domain.AuditEntityCare, strconv.FormatInt(id, 10), care.UserId, care, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertCareEvent(ctx context.Context, tx *sqlx.Tx, eventType string, care *domain.Care) error {
+	event, err := domain.NewCareEvent(eventType, care, nil)
+	if err != nil {
+		return err
+	}
+
+	return insertOutboxEvents(ctx, tx, event)
+}
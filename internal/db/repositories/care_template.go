@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/db/models"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+var _ = (domain.CareTemplateStorer)((*careTemplateRepository)(nil))
+
+type careTemplateRepository struct {
+	db *sqlx.DB
+}
+
+func NewCareTemplateRepository(db *sqlx.DB) *careTemplateRepository {
+	return &careTemplateRepository{db}
+}
+
+func (r *careTemplateRepository) CreateCareTemplate(ctx context.Context, template *domain.CareTemplate) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO care_templates (user_id, name, created_at, updated_at)
+	VALUES ($1, $2, $3, $4) RETURNING id`
+
+	if err := tx.QueryRowContext(ctx, query, template.UserId, template.Name, template.CreatedAt, template.UpdatedAt).Scan(&template.Id); err != nil {
+		return 0, err
+	}
+
+	itemQuery := `INSERT INTO care_template_items (template_id, position, name, notes, interval_days, offset_days)
+	VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+
+	for i, item := range template.Items {
+		if err := tx.QueryRowContext(ctx, itemQuery, template.Id, i, item.Name, item.Notes, item.Interval, item.Offset).Scan(&item.Id); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return template.Id, nil
+}
+
+func (r *careTemplateRepository) GetCareTemplateByID(ctx context.Context, id int64) (*domain.CareTemplate, error) {
+	query := `SELECT id, user_id, name, created_at, updated_at FROM care_templates WHERE id = $1`
+
+	templates, err := r.getTemplates(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(templates) == 0 {
+		return nil, errs.ErrSelectNotMatch
+	}
+
+	return templates[0], nil
+}
+
+func (r *careTemplateRepository) GetCareTemplatesByUserID(ctx context.Context, userId int64) ([]*domain.CareTemplate, error) {
+	query := `SELECT id, user_id, name, created_at, updated_at FROM care_templates
+	WHERE user_id = $1 OR user_id IS NULL
+	ORDER BY user_id NULLS FIRST, name`
+
+	return r.getTemplates(ctx, query, userId)
+}
+
+func (r *careTemplateRepository) getTemplates(ctx context.Context, query string, args ...any) ([]*domain.CareTemplate, error) {
+	var templates []models.PGCareTemplate
+	if err := r.db.SelectContext(ctx, &templates, query, args...); err != nil {
+		return nil, err
+	}
+
+	if len(templates) == 0 {
+		return []*domain.CareTemplate{}, nil
+	}
+
+	ids := make([]int64, 0, len(templates))
+	for _, t := range templates {
+		ids = append(ids, t.Id)
+	}
+
+	itemQuery := `SELECT id, template_id, position, name, notes, interval_days, offset_days
+	FROM care_template_items WHERE template_id = ANY($1) ORDER BY template_id, position`
+
+	var items []models.PGCareTemplateItem
+	if err := r.db.SelectContext(ctx, &items, itemQuery, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.CareTemplate, 0, len(templates))
+	for _, t := range templates {
+		result = append(result, models.PGCareTemplateToDomainCareTemplate(t, items))
+	}
+
+	return result, nil
+}
+
+func (r *careTemplateRepository) DeleteCareTemplate(ctx context.Context, id int64) error {
+	query := `DELETE FROM care_templates WHERE id = $1 AND user_id IS NOT NULL`
+
+	return RunUpdateExec(ctx, r.db, query, id)
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/db/models"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+var _ = (domain.EmailStorer)((*emailRepository)(nil))
+
+const emailDeliveryColumns = `id, external_id, recipient, template, subject, html, text, status, attempts,
+	next_attempt_at, error, expires_at, sent_at, created_at, updated_at`
+
+type emailRepository struct {
+	db *sqlx.DB
+}
+
+func NewEmailRepository(db *sqlx.DB) *emailRepository {
+	return &emailRepository{db}
+}
+
+func (r *emailRepository) CreateEmailDelivery(ctx context.Context, d *domain.EmailDelivery) error {
+	query := `INSERT INTO email_deliveries (recipient, template, subject, html, text, status, next_attempt_at, expires_at,
+		created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, external_id`
+
+	var expiresAt sql.NullTime
+	if d.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *d.ExpiresAt, Valid: true}
+	}
+
+	return r.db.QueryRowContext(ctx, query, d.Recipient, d.Template, d.Subject, d.HTML, d.Text, d.Status, d.NextAttemptAt,
+		expiresAt, d.CreatedAt, d.UpdatedAt).Scan(&d.Id, &d.ExternalId)
+}
+
+func (r *emailRepository) GetEmailDeliveryByExternalId(ctx context.Context, id string) (*domain.EmailDelivery, error) {
+	query := `SELECT ` + emailDeliveryColumns + ` FROM email_deliveries WHERE external_id = $1`
+
+	var deliveries []models.PGEmailDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, id); err != nil {
+		return nil, err
+	}
+
+	if len(deliveries) == 0 {
+		return nil, errs.ErrSelectNotMatch
+	}
+
+	return models.PGEmailDeliveryToDomainEmailDelivery(deliveries[0]), nil
+}
+
+func (r *emailRepository) GetEmailDeliveries(ctx context.Context, filter domain.EmailFilter) ([]*domain.EmailDelivery, error) {
+	query := `SELECT ` + emailDeliveryColumns + ` FROM email_deliveries
+	WHERE ($1 = '' OR recipient = $1) AND ($2 = '' OR status = $2) AND ($3 = 0 OR id < $3)
+	ORDER BY id DESC LIMIT $4`
+
+	var deliveries []models.PGEmailDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, filter.Recipient, filter.Status, filter.BeforeId, filter.Limit); err != nil {
+		return nil, err
+	}
+
+	return models.PGEmailDeliveriesToDomainEmailDeliveries(deliveries), nil
+}
+
+func (r *emailRepository) ClaimEmailDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.EmailDelivery, error) {
+	query := `UPDATE email_deliveries SET attempts = attempts + 1, next_attempt_at = $2
+	WHERE id IN (
+		SELECT id FROM email_deliveries
+		WHERE status = 'queued' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	) RETURNING ` + emailDeliveryColumns
+
+	var deliveries []models.PGEmailDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, now, now.Add(lease), limit); err != nil {
+		return nil, err
+	}
+
+	return models.PGEmailDeliveriesToDomainEmailDeliveries(deliveries), nil
+}
+
+func (r *emailRepository) UpdateEmailDelivery(ctx context.Context, d *domain.EmailDelivery) error {
+	query := `UPDATE email_deliveries SET html = $1, text = $2, status = $3, attempts = $4, next_attempt_at = $5,
+	error = $6, sent_at = $7, updated_at = $8
+	WHERE id = $9`
+
+	var sentAt sql.NullTime
+	if d.SentAt != nil {
+		sentAt = sql.NullTime{Time: *d.SentAt, Valid: true}
+	}
+
+	return RunUpdateExec(ctx, r.db, query, d.HTML, d.Text, d.Status, d.Attempts, d.NextAttemptAt,
+		nullString(d.Error), sentAt, d.UpdatedAt, d.Id)
+}
+
+func (r *emailRepository) CountSentEmails(ctx context.Context, recipient string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM email_deliveries WHERE recipient = $1 AND status = 'sent' AND sent_at >= $2`
+
+	var count int
+	err := r.db.GetContext(ctx, &count, query, recipient, since)
+	return count, err
+}
+
+func (r *emailRepository) DeleteEmailDeliveries(ctx context.Context, before time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM email_deliveries WHERE status <> 'queued' AND updated_at < $1`, before)
+	return err
+}
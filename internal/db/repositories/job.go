@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/db/models"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+var _ = (domain.JobStorer)((*jobRepository)(nil))
+
+type jobRepository struct {
+	db *sqlx.DB
+}
+
+func NewJobRepository(db *sqlx.DB) *jobRepository {
+	return &jobRepository{db}
+}
+
+const jobColumns = `id, external_id, user_id, user_external_id, kind, status, run_after, attempts, result, error, expires_at, created_at, updated_at`
+
+func (r *jobRepository) CreateJob(ctx context.Context, job *domain.Job) (string, error) {
+	query := `INSERT INTO user_jobs (user_id, user_external_id, kind, status, run_after, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, external_id`
+
+	err := r.db.QueryRowContext(ctx, query, job.UserId, job.UserExternalId, job.Kind, job.Status, job.RunAfter, job.CreatedAt, job.UpdatedAt).
+		Scan(&job.Id, &job.ExternalId)
+	if err != nil {
+		return "", err
+	}
+
+	return job.ExternalId, nil
+}
+
+func (r *jobRepository) GetJobByExternalId(ctx context.Context, id string) (*domain.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM user_jobs WHERE external_id = $1`
+
+	return r.getJob(ctx, query, id)
+}
+
+func (r *jobRepository) GetPendingJobByUser(ctx context.Context, userExternalId, kind string) (*domain.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM user_jobs
+	WHERE user_external_id = $1 AND kind = $2 AND status IN ('pending', 'running')
+	ORDER BY created_at DESC LIMIT 1`
+
+	return r.getJob(ctx, query, userExternalId, kind)
+}
+
+func (r *jobRepository) GetJobsByUserID(ctx context.Context, userId int64) ([]*domain.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM user_jobs WHERE user_id = $1 ORDER BY id`
+
+	var jobs []models.PGJob
+	if err := r.db.SelectContext(ctx, &jobs, query, userId); err != nil {
+		return nil, err
+	}
+
+	return models.PGJobsToDomainJobs(jobs), nil
+}
+
+func (r *jobRepository) getJob(ctx context.Context, query string, args ...any) (*domain.Job, error) {
+	var jobs []models.PGJob
+	if err := r.db.SelectContext(ctx, &jobs, query, args...); err != nil {
+		return nil, err
+	}
+
+	if len(jobs) == 0 {
+		return nil, errs.ErrSelectNotMatch
+	}
+
+	return models.PGJobToDomainJob(jobs[0]), nil
+}
+
+// ClaimPendingJobs atomically moves up to limit due jobs to the running state
+// so that several server replicas never pick up the same job. While a job
+// runs, run_after holds the end of its lease.
+func (r *jobRepository) ClaimPendingJobs(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.Job, error) {
+	query := `UPDATE user_jobs SET status = 'running', attempts = attempts + 1, run_after = $2
+	WHERE id IN (
+		SELECT id FROM user_jobs
+		WHERE status IN ('pending', 'running') AND run_after <= $1
+		ORDER BY run_after
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	) RETURNING ` + jobColumns
+
+	var jobs []models.PGJob
+	if err := r.db.SelectContext(ctx, &jobs, query, now, now.Add(lease), limit); err != nil {
+		return nil, err
+	}
+
+	return models.PGJobsToDomainJobs(jobs), nil
+}
+
+func (r *jobRepository) GetExpiredJobs(ctx context.Context, now time.Time) ([]*domain.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM user_jobs
+	WHERE status = 'done' AND expires_at IS NOT NULL AND expires_at <= $1`
+
+	var jobs []models.PGJob
+	if err := r.db.SelectContext(ctx, &jobs, query, now); err != nil {
+		return nil, err
+	}
+
+	return models.PGJobsToDomainJobs(jobs), nil
+}
+
+func (r *jobRepository) UpdateJob(ctx context.Context, job *domain.Job) error {
+	query := `UPDATE user_jobs SET status = $1, run_after = $2, attempts = $3, result = $4, error = $5, expires_at = $6, updated_at = $7
+	WHERE id = $8`
+
+	var expiresAt sql.NullTime
+	if job.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *job.ExpiresAt, Valid: true}
+	}
+
+	return RunUpdateExec(ctx, r.db, query, job.Status, job.RunAfter, job.Attempts, nullString(job.Result), nullString(job.Error), expiresAt, job.UpdatedAt, job.Id)
+}
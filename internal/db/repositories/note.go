@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/db/models"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+var _ = (domain.NoteStorer)((*noteRepository)(nil))
+
+const noteColumns = `id, plant_id, user_id, revision, body, restored_from, created_at`
+
+type noteRepository struct {
+	db *sqlx.DB
+}
+
+func NewNoteRepository(db *sqlx.DB) *noteRepository {
+	return &noteRepository{db}
+}
+
+func (r *noteRepository) CreateNoteRevision(ctx context.Context, revision *domain.NoteRevision) (int64, error) {
+	query := `INSERT INTO plant_note_revisions (plant_id, user_id, revision, body, restored_from, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+
+	var id int64
+	err := r.db.QueryRowContext(ctx, query, revision.PlantId, revision.UserId, revision.Revision, revision.Body,
+		nullInt(revision.RestoredFrom), revision.CreatedAt).Scan(&id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return 0, errs.ErrNoteConflict
+		}
+		return 0, err
+	}
+
+	revision.Id = id
+	return id, nil
+}
+
+func (r *noteRepository) GetLatestNoteRevision(ctx context.Context, plantId int64) (*domain.NoteRevision, error) {
+	query := `SELECT ` + noteColumns + ` FROM plant_note_revisions
+	WHERE plant_id = $1 ORDER BY revision DESC LIMIT 1`
+
+	return r.getNoteRevision(ctx, query, plantId)
+}
+
+func (r *noteRepository) GetNoteRevision(ctx context.Context, plantId int64, revision int) (*domain.NoteRevision, error) {
+	query := `SELECT ` + noteColumns + ` FROM plant_note_revisions
+	WHERE plant_id = $1 AND revision = $2`
+
+	return r.getNoteRevision(ctx, query, plantId, revision)
+}
+
+func (r *noteRepository) getNoteRevision(ctx context.Context, query string, args ...any) (*domain.NoteRevision, error) {
+	var revisions []models.PGNoteRevision
+	if err := r.db.SelectContext(ctx, &revisions, query, args...); err != nil {
+		return nil, err
+	}
+
+	if len(revisions) == 0 {
+		return nil, errs.ErrSelectNotMatch
+	}
+
+	return models.PGNoteRevisionToDomainNoteRevision(revisions[0]), nil
+}
+
+func (r *noteRepository) GetNoteRevisions(ctx context.Context, plantId int64) ([]*domain.NoteRevision, error) {
+	query := `SELECT ` + noteColumns + ` FROM plant_note_revisions
+	WHERE plant_id = $1 ORDER BY revision DESC`
+
+	var revisions []models.PGNoteRevision
+	if err := r.db.SelectContext(ctx, &revisions, query, plantId); err != nil {
+		return nil, err
+	}
+
+	return models.PGNoteRevisionsToDomainNoteRevisions(revisions), nil
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/db/models"
+)
+
+var _ = (domain.OutboxStorer)((*outboxRepository)(nil))
+
+type outboxRepository struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) *outboxRepository {
+	return &outboxRepository{db}
+}
+
+func (r *outboxRepository) CreateOutboxEvents(ctx context.Context, events ...*domain.Event) error {
+	return insertOutboxEvents(ctx, r.db, events...)
+}
+
+func (r *outboxRepository) PublishOutboxEvents(ctx context.Context, limit int, publish func(ctx context.Context, events []*domain.Event) error) (int, error) {
+	query := `SELECT id, event_id, type, aggregate_id, user_id, payload, occurred_at
+	FROM outbox WHERE published_at IS NULL
+	ORDER BY id
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var rows []models.PGOutboxEvent
+	if err := tx.SelectContext(ctx, &rows, query, limit); err != nil {
+		return 0, err
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	if err := publish(ctx, models.PGOutboxEventsToDomainEvents(rows)); err != nil {
+		return 0, err
+	}
+
+	ids := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.Id)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE outbox SET published_at = $1 WHERE id = ANY($2)`, time.Now().UTC(), pq.Array(ids)); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(rows), nil
+}
+
+func (r *outboxRepository) DeletePublishedOutboxEvents(ctx context.Context, before time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM outbox WHERE published_at < $1`, before)
+	return err
+}
+
+// insertOutboxEvents saves events to the outbox, skipping the ones already
+// saved. Repositories call it with the transaction of the change that raised
+// the events, so that they are only published if the change is committed.
+func insertOutboxEvents(ctx context.Context, db sqlx.ExecerContext, events ...*domain.Event) error {
+	query := `INSERT INTO outbox (event_id, type, aggregate_id, user_id, payload, occurred_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (event_id) DO NOTHING`
+
+	for _, event := range events {
+		_, err := db.ExecContext(ctx, query, event.Id, event.Type, event.AggregateId, event.UserId, []byte(event.Payload), event.OccurredAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
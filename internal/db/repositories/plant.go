@@ -2,9 +2,12 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/mathehluiz/plant-care-tracker/domain"
 	"github.com/mathehluiz/plant-care-tracker/internal/db/models"
 	"github.com/mathehluiz/plant-care-tracker/internal/errs"
@@ -20,25 +23,66 @@ func NewPlantRepository(db *sqlx.DB) *plantRepository {
 	return &plantRepository{db}
 }
 
+const plantColumns = `id, name, species, acquisition_date, location, care_frequency, user_id, status, status_reason, status_changed_at, version, created_at, updated_at, deleted_at`
+
 func (p *plantRepository) CreatePlant(ctx context.Context, plant *domain.Plant) (int64, error) {
-	insertQuery := `INSERT INTO plants (name, acquisition_date, location, care_frequency, user_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`
+	insertQuery := `INSERT INTO plants (name, species, acquisition_date, location, care_frequency, user_id, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id;`
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
 
 	var id int64
-	err := p.db.QueryRowxContext(ctx, insertQuery, plant.Name, plant.AcquisitionDate, plant.Location, plant.CareFrequency, plant.UserId, plant.CreatedAt, plant.UpdatedAt).Scan(&id)
+	err = tx.QueryRowxContext(ctx, insertQuery, plant.Name, nullString(plant.Species), plant.AcquisitionDate, plant.Location, plant.CareFrequency, plant.UserId, plant.Status, plant.CreatedAt, plant.UpdatedAt).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
 
+	created := *plant
+	created.Id = id
+	if err := insertPlantEvent(ctx, tx, domain.EventPlantCreated, &created); err != nil {
+		return 0, err
+	}
+
+	if err := recordChange(ctx, tx, domain.AuditCreate, domain.AuditEntityPlant, strconv.FormatInt(id, 10), plant.UserId, nil, &created); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
 	return id, nil
 }
 
 func (p *plantRepository) GetPlantByID(ctx context.Context, id int64) (*domain.Plant, error) {
-	selectQuery := `SELECT id, name, acquisition_date, location, care_frequency, user_id, created_at, updated_at, deleted_at
-		FROM plants WHERE id = $1;`
+	return getPlant(ctx, p.db, id)
+}
+
+// getPlant loads the plant on db, which may be a transaction.
+func getPlant(ctx context.Context, db sqlx.QueryerContext, id int64) (*domain.Plant, error) {
+	return selectPlant(ctx, db, `SELECT `+plantColumns+`
+		FROM plants WHERE id = $1;`, id)
+}
 
+// lockPlant loads the plant and locks it until the end of tx. A plant that
+// is gone is reported as errs.ErrNoRowsAffected, like a write that missed.
+func lockPlant(ctx context.Context, tx *sqlx.Tx, id int64) (*domain.Plant, error) {
+	plant, err := selectPlant(ctx, tx, `SELECT `+plantColumns+`
+		FROM plants WHERE id = $1 FOR UPDATE;`, id)
+	if errors.Is(err, errs.ErrSelectNotMatch) {
+		return nil, errs.ErrNoRowsAffected
+	}
+
+	return plant, err
+}
+
+func selectPlant(ctx context.Context, db sqlx.QueryerContext, selectQuery string, id int64) (*domain.Plant, error) {
 	var plant []models.PGPlant
-	if err := p.db.SelectContext(ctx, &plant, selectQuery, id); err != nil {
+	if err := sqlx.SelectContext(ctx, db, &plant, selectQuery, id); err != nil {
 		return nil, err
 	}
 
@@ -50,54 +94,180 @@ func (p *plantRepository) GetPlantByID(ctx context.Context, id int64) (*domain.P
 		return nil, errs.ErtSelectMultipleMatch
 	}
 
-	return &domain.Plant{
-		Id:              plant[0].Id,
-		Name:            plant[0].Name,
-		AcquisitionDate: plant[0].AcquisitionDate,
-		Location:        plant[0].Location,
-		CareFrequency:   plant[0].CareFrequency,
-		UserId:          plant[0].UserId,
-		CreatedAt:       plant[0].CreatedAt,
-		UpdatedAt:       plant[0].UpdatedAt,
-	}, nil
+	return models.PGPlantToDomainPlant(plant[0]), nil
+}
+
+func (p *plantRepository) GetPlantsByIDs(ctx context.Context, ids []int64) ([]*domain.Plant, error) {
+	selectQuery := `SELECT ` + plantColumns + `
+		FROM plants WHERE id = ANY($1) AND deleted_at IS NULL;`
+
+	var plants []models.PGPlant
+	if err := p.db.SelectContext(ctx, &plants, selectQuery, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+
+	domainPlants := make([]*domain.Plant, 0, len(plants))
+	for _, plant := range plants {
+		domainPlants = append(domainPlants, models.PGPlantToDomainPlant(plant))
+	}
+
+	return domainPlants, nil
 }
 
-func (p *plantRepository) GetPlantsByUserID(ctx context.Context, userID int64) ([]*domain.Plant, error) {
-	selectQuery := `SELECT id, name, acquisition_date, location, care_frequency, user_id, created_at, updated_at, deleted_at
-		FROM plants WHERE user_id = $1;`
+func (p *plantRepository) GetPlantsByUserID(ctx context.Context, userID int64, statuses ...string) ([]*domain.Plant, error) {
+	selectQuery := `SELECT ` + plantColumns + `
+		FROM plants WHERE user_id = $1 AND (cardinality($2::text[]) = 0 OR status = ANY($2));`
+
+	if statuses == nil {
+		statuses = []string{}
+	}
 
 	var plants []models.PGPlant
-	if err := p.db.SelectContext(ctx, &plants, selectQuery, userID); err != nil {
+	if err := p.db.SelectContext(ctx, &plants, selectQuery, userID, pq.Array(statuses)); err != nil {
 		return nil, err
 	}
 
 	var domainPlants []*domain.Plant
 	for _, plant := range plants {
-		domainPlants = append(domainPlants, &domain.Plant{
-			Id:              plant.Id,
-			Name:            plant.Name,
-			AcquisitionDate: plant.AcquisitionDate,
-			Location:        plant.Location,
-			CareFrequency:   plant.CareFrequency,
-			UserId:          plant.UserId,
-			CreatedAt:       plant.CreatedAt,
-			UpdatedAt:       plant.UpdatedAt,
-		})
+		domainPlants = append(domainPlants, models.PGPlantToDomainPlant(plant))
 	}
 
 	return domainPlants, nil
 }
 
+const plantExistsQuery = `SELECT EXISTS (SELECT 1 FROM plants WHERE id = $1 AND deleted_at IS NULL);`
+
+// UpdatePlant saves the plant if it is still at plant.Version, which is then
+// bumped.
 func (p *plantRepository) UpdatePlant(ctx context.Context, plant *domain.Plant) error {
-	updateQuery := `UPDATE plants SET name = $1, acquisition_date = $2, location = $3, care_frequency = $4, updated_at = $5
-		WHERE id = $6;`
+	updateQuery := `UPDATE plants SET name = $1, species = $2, acquisition_date = $3, location = $4, care_frequency = $5,
+		status = $6, status_reason = $7, status_changed_at = $8, updated_at = $9, version = version + 1
+		WHERE id = $10 AND version = $11 AND deleted_at IS NULL;`
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := lockPlant(ctx, tx, plant.Id)
+	if err != nil {
+		return err
+	}
+
+	err = RunVersionedUpdateExec(ctx, tx, plantExistsQuery, plant.Id, updateQuery, plant.Name, nullString(plant.Species), plant.AcquisitionDate,
+		plant.Location, plant.CareFrequency, plant.Status, nullString(plant.StatusReason), plant.StatusChangedAt, plant.UpdatedAt, plant.Id, plant.Version)
+	if err != nil {
+		return err
+	}
 
-	return RunUpdateExec(ctx, p.db, updateQuery, plant.Name, plant.AcquisitionDate, plant.Location, plant.CareFrequency, plant.UpdatedAt, plant.Id)
+	updated := *plant
+	updated.Version++
+	if err := insertPlantEvent(ctx, tx, domain.EventPlantUpdated, &updated); err != nil {
+		return err
+	}
+
+	after, err := getPlant(ctx, tx, plant.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := recordChange(ctx, tx, domain.AuditUpdate, domain.AuditEntityPlant, strconv.FormatInt(plant.Id, 10), before.UserId, before, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	plant.Version++
+	return nil
 }
 
-func (p *plantRepository) DeletePlant(ctx context.Context, id int64) error {
-	deleteQuery := `UPDATE plants SET deleted_at = $1 WHERE id = $2;`
-    now := time.Now()
-	
-	return RunUpdateExec(ctx, p.db, deleteQuery, now, id)
-}
\ No newline at end of file
+// DeletePlant deletes the plant if it is still at version, or whatever its
+// version when version is 0.
+func (p *plantRepository) DeletePlant(ctx context.Context, id int64, version int) error {
+	deleteQuery := `UPDATE plants SET deleted_at = $1 WHERE id = $2 AND ($3 = 0 OR version = $3) AND deleted_at IS NULL;`
+	now := time.Now()
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := lockPlant(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := RunVersionedUpdateExec(ctx, tx, plantExistsQuery, id, deleteQuery, now, id, version); err != nil {
+		return err
+	}
+
+	plant, err := getPlant(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := insertPlantEvent(ctx, tx, domain.EventPlantDeleted, plant); err != nil {
+		return err
+	}
+
+	if err := recordChange(ctx, tx, domain.AuditDelete, domain.AuditEntityPlant, strconv.FormatInt(id, 10), before.UserId, before, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertPlantEvent(ctx context.Context, tx *sqlx.Tx, eventType string, plant *domain.Plant) error {
+	event, err := domain.NewPlantEvent(eventType, plant)
+	if err != nil {
+		return err
+	}
+
+	return insertOutboxEvents(ctx, tx, event)
+}
+
+var survivalGroupColumns = map[string]string{
+	"species":  "COALESCE(NULLIF(species, ''), 'unknown')",
+	"location": "COALESCE(NULLIF(location, ''), 'unknown')",
+}
+
+func (p *plantRepository) GetPlantSurvival(ctx context.Context, userID int64, groupBy string) ([]*domain.PlantSurvival, error) {
+	column, ok := survivalGroupColumns[groupBy]
+	if !ok {
+		return nil, errs.ErrInvalidGroupBy
+	}
+
+	selectQuery := `SELECT ` + column + ` AS grp,
+		COUNT(*) AS total,
+		COUNT(*) FILTER (WHERE status = 'active') AS active,
+		COUNT(*) FILTER (WHERE status = 'archived') AS archived,
+		COUNT(*) FILTER (WHERE status = 'deceased') AS deceased,
+		COUNT(*) FILTER (WHERE status = 'given_away') AS given_away
+		FROM plants WHERE user_id = $1 AND deleted_at IS NULL
+		GROUP BY grp ORDER BY grp;`
+
+	var rows []models.PGPlantSurvival
+	if err := p.db.SelectContext(ctx, &rows, selectQuery, userID); err != nil {
+		return nil, err
+	}
+
+	stats := make([]*domain.PlantSurvival, 0, len(rows))
+	for _, row := range rows {
+		s := &domain.PlantSurvival{
+			Group:     row.Group,
+			Total:     row.Total,
+			Active:    row.Active,
+			Archived:  row.Archived,
+			Deceased:  row.Deceased,
+			GivenAway: row.GivenAway,
+		}
+		s.ComputeSurvivalRate()
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
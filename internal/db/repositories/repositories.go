@@ -2,11 +2,16 @@ package repositories
 
 import (
 	"context"
-	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"database/sql"
+	"errors"
+
 	"github.com/jmoiron/sqlx"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
 )
 
-func RunUpdateExec(ctx context.Context, db *sqlx.DB, query string, args ...any) error {
+// RunUpdateExec runs an UPDATE or DELETE that must affect at least one row,
+// returning errs.ErrNoRowsAffected otherwise.
+func RunUpdateExec(ctx context.Context, db sqlx.ExecerContext, query string, args ...any) error {
 	changes, err := db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
@@ -23,3 +28,37 @@ func RunUpdateExec(ctx context.Context, db *sqlx.DB, query string, args ...any)
 
 	return nil
 }
+
+// RunVersionedUpdateExec runs a write guarded by an optimistic concurrency
+// check, such as "WHERE id = $1 AND version = $2". When no row is affected,
+// existsQuery, which takes id as its only argument, tells a row that is gone,
+// errs.ErrNoRowsAffected, from one that was changed in the meantime,
+// errs.ErrVersionConflict.
+func RunVersionedUpdateExec(ctx context.Context, db sqlx.ExtContext, existsQuery string, id int64, query string, args ...any) error {
+	err := RunUpdateExec(ctx, db, query, args...)
+	if !errors.Is(err, errs.ErrNoRowsAffected) {
+		return err
+	}
+
+	var exists bool
+	if err := sqlx.GetContext(ctx, db, &exists, existsQuery, id); err != nil {
+		return err
+	}
+
+	if exists {
+		return errs.ErrVersionConflict
+	}
+	return errs.ErrNoRowsAffected
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullInt(i int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(i), Valid: i != 0}
+}
+
+func nullInt64(i int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: i, Valid: i != 0}
+}
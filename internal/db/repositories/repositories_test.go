@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+// versionsDriver is a database/sql driver over a table of row versions by
+// id. Every write is taken for "UPDATE ... SET version = version + 1 WHERE
+// id = $1 AND version = $2", and every query for the exists query of the id.
+type versionsDriver struct {
+	versions map[int64]int64
+}
+
+func (d *versionsDriver) Connect(ctx context.Context) (driver.Conn, error) { return d, nil }
+func (d *versionsDriver) Driver() driver.Driver                            { return nil }
+
+func (d *versionsDriver) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+func (d *versionsDriver) Close() error              { return nil }
+func (d *versionsDriver) Begin() (driver.Tx, error) { return nil, errors.New("not implemented") }
+
+func (d *versionsDriver) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	id, version := args[0].Value.(int64), args[1].Value.(int64)
+	if current, ok := d.versions[id]; !ok || current != version {
+		return driver.RowsAffected(0), nil
+	}
+	d.versions[id]++
+	return driver.RowsAffected(1), nil
+}
+
+func (d *versionsDriver) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	_, exists := d.versions[args[0].Value.(int64)]
+	return &existsRows{exists: exists}, nil
+}
+
+type existsRows struct {
+	exists bool
+	read   bool
+}
+
+func (r *existsRows) Columns() []string { return []string{"exists"} }
+func (r *existsRows) Close() error      { return nil }
+
+func (r *existsRows) Next(dest []driver.Value) error {
+	if r.read {
+		return io.EOF
+	}
+	r.read = true
+	dest[0] = r.exists
+	return nil
+}
+
+func TestRunVersionedUpdateExec(t *testing.T) {
+	const (
+		existsQuery = `SELECT EXISTS (SELECT 1 FROM plants WHERE id = $1)`
+		updateQuery = `UPDATE plants SET version = version + 1 WHERE id = $1 AND version = $2`
+	)
+
+	cases := []struct {
+		purpose     string
+		id          int64
+		version     int
+		wantErr     error
+		wantVersion int64
+	}{
+		{"should update a row at the expected version and bump it", 1, 3, nil, 4},
+		{"should report a row changed in the meantime as a conflict", 1, 2, errs.ErrVersionConflict, 3},
+		{"should report a row that is gone", 2, 1, errs.ErrNoRowsAffected, 0},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			d := &versionsDriver{versions: map[int64]int64{1: 3}}
+			db := sqlx.NewDb(sql.OpenDB(d), "postgres")
+			defer db.Close()
+
+			err := RunVersionedUpdateExec(context.Background(), db, existsQuery, tt.id, updateQuery, tt.id, tt.version)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantVersion, d.versions[tt.id])
+		})
+	}
+}
@@ -2,6 +2,9 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"strconv"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/mathehluiz/plant-care-tracker/domain"
@@ -21,23 +24,28 @@ func NewUserRepository(db *sqlx.DB) *userRepository {
 }
 
 func (u *userRepository) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
-	selectQuery := `SELECT id, external_id, email, username, password, roles, active, verified
+	selectQuery := `SELECT id, external_id, email, username, password, roles, active, verified, timezone, locale, quiet_hours_start, quiet_hours_end
 		FROM users WHERE username = $1;`
 
 	return u.getUser(ctx, selectQuery, username)
 }
 
 func (u *userRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
-	selectQuery := `SELECT id, external_id, email, username, password, roles, active, verified
+	selectQuery := `SELECT id, external_id, email, username, password, roles, active, verified, timezone, locale, quiet_hours_start, quiet_hours_end
 		FROM users WHERE email = $1;`
 
 	return u.getUser(ctx, selectQuery, email)
 }
 
-func (u *userRepository) getUser(ctx context.Context, query string, args string) (*domain.User, error) {
+func (u *userRepository) getUser(ctx context.Context, query string, arg any) (*domain.User, error) {
+	return getUser(ctx, u.db, query, arg)
+}
+
+// getUser runs a user SELECT on db, which may be a transaction.
+func getUser(ctx context.Context, db sqlx.QueryerContext, query string, arg any) (*domain.User, error) {
 	var user []models.PGUser
 
-	if err := u.db.SelectContext(ctx, &user, query, args); err != nil {
+	if err := sqlx.SelectContext(ctx, db, &user, query, arg); err != nil {
 		return nil, err
 	}
 
@@ -49,7 +57,7 @@ func (u *userRepository) getUser(ctx context.Context, query string, args string)
 		return nil, errs.ErtSelectMultipleMatch
 	}
 
-	return &domain.User{
+	domainUser := &domain.User{
 		Id:         user[0].Id,
 		ExternalId: user[0].ExternalId,
 		Email:      user[0].Email,
@@ -58,101 +66,303 @@ func (u *userRepository) getUser(ctx context.Context, query string, args string)
 		Roles:      user[0].Roles,
 		Active:     user[0].Active,
 		Verified:   user[0].Verified,
-	}, nil
+		Timezone:   user[0].Timezone,
+		Locale:     user[0].Locale,
+	}
+
+	if user[0].QuietStart.Valid && user[0].QuietEnd.Valid {
+		domainUser.QuietHours = domain.QuietHoursFromMinutes(int(user[0].QuietStart.Int16), int(user[0].QuietEnd.Int16))
+	}
+
+	return domainUser, nil
 }
 
-func (u *userRepository) GetUserByExternalId(ctx context.Context, id string) (*domain.User, error) {
-	selectQuery := `SELECT id, external_id, email, username, password, roles, active, verified
+const userByExternalIdQuery = `SELECT id, external_id, email, username, password, roles, active, verified, timezone, locale, quiet_hours_start, quiet_hours_end
 		FROM users WHERE external_id = $1;`
 
-	return u.getUser(ctx, selectQuery, id)
+// userLockQuery reads the user with the given external id and locks it until
+// the end of the transaction.
+const userLockQuery = `SELECT id, external_id, email, username, password, roles, active, verified, timezone, locale, quiet_hours_start, quiet_hours_end
+		FROM users WHERE external_id = $1 FOR UPDATE;`
+
+const userByIdQuery = `SELECT id, external_id, email, username, password, roles, active, verified, timezone, locale, quiet_hours_start, quiet_hours_end
+		FROM users WHERE id = $1;`
+
+func (u *userRepository) GetUserByExternalId(ctx context.Context, id string) (*domain.User, error) {
+	return u.getUser(ctx, userByExternalIdQuery, id)
+}
+
+func (u *userRepository) GetUserByID(ctx context.Context, id int64) (*domain.User, error) {
+	return u.getUser(ctx, userByIdQuery, id)
 }
 
 func (u *userRepository) AddRolesToUser(ctx context.Context, id string, roles []string) error {
-	user, err := u.GetUserByExternalId(ctx, id)
+	return u.changeUser(ctx, id, domain.AuditUpdate, "", func(tx *sqlx.Tx, user *domain.User) error {
+		newRoles := make([]string, 0)
+
+		for _, role := range roles {
+			exists := false
+			for _, r := range user.Roles {
+				if role == r {
+					exists = true
+				}
+			}
+			if !exists {
+				newRoles = append(newRoles, role)
+			}
+		}
+
+		if len(newRoles) == 0 {
+			return nil
+		}
+
+		updateQuery := `UPDATE users SET roles = $1 WHERE external_id = $2;`
+
+		return RunUpdateExec(ctx, tx, updateQuery, drivers.StringArray(append(user.Roles, newRoles...)), id)
+	})
+}
+
+func (u *userRepository) RemoveRolesFromUser(ctx context.Context, id string, roles []string) error {
+	return u.changeUser(ctx, id, domain.AuditUpdate, "", func(tx *sqlx.Tx, user *domain.User) error {
+		newRoles := make([]string, 0)
+
+		for _, role := range user.Roles {
+			for _, r := range roles {
+				if role != r {
+					newRoles = append(newRoles, role)
+				}
+			}
+		}
+
+		updateQuery := `UPDATE users SET roles = $1 WHERE external_id = $2;`
+
+		return RunUpdateExec(ctx, tx, updateQuery, drivers.StringArray(newRoles), id)
+	})
+}
+
+func (u *userRepository) DeleteUser(ctx context.Context, id string) error {
+	deleteQuery := `UPDATE users SET deleted_at = NOW(), active = false WHERE external_id = $1;`
+
+	return u.changeUser(ctx, id, domain.AuditDelete, domain.EventUserDeleted, func(tx *sqlx.Tx, _ *domain.User) error {
+		return RunUpdateExec(ctx, tx, deleteQuery, id)
+	})
+}
+
+// changeUser locks the user with the given external id and runs update on
+// it. In the same transaction, it records the change in the audit log under
+// action and, unless eventType is empty, saves an eventType event of the
+// updated user.
+func (u *userRepository) changeUser(ctx context.Context, id, action, eventType string, update func(tx *sqlx.Tx, user *domain.User) error) error {
+	tx, err := u.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	newRoles := make([]string, 0)
+	before, err := getUser(ctx, tx, userLockQuery, id)
+	if err != nil {
+		return err
+	}
 
-	for _, role := range roles {
-		exists := false
-		for _, r := range user.Roles {
-			if role == r {
-				exists = true
-			}
-		}
-		if !exists {
-			newRoles = append(newRoles, role)
-		}
+	if err := update(tx, before); err != nil {
+		return err
+	}
+
+	user, err := getUser(ctx, tx, userByExternalIdQuery, id)
+	if err != nil {
+		return err
 	}
 
-	if len(newRoles) == 0 {
-		return nil
+	var after any = user
+	if action == domain.AuditDelete {
+		after = nil
 	}
 
-	updateQuery := `UPDATE users SET roles = $1 WHERE external_id = $2;`
+	if err := recordChange(ctx, tx, action, domain.AuditEntityUser, id, before.Id, before, after); err != nil {
+		return err
+	}
 
-	return RunUpdateExec(ctx, u.db, updateQuery, drivers.StringArray(append(user.Roles, newRoles...)), id)
+	if eventType != "" {
+		event, err := domain.NewUserEvent(eventType, user)
+		if err != nil {
+			return err
+		}
+
+		if err := insertOutboxEvents(ctx, tx, event); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
-func (u *userRepository) RemoveRolesFromUser(ctx context.Context, id string, roles []string) error {
-	user, err := u.GetUserByExternalId(ctx, id)
+// EraseUser permanently removes the user and every row they own, along with
+// the events and jobs kept about them, and pseudonymises their audit
+// entries. Unlike DeleteUser it cannot be undone.
+func (u *userRepository) EraseUser(ctx context.Context, id int64) error {
+	tx, err := u.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	newRoles := make([]string, 0)
+	user, err := getUser(ctx, tx, userByIdQuery, id)
+	if errors.Is(err, errs.ErrSelectNotMatch) {
+		return errs.ErrNoRowsAffected
+	}
+	if err != nil {
+		return err
+	}
 
-	for _, role := range user.Roles {
-		for _, r := range roles {
-			if role != r {
-				newRoles = append(newRoles, role)
-			}
+	queries := []string{
+		`DELETE FROM cares WHERE user_id = $1;`,
+		`DELETE FROM plants WHERE user_id = $1;`,
+		`DELETE FROM outbox WHERE user_id = $1;`,
+		`DELETE FROM user_jobs WHERE user_id = $1;`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
 		}
 	}
 
-	updateQuery := `UPDATE users SET roles = $1 WHERE external_id = $2;`
+	if _, err := tx.ExecContext(ctx, `DELETE FROM email_deliveries WHERE recipient = $1;`, user.Email); err != nil {
+		return err
+	}
 
-	return RunUpdateExec(ctx, u.db, updateQuery, drivers.StringArray(newRoles), id)
-}
+	if err := pseudonymiseAuditEntries(ctx, tx, user); err != nil {
+		return err
+	}
 
-func (u *userRepository) DeleteUser(ctx context.Context, id string) error {
-	deleteQuery := `UPDATE users SET deleted_at = NOW(), active = false WHERE external_id = $1;`
+	changes, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1;`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := changes.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errs.ErrNoRowsAffected
+	}
+
+	// The erasure is recorded without the user data, which is the point of
+	// erasing it.
+	erased := map[string]any{"erased": true}
+	if err := recordChange(ctx, tx, domain.AuditDelete, domain.AuditEntityUser, strconv.FormatInt(id, 10), id, nil, erased); err != nil {
+		return err
+	}
 
-	return RunUpdateExec(ctx, u.db, deleteQuery, id)
+	event, err := domain.NewUserErasedEvent(user)
+	if err != nil {
+		return err
+	}
+
+	if err := insertOutboxEvents(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
+// UpdatePassword only records that the password changed, never its hash.
 func (u *userRepository) UpdatePassword(ctx context.Context, id, password string) error {
 	updateQuery := `UPDATE users SET password = $1 WHERE external_id = $2;`
 
-	return RunUpdateExec(ctx, u.db, updateQuery, password, id)
+	tx, err := u.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	user, err := getUser(ctx, tx, userLockQuery, id)
+	if err != nil {
+		return err
+	}
+
+	if err := RunUpdateExec(ctx, tx, updateQuery, password, id); err != nil {
+		return err
+	}
+
+	changed := map[string]any{"password": "changed"}
+	if err := recordChange(ctx, tx, domain.AuditUpdate, domain.AuditEntityUser, id, user.Id, nil, changed); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (u *userRepository) UpdatePreferences(ctx context.Context, user *domain.User) error {
+	updateQuery := `UPDATE users SET timezone = $1, locale = $2, quiet_hours_start = $3, quiet_hours_end = $4 WHERE external_id = $5;`
+
+	var start, end sql.NullInt16
+	if user.QuietHours != nil {
+		s, e := user.QuietHours.Minutes()
+		start = sql.NullInt16{Int16: int16(s), Valid: true}
+		end = sql.NullInt16{Int16: int16(e), Valid: true}
+	}
+
+	return u.changeUser(ctx, user.ExternalId, domain.AuditUpdate, "", func(tx *sqlx.Tx, _ *domain.User) error {
+		return RunUpdateExec(ctx, tx, updateQuery, user.Timezone, user.Locale, start, end, user.ExternalId)
+	})
 }
 
 func (u *userRepository) VerifyUser(ctx context.Context, id string) error {
 	updateQuery := `UPDATE users SET verified = true WHERE external_id = $1;`
 
-	return RunUpdateExec(ctx, u.db, updateQuery, id)
+	return u.changeUser(ctx, id, domain.AuditUpdate, domain.EventUserVerified, func(tx *sqlx.Tx, _ *domain.User) error {
+		return RunUpdateExec(ctx, tx, updateQuery, id)
+	})
 }
 
 func (u *userRepository) UpdateActiveUserStatus(ctx context.Context, id string, active bool) error {
 	updateQuery := `UPDATE users SET active = $1 WHERE external_id = $2;`
 
-	return RunUpdateExec(ctx, u.db, updateQuery, active, id)
+	return u.changeUser(ctx, id, domain.AuditUpdate, "", func(tx *sqlx.Tx, _ *domain.User) error {
+		return RunUpdateExec(ctx, tx, updateQuery, active, id)
+	})
 }
 
 func (u *userRepository) CreateUser(ctx context.Context, user *domain.User) (string, error) {
 	insertQuery := `
-		INSERT INTO users (email, username, password, roles)
-		VALUES ($1, $2, $3, $4) RETURNING external_id;
+		INSERT INTO users (email, username, password, roles, timezone, locale)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, external_id;
 	`
 
-	var id string
-	if err := u.db.QueryRowContext(ctx, insertQuery,
-		user.Email, user.Username, user.Password, drivers.StringArray(user.Roles)).Scan(&id); err != nil {
+	tx, err := u.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRowContext(ctx, insertQuery,
+		user.Email, user.Username, user.Password, drivers.StringArray(user.Roles), user.Timezone, user.Locale).Scan(&user.Id, &user.ExternalId); err != nil {
+		return "", err
+	}
+
+	created, err := getUser(ctx, tx, userByIdQuery, user.Id)
+	if err != nil {
+		return "", err
+	}
+
+	if err := recordChange(ctx, tx, domain.AuditCreate, domain.AuditEntityUser, user.ExternalId, user.Id, nil, created); err != nil {
+		return "", err
+	}
+
+	event, err := domain.NewUserEvent(domain.EventUserRegistered, user)
+	if err != nil {
+		return "", err
+	}
+
+	if err := insertOutboxEvents(ctx, tx, event); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return "", err
 	}
 
-	return id, nil
+	return user.ExternalId, nil
 }
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/db/models"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+var _ = (domain.WebhookStorer)((*webhookRepository)(nil))
+
+const webhookColumns = `id, user_id, url, events, secret, active, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, webhook_id, event_id, event, payload, status, attempts, next_attempt_at,
+	response_status, error, redelivery_of, delivered_at, created_at, updated_at`
+
+type webhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) *webhookRepository {
+	return &webhookRepository{db}
+}
+
+func (r *webhookRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) (int64, error) {
+	query := `INSERT INTO webhooks (user_id, url, events, secret, active, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+	err := r.db.QueryRowContext(ctx, query, webhook.UserId, webhook.URL, pq.Array(webhook.Events), webhook.Secret, webhook.Active,
+		webhook.CreatedAt, webhook.UpdatedAt).Scan(&webhook.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	return webhook.Id, nil
+}
+
+func (r *webhookRepository) GetWebhookByID(ctx context.Context, id int64) (*domain.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+
+	var webhooks []models.PGWebhook
+	if err := r.db.SelectContext(ctx, &webhooks, query, id); err != nil {
+		return nil, err
+	}
+
+	if len(webhooks) == 0 {
+		return nil, errs.ErrSelectNotMatch
+	}
+
+	return models.PGWebhookToDomainWebhook(webhooks[0]), nil
+}
+
+func (r *webhookRepository) GetWebhooksByUserID(ctx context.Context, userId int64) ([]*domain.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 ORDER BY id`
+
+	var webhooks []models.PGWebhook
+	if err := r.db.SelectContext(ctx, &webhooks, query, userId); err != nil {
+		return nil, err
+	}
+
+	return models.PGWebhooksToDomainWebhooks(webhooks), nil
+}
+
+func (r *webhookRepository) UpdateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	query := `UPDATE webhooks SET url = $1, events = $2, active = $3, updated_at = $4 WHERE id = $5`
+
+	return RunUpdateExec(ctx, r.db, query, webhook.URL, pq.Array(webhook.Events), webhook.Active, webhook.UpdatedAt, webhook.Id)
+}
+
+func (r *webhookRepository) DeleteWebhook(ctx context.Context, id int64) error {
+	return RunUpdateExec(ctx, r.db, `DELETE FROM webhooks WHERE id = $1`, id)
+}
+
+func (r *webhookRepository) CreateWebhookDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_id, event, payload, status, next_attempt_at, redelivery_of, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	ON CONFLICT (webhook_id, event_id) WHERE redelivery_of IS NULL DO NOTHING
+	RETURNING id`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, d := range deliveries {
+		err := tx.QueryRowContext(ctx, query, d.WebhookId, d.EventId, d.Event, string(d.Payload), d.Status, d.NextAttemptAt,
+			nullInt64(d.RedeliveryOf), d.CreatedAt, d.UpdatedAt).Scan(&d.Id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *webhookRepository) GetWebhookDeliveryByID(ctx context.Context, id int64) (*domain.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	var deliveries []models.PGWebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, id); err != nil {
+		return nil, err
+	}
+
+	if len(deliveries) == 0 {
+		return nil, errs.ErrSelectNotMatch
+	}
+
+	return models.PGWebhookDeliveryToDomainWebhookDelivery(deliveries[0]), nil
+}
+
+func (r *webhookRepository) GetWebhookDeliveries(ctx context.Context, webhookId, beforeId int64, limit int) ([]*domain.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries
+	WHERE webhook_id = $1 AND ($2 = 0 OR id < $2)
+	ORDER BY id DESC LIMIT $3`
+
+	var deliveries []models.PGWebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, webhookId, beforeId, limit); err != nil {
+		return nil, err
+	}
+
+	return models.PGWebhookDeliveriesToDomainWebhookDeliveries(deliveries), nil
+}
+
+func (r *webhookRepository) ClaimWebhookDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.WebhookDelivery, error) {
+	query := `UPDATE webhook_deliveries SET attempts = attempts + 1, next_attempt_at = $2
+	WHERE id IN (
+		SELECT id FROM webhook_deliveries
+		WHERE status = 'pending' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	) RETURNING ` + webhookDeliveryColumns
+
+	var deliveries []models.PGWebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, now, now.Add(lease), limit); err != nil {
+		return nil, err
+	}
+
+	return models.PGWebhookDeliveriesToDomainWebhookDeliveries(deliveries), nil
+}
+
+func (r *webhookRepository) UpdateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `UPDATE webhook_deliveries SET status = $1, next_attempt_at = $2, response_status = $3, error = $4,
+	delivered_at = $5, updated_at = $6
+	WHERE id = $7`
+
+	var deliveredAt sql.NullTime
+	if delivery.DeliveredAt != nil {
+		deliveredAt = sql.NullTime{Time: *delivery.DeliveredAt, Valid: true}
+	}
+
+	return RunUpdateExec(ctx, r.db, query, delivery.Status, delivery.NextAttemptAt, nullInt(delivery.ResponseStatus),
+		nullString(delivery.Error), deliveredAt, delivery.UpdatedAt, delivery.Id)
+}
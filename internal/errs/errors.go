@@ -2,30 +2,76 @@ package errs
 
 import "errors"
 
+// Errors of the storage layer, never shown to clients as they are.
 var (
 	ErrNoRowsAffected      = errors.New("no rows affected")
 	ErrSelectNotMatch      = errors.New("select query did not match any rows")
 	ErtSelectMultipleMatch = errors.New("select query matched multiple rows")
+)
+
+// Messages of the FieldErrors of a merge patch.
+var (
+	ErrFieldNotNullable = New(KindInvalid, "not_nullable", "cannot be null")
+	ErrFieldInvalidType = New(KindInvalid, "invalid_type", "has an invalid type")
+	ErrFieldReadOnly    = New(KindInvalid, "read_only", "is unknown or cannot be changed")
+)
+
+var (
+	ErrVersionConflict = New(KindConflict, "version_conflict", "resource was modified since it was read")
+
+	ErrInvalidBody          = New(KindInvalid, "invalid_body", "invalid body provided")
+	ErrInvalidFields        = New(KindInvalid, "invalid_fields", "invalid fields provided")
+	ErrUnsupportedMediaType = New(KindUnsupportedMediaType, "unsupported_media_type", "unsupported content type")
+	ErrInvalidResponse      = New(KindInternal, "invalid_response", "response does not match the API contract")
+	ErrInvalidPassword      = New(KindInvalid, "invalid_password", "invalid password provided")
+	ErrInvalidQuietHours    = New(KindInvalid, "invalid_quiet_hours", "invalid quiet hours provided")
+
+	ErrInvalidCode        = New(KindUnauthenticated, "invalid_code", "invalid code provided")
+	ErrInvalidCredentials = New(KindUnauthenticated, "invalid_credentials", "invalid password provided")
+	ErrMissingToken       = New(KindUnauthenticated, "missing_token", "no authorization token provided")
+	ErrExpiredToken       = New(KindUnauthenticated, "expired_token", "token has expired")
+	ErrInvalidToken       = New(KindUnauthenticated, "invalid_token", "invalid token format")
+
+	ErrAdminOnly            = New(KindForbidden, "admin_only", "only admins can do this")
+	ErrNotFound             = New(KindNotFound, "not_found", "not found")
+	ErrPreconditionRequired = New(KindPreconditionRequired, "precondition_required", "an If-Match header with the current ETag is required")
+	ErrPreconditionFailed   = New(KindPreconditionFailed, "precondition_failed", "resource was modified, fetch it again and retry")
+	ErrAlreadyVerified      = New(KindConflict, "already_verified", "user is already verified")
+	ErrCodeExpired          = New(KindInvalid, "code_expired", "code expired! New code sent to email")
+
+	ErrUsernameAlreadyExists = New(KindConflict, "username_already_exists", "username already exists")
+	ErrEmailAlreadyExists    = New(KindConflict, "email_already_exists", "email already exists")
+
+	ErrInvalidPlantStatus       = New(KindInvalid, "invalid_plant_status", "invalid plant status provided")
+	ErrInvalidPlantTransition   = New(KindConflict, "invalid_plant_transition", "plant cannot move to the requested status")
+	ErrInvalidPlantStatusReason = New(KindInvalid, "invalid_plant_status_reason", "invalid plant status reason provided")
+	ErrInvalidPlantStatusDate   = New(KindInvalid, "invalid_plant_status_date", "invalid plant status date provided")
+	ErrInvalidGroupBy           = New(KindInvalid, "invalid_group_by", "invalid group by provided")
 
-	ErrInvalidBody     = errors.New("invalid body provided")
-	ErrInvalidUsername = errors.New("invalid username provided")
-	ErrInvalidPassword = errors.New("invalid password provided")
-	ErrInvalidEmail    = errors.New("invalid email provided")
+	ErrInvalidMonth = New(KindInvalid, "invalid_month", "invalid month provided, expected YYYY-MM")
 
-	ErrInvalidCode = errors.New("invalid code provided")
+	ErrInvalidCareDate        = New(KindInvalid, "invalid_care_date", "invalid care date provided")
+	ErrInvalidCareInterval    = New(KindInvalid, "invalid_care_interval", "invalid care interval provided")
+	ErrInvalidCareRecurrence  = New(KindInvalid, "invalid_care_recurrence", "invalid care recurrence rule provided")
+	ErrCareRecurrenceEnded    = New(KindConflict, "care_recurrence_ended", "care recurrence has no upcoming occurrences")
+	ErrInvalidOccurrenceRange = New(KindInvalid, "invalid_occurrence_range", "invalid occurrence range provided")
+	ErrInvalidCareReason      = New(KindInvalid, "invalid_care_reason", "invalid care reason provided")
+	ErrInvalidSnooze          = New(KindInvalid, "invalid_snooze", "invalid snooze provided")
+	ErrInvalidNote            = New(KindInvalid, "invalid_note", "invalid note provided")
+	ErrNoteUnchanged          = New(KindConflict, "note_unchanged", "note is the same as the current revision")
+	ErrNoteConflict           = New(KindConflict, "note_conflict", "note was changed by someone else")
 
-	ErrNotFound        = errors.New("not found")
-	ErrAlreadyVerified = errors.New("user is already verified")
-	ErrCodeExpired     = errors.New("code expired! New code sent to email")
+	ErrInvalidChecklist      = New(KindInvalid, "invalid_checklist", "a checklist can have at most 30 items")
+	ErrInvalidChecklistItem  = New(KindInvalid, "invalid_checklist_item", "invalid checklist item provided")
+	ErrChecklistItemNotFound = New(KindNotFound, "checklist_item_not_found", "checklist item not found")
+	ErrChecklistIncomplete   = New(KindConflict, "checklist_incomplete", "every checklist item must be checked first")
 
-	ErrUsernameAlreadyExists = errors.New("username already exists")
-	ErrEmailAlreadyExists    = errors.New("email already exists")
+	ErrBuiltInCareTemplate = New(KindForbidden, "built_in_care_template", "built-in care templates cannot be changed")
 
-	ErrInvalidPlantName         = errors.New("invalid plant name provided")
-	ErrInvalidPlantLocation     = errors.New("invalid plant location provided")
-	ErrInvalidPlantCareFrequency = errors.New("invalid plant care frequency provided")
+	ErrWebhookInactive     = New(KindConflict, "webhook_inactive", "webhook is not active")
+	ErrWebhookNotRetryable = New(KindConflict, "webhook_not_retryable", "only finished deliveries can be redelivered")
 
-	ErrInvalidCareName  = errors.New("invalid care name provided")
-	ErrInvalidCareNotes = errors.New("invalid care notes provided")
-	ErrInvalidCareDate  = errors.New("invalid care date provided")
+	ErrJobAlreadyRequested = New(KindConflict, "job_already_requested", "a job of this kind is already pending")
+	ErrJobNotCancellable   = New(KindConflict, "job_not_cancellable", "job can no longer be cancelled")
+	ErrExportExpired       = New(KindNotFound, "export_expired", "export link is invalid or has expired")
 )
@@ -0,0 +1,62 @@
+package errs
+
+import (
+	"errors"
+	"strings"
+)
+
+// FieldError describes why the value sent for one field was rejected. Rule
+// names the check that failed, such as min or the code of an *Error.
+// Messages holds the message in other locales, when known, by locale.
+type FieldError struct {
+	Field    string            `json:"field"`
+	Rule     string            `json:"rule,omitempty"`
+	Message  string            `json:"message"`
+	Messages map[string]string `json:"-"`
+}
+
+// FieldErrors collects every rejected field of a request.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, f := range e {
+		messages = append(messages, f.Field+": "+f.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Add records that field was rejected because of err.
+func (e *FieldErrors) Add(field string, err error) {
+	f := FieldError{Field: field, Message: err.Error()}
+
+	var ce *Error
+	if errors.As(err, &ce) {
+		f.Rule = ce.Code
+	}
+
+	*e = append(*e, f)
+}
+
+// Merge records the fields rejected in err, if any. Errors other than
+// FieldErrors are recorded against field.
+func (e *FieldErrors) Merge(field string, err error) {
+	if err == nil {
+		return
+	}
+
+	var fields FieldErrors
+	if errors.As(err, &fields) {
+		*e = append(*e, fields...)
+		return
+	}
+	e.Add(field, err)
+}
+
+// Err returns e, or nil when no field was rejected.
+func (e FieldErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
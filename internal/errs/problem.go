@@ -0,0 +1,109 @@
+package errs
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind classifies errors by what went wrong, which decides their HTTP status.
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindInvalid
+	KindUnauthenticated
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindPreconditionFailed
+	KindUnsupportedMediaType
+	KindPreconditionRequired
+)
+
+var kindStatus = map[Kind]int{
+	KindInternal:             http.StatusInternalServerError,
+	KindInvalid:              http.StatusBadRequest,
+	KindUnauthenticated:      http.StatusUnauthorized,
+	KindForbidden:            http.StatusForbidden,
+	KindNotFound:             http.StatusNotFound,
+	KindConflict:             http.StatusConflict,
+	KindPreconditionFailed:   http.StatusPreconditionFailed,
+	KindUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	KindPreconditionRequired: http.StatusPreconditionRequired,
+}
+
+// Status is the HTTP status of errors of kind k.
+func (k Kind) Status() int {
+	if status, ok := kindStatus[k]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Error is an error meant for clients. Code identifies it for programs and
+// never changes once released; Message is safe to show and may be reworded.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+// New creates an error to be reported to clients.
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrInternal is what clients see of errors that are not an *Error.
+var ErrInternal = New(KindInternal, "internal", "internal server error")
+
+// Status is the HTTP status of err: the status of its kind for an *Error,
+// 400 for FieldErrors and 500 for anything else.
+func Status(err error) int {
+	return ProblemFor(err).Status
+}
+
+// Problem describes an error as RFC 7807 problem details, extended with the
+// stable code of the error, the invalid fields and the correlation id under
+// which it was logged.
+type Problem struct {
+	Type          string      `json:"type"`
+	Title         string      `json:"title"`
+	Status        int         `json:"status"`
+	Detail        string      `json:"detail"`
+	Instance      string      `json:"instance,omitempty"`
+	Code          string      `json:"code"`
+	CorrelationId string      `json:"correlationId,omitempty"`
+	Fields        FieldErrors `json:"fields,omitempty"`
+}
+
+// ProblemFor describes err to clients. Errors that are not an *Error are
+// reported as ErrInternal, so that their message never leaks.
+func ProblemFor(err error) *Problem {
+	var fields FieldErrors
+	if errors.As(err, &fields) {
+		p := newProblem(ErrInvalidFields)
+		p.Fields = fields
+		return p
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return newProblem(e)
+	}
+	return newProblem(ErrInternal)
+}
+
+func newProblem(e *Error) *Problem {
+	status := e.Kind.Status()
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: e.Message,
+		Code:   e.Code,
+	}
+}
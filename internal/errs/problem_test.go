@@ -0,0 +1,70 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblemFor(t *testing.T) {
+	cases := []struct {
+		purpose    string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+		wantFields FieldErrors
+	}{
+		{
+			purpose:    "should describe errors meant for clients",
+			err:        ErrInvalidPlantStatus,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_plant_status",
+			wantDetail: "invalid plant status provided",
+		},
+		{
+			purpose:    "should find wrapped errors",
+			err:        fmt.Errorf("saving plant: %w", ErrVersionConflict),
+			wantStatus: http.StatusConflict,
+			wantCode:   "version_conflict",
+			wantDetail: "resource was modified since it was read",
+		},
+		{
+			purpose:    "should list invalid fields",
+			err:        FieldErrors{{Field: "name", Message: "cannot be null"}},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_fields",
+			wantDetail: "invalid fields provided",
+			wantFields: FieldErrors{{Field: "name", Message: "cannot be null"}},
+		},
+		{
+			purpose:    "should hide the message of internal errors",
+			err:        errors.New(`pq: relation "plants" does not exist`),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "internal",
+			wantDetail: "internal server error",
+		},
+		{
+			purpose:    "should hide storage errors",
+			err:        ErrSelectNotMatch,
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "internal",
+			wantDetail: "internal server error",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			p := ProblemFor(tt.err)
+			assert.Equal(t, "about:blank", p.Type)
+			assert.Equal(t, http.StatusText(tt.wantStatus), p.Title)
+			assert.Equal(t, tt.wantStatus, p.Status)
+			assert.Equal(t, tt.wantCode, p.Code)
+			assert.Equal(t, tt.wantDetail, p.Detail)
+			assert.Equal(t, tt.wantFields, p.Fields)
+		})
+	}
+}
@@ -0,0 +1,295 @@
+// Package events publishes the domain events saved to the outbox to a Redis
+// stream and runs the in-process subscribers that consume them.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	l "github.com/mathehluiz/plant-care-tracker/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// Stream is the Redis stream the events are published to.
+	Stream = "events"
+	// DeadLetterStream receives the events a subscriber failed to handle
+	// MaxDeliveries times, along with the name of its group.
+	DeadLetterStream = "events:dead"
+
+	// MaxDeliveries is how many times an event is handed to a subscriber
+	// before it is dead-lettered.
+	MaxDeliveries = 5
+
+	// streamMaxLen caps the stream, approximately. Subscribers that fall
+	// further behind than that miss the oldest events.
+	streamMaxLen = 100000
+)
+
+// Handler handles one event. Returning an error leaves the event pending, so
+// it is handed again later, possibly to another replica. As delivery is at
+// least once, handlers must cope with seeing an event twice.
+type Handler func(ctx context.Context, event *domain.Event) error
+
+type subscription struct {
+	group   string
+	handler Handler
+	types   map[string]bool
+}
+
+// wants reports whether the subscription handles events of eventType.
+// Subscriptions without types handle every event.
+func (s *subscription) wants(eventType string) bool {
+	return len(s.types) == 0 || s.types[eventType]
+}
+
+// Bus publishes events to Stream and hands them to the subscribers. Every
+// subscriber reads the stream through its own consumer group, so each of them
+// sees every event, and the replicas of a subscriber share its events.
+type Bus struct {
+	client        *redis.Client
+	consumer      string
+	subscriptions []*subscription
+	block         time.Duration
+	claimIdle     time.Duration
+	claimInterval time.Duration
+}
+
+func NewBus(client *redis.Client) *Bus {
+	return &Bus{
+		client:        client,
+		consumer:      consumerName(),
+		block:         5 * time.Second,
+		claimIdle:     30 * time.Second,
+		claimInterval: 15 * time.Second,
+	}
+}
+
+// consumerName tells the replicas apart within a consumer group.
+func consumerName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// Subscribe registers handler as the consumer group group, for the events of
+// the given types or, without types, every event. It must be called before
+// Start.
+func (b *Bus) Subscribe(group string, handler Handler, types ...string) {
+	sub := &subscription{group: group, handler: handler, types: make(map[string]bool, len(types))}
+	for _, t := range types {
+		sub.types[t] = true
+	}
+	b.subscriptions = append(b.subscriptions, sub)
+}
+
+// Publish appends the events to the stream in one round trip.
+func (b *Bus) Publish(ctx context.Context, events []*domain.Event) error {
+	pipe := b.client.Pipeline()
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: Stream,
+			MaxLen: streamMaxLen,
+			Approx: true,
+			Values: map[string]any{"event": data},
+		})
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Start creates the consumer groups that do not exist yet and consumes the
+// stream until ctx is cancelled. It is meant to be run in its own goroutine.
+func (b *Bus) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, sub := range b.subscriptions {
+		if err := b.createGroup(ctx, sub.group); err != nil {
+			l.Logger.Error("cannot create consumer group", zap.Error(err), zap.String("group", sub.group))
+			continue
+		}
+
+		wg.Add(1)
+		go func(sub *subscription) {
+			defer wg.Done()
+			b.consume(ctx, sub)
+		}(sub)
+	}
+	wg.Wait()
+}
+
+// createGroup creates group at the end of the stream, so a new subscriber
+// only sees the events published from then on.
+func (b *Bus) createGroup(ctx context.Context, group string) error {
+	err := b.client.XGroupCreateMkStream(ctx, Stream, group, "$").Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+func (b *Bus) consume(ctx context.Context, sub *subscription) {
+	lastClaim := time.Now()
+
+	for ctx.Err() == nil {
+		if err := b.read(ctx, sub); err != nil && ctx.Err() == nil {
+			l.Logger.Error("cannot read events", zap.Error(err), zap.String("group", sub.group))
+			time.Sleep(time.Second)
+		}
+
+		if time.Since(lastClaim) >= b.claimInterval {
+			if err := b.reclaim(ctx, sub); err != nil && ctx.Err() == nil {
+				l.Logger.Error("cannot reclaim events", zap.Error(err), zap.String("group", sub.group))
+			}
+			lastClaim = time.Now()
+		}
+	}
+}
+
+// read handles the events published since the last read of the group,
+// waiting up to b.block for one to arrive.
+func (b *Bus) read(ctx context.Context, sub *subscription) error {
+	streams, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    sub.group,
+		Consumer: b.consumer,
+		Streams:  []string{Stream, ">"},
+		Count:    50,
+		Block:    b.block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			b.handle(ctx, sub, message)
+		}
+	}
+	return nil
+}
+
+// reclaim takes over the events left pending for longer than b.claimIdle,
+// because their handler failed or their consumer died, and hands them to the
+// subscriber again. Events already handed MaxDeliveries times are moved to
+// DeadLetterStream instead.
+func (b *Bus) reclaim(ctx context.Context, sub *subscription) error {
+	pending, err := b.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: Stream,
+		Group:  sub.group,
+		Idle:   b.claimIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  100,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	deliveries := make(map[string]int64, len(pending))
+	ids := make([]string, 0, len(pending))
+	for _, p := range pending {
+		deliveries[p.ID] = p.RetryCount
+		ids = append(ids, p.ID)
+	}
+
+	messages, err := b.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   Stream,
+		Group:    sub.group,
+		Consumer: b.consumer,
+		MinIdle:  b.claimIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		if deliveries[message.ID] >= MaxDeliveries {
+			b.deadLetter(ctx, sub, message)
+			continue
+		}
+		b.handle(ctx, sub, message)
+	}
+	return nil
+}
+
+// handle passes the event to the subscriber and acknowledges it if it was
+// handled, or is not of a type the subscriber wants.
+func (b *Bus) handle(ctx context.Context, sub *subscription, message redis.XMessage) {
+	event, err := decode(message)
+	if err != nil {
+		l.Logger.Error("cannot decode event", zap.Error(err), zap.String("message", message.ID))
+		b.deadLetter(ctx, sub, message)
+		return
+	}
+
+	if sub.wants(event.Type) {
+		if err := sub.handler(ctx, event); err != nil {
+			l.Logger.Error("cannot handle event", zap.Error(err), zap.String("group", sub.group),
+				zap.String("event", event.Type), zap.String("id", event.Id))
+			return
+		}
+	}
+
+	b.ack(ctx, sub, message.ID)
+}
+
+func (b *Bus) deadLetter(ctx context.Context, sub *subscription, message redis.XMessage) {
+	values := map[string]any{"group": sub.group, "message": message.ID}
+	for k, v := range message.Values {
+		values[k] = v
+	}
+
+	err := b.client.XAdd(ctx, &redis.XAddArgs{Stream: DeadLetterStream, MaxLen: streamMaxLen, Approx: true, Values: values}).Err()
+	if err != nil {
+		l.Logger.Error("cannot dead-letter event", zap.Error(err), zap.String("group", sub.group), zap.String("message", message.ID))
+		return
+	}
+
+	l.Logger.Warn("dead-lettered event", zap.String("group", sub.group), zap.String("message", message.ID))
+	b.ack(ctx, sub, message.ID)
+}
+
+func (b *Bus) ack(ctx context.Context, sub *subscription, id string) {
+	if err := b.client.XAck(ctx, Stream, sub.group, id).Err(); err != nil {
+		l.Logger.Error("cannot acknowledge event", zap.Error(err), zap.String("group", sub.group), zap.String("message", id))
+	}
+}
+
+func decode(message redis.XMessage) (*domain.Event, error) {
+	data, ok := message.Values["event"].(string)
+	if !ok {
+		return nil, errors.New("message has no event")
+	}
+
+	var event domain.Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
@@ -0,0 +1,142 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBus(t *testing.T) *Bus {
+	mock, err := cache.StartMock()
+	assert.NoError(t, err)
+	t.Cleanup(func() { mock.Close() })
+
+	bus := NewBus(mock.Client())
+	bus.block = 10 * time.Millisecond
+	bus.claimIdle = 0
+	return bus
+}
+
+func newTestEvent(t *testing.T, eventType string) *domain.Event {
+	event, err := domain.NewEvent(eventType, "1", 1, map[string]any{"ok": true})
+	assert.NoError(t, err)
+	return event
+}
+
+func TestBusDelivery(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		purpose     string
+		types       []string
+		failures    int
+		wantHandled []string
+		wantPending int64
+		wantDead    int64
+	}{
+		{
+			"should hand every event to a subscriber without types",
+			nil,
+			0,
+			[]string{domain.EventPlantCreated, domain.EventUserRegistered},
+			0,
+			0,
+		},
+		{
+			"should only hand the events of the subscribed types",
+			[]string{domain.EventUserRegistered},
+			0,
+			[]string{domain.EventUserRegistered},
+			0,
+			0,
+		},
+		{
+			"should hand a failed event again when it is reclaimed",
+			[]string{domain.EventUserRegistered},
+			1,
+			[]string{domain.EventUserRegistered},
+			0,
+			0,
+		},
+		{
+			"should dead-letter an event that keeps failing",
+			[]string{domain.EventUserRegistered},
+			MaxDeliveries,
+			nil,
+			0,
+			1,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			bus := newTestBus(t)
+
+			var handled []string
+			failures := tt.failures
+			bus.Subscribe("test", func(ctx context.Context, event *domain.Event) error {
+				if failures > 0 {
+					failures--
+					return errors.New("boom")
+				}
+				handled = append(handled, event.Type)
+				return nil
+			}, tt.types...)
+			sub := bus.subscriptions[0]
+
+			assert.NoError(t, bus.createGroup(ctx, sub.group))
+			assert.NoError(t, bus.Publish(ctx, []*domain.Event{
+				newTestEvent(t, domain.EventPlantCreated),
+				newTestEvent(t, domain.EventUserRegistered),
+			}))
+
+			assert.NoError(t, bus.read(ctx, sub))
+			for i := 0; i < MaxDeliveries; i++ {
+				assert.NoError(t, bus.reclaim(ctx, sub))
+			}
+
+			assert.Equal(t, tt.wantHandled, handled)
+
+			pending, err := bus.client.XPending(ctx, Stream, sub.group).Result()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPending, pending.Count)
+
+			dead, err := bus.client.XLen(ctx, DeadLetterStream).Result()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantDead, dead)
+		})
+	}
+}
+
+func TestBusGroups(t *testing.T) {
+	ctx := context.Background()
+	bus := newTestBus(t)
+
+	counts := map[string]int{}
+	for _, group := range []string{"a", "b"} {
+		group := group
+		bus.Subscribe(group, func(ctx context.Context, event *domain.Event) error {
+			counts[group]++
+			return nil
+		})
+	}
+
+	for _, sub := range bus.subscriptions {
+		assert.NoError(t, bus.createGroup(ctx, sub.group))
+		assert.NoError(t, bus.createGroup(ctx, sub.group), "creating a group again should be a no-op")
+	}
+
+	assert.NoError(t, bus.Publish(ctx, []*domain.Event{newTestEvent(t, domain.EventCareCreated)}))
+
+	for _, sub := range bus.subscriptions {
+		assert.NoError(t, bus.read(ctx, sub))
+		assert.NoError(t, bus.read(ctx, sub))
+	}
+
+	assert.Equal(t, map[string]int{"a": 1, "b": 1}, counts)
+}
@@ -0,0 +1,117 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	l "github.com/mathehluiz/plant-care-tracker/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// dueLookback is how far back the first scan for due cares after a start
+// looks, so that cares that came due while no replica was running still get
+// their care.due event.
+const dueLookback = time.Hour
+
+// DueScanner raises care.due when the next occurrence of a care comes due,
+// or when the quiet hours of its owner end if it came due during them, so
+// that neither the webhooks nor the live clients are notified in the
+// meantime. The event id is derived from the care and its due time, so
+// scanning the same window again, here or on another replica, raises nothing
+// new.
+type DueScanner struct {
+	cStorer  domain.CareStorer
+	uStorer  domain.UserStorer
+	oStorer  domain.OutboxStorer
+	interval time.Duration
+	from     time.Time
+}
+
+func NewDueScanner(cStorer domain.CareStorer, uStorer domain.UserStorer, oStorer domain.OutboxStorer) *DueScanner {
+	return &DueScanner{
+		cStorer:  cStorer,
+		uStorer:  uStorer,
+		oStorer:  oStorer,
+		interval: 10 * time.Second,
+	}
+}
+
+// Start scans for due cares until ctx is cancelled. It is meant to be run in
+// its own goroutine.
+func (s *DueScanner) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce raises care.due for the cares that may be delivered since the last
+// scan. As quiet hours defer a care by less than domain.MaxNotificationDelay,
+// those are the cares that came due since then, or up to that much earlier.
+func (s *DueScanner) RunOnce(ctx context.Context) {
+	now := time.Now().UTC()
+
+	from := s.from
+	if from.IsZero() {
+		from = now.Add(-dueLookback)
+	}
+
+	cares, err := s.cStorer.GetCaresDueBetween(ctx, from.Add(-domain.MaxNotificationDelay), now)
+	if err != nil {
+		l.Logger.Error("cannot list due cares", zap.Error(err))
+		return
+	}
+
+	owners := make(map[int64]*domain.User)
+
+	var due []*domain.Event
+	for _, care := range cares {
+		if care.Finished() {
+			continue
+		}
+
+		owner, ok := owners[care.UserId]
+		if !ok {
+			owner, err = s.uStorer.GetUserByID(ctx, care.UserId)
+			if err != nil && !errors.Is(err, errs.ErrSelectNotMatch) {
+				l.Logger.Error("cannot load care owner", zap.Error(err), zap.Int64("care", care.Id))
+				return
+			}
+			owners[care.UserId] = owner
+		}
+		if owner == nil {
+			continue
+		}
+
+		if deliverAt := owner.NextDeliveryTime(care.NextCare); !deliverAt.After(from) || deliverAt.After(now) {
+			continue
+		}
+
+		event, err := domain.NewCareDueEvent(care)
+		if err != nil {
+			l.Logger.Error("cannot raise care.due", zap.Error(err), zap.Int64("care", care.Id))
+			continue
+		}
+		due = append(due, event)
+	}
+
+	if len(due) > 0 {
+		if err := s.oStorer.CreateOutboxEvents(ctx, due...); err != nil {
+			l.Logger.Error("cannot save care.due events", zap.Error(err))
+			return
+		}
+	}
+
+	s.from = now
+}
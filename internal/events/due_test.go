@@ -0,0 +1,114 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+type dueCareStorer struct {
+	domain.CareStorer
+	cares []*domain.Care
+}
+
+func (s *dueCareStorer) GetCaresDueBetween(ctx context.Context, from, to time.Time) ([]*domain.Care, error) {
+	var due []*domain.Care
+	for _, care := range s.cares {
+		if care.NextCare.After(from) && !care.NextCare.After(to) {
+			due = append(due, care)
+		}
+	}
+	return due, nil
+}
+
+type dueUserStorer struct {
+	domain.UserStorer
+	users map[int64]*domain.User
+}
+
+func (s *dueUserStorer) GetUserByID(ctx context.Context, id int64) (*domain.User, error) {
+	if user, ok := s.users[id]; ok {
+		return user, nil
+	}
+	return nil, errs.ErrSelectNotMatch
+}
+
+type dueOutboxStorer struct {
+	domain.OutboxStorer
+	events []*domain.Event
+}
+
+func (s *dueOutboxStorer) CreateOutboxEvents(ctx context.Context, events ...*domain.Event) error {
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func TestDueScannerQuietHours(t *testing.T) {
+	now := time.Now().UTC()
+	clock := func(t time.Time) string { return t.Format("15:04") }
+
+	// The quiet hours of user 2 started an hour ago and end next minute,
+	// while those of user 3 ended at the start of this minute.
+	quiet := &domain.QuietHours{Start: clock(now.Add(-time.Hour)), End: clock(now.Add(time.Minute))}
+	ended := &domain.QuietHours{Start: clock(now.Add(-2 * time.Hour)), End: clock(now)}
+
+	users := &dueUserStorer{users: map[int64]*domain.User{
+		1: {Id: 1, Timezone: "UTC"},
+		2: {Id: 2, Timezone: "UTC", QuietHours: quiet},
+		3: {Id: 3, Timezone: "UTC", QuietHours: ended},
+	}}
+
+	cases := []struct {
+		purpose string
+		care    *domain.Care
+		raised  bool
+	}{
+		{
+			"should raise a care that just came due",
+			&domain.Care{Id: 1, UserId: 1, NextCare: now.Add(-5 * time.Second)},
+			true,
+		},
+		{
+			"should not raise a care again on the next scan",
+			&domain.Care{Id: 2, UserId: 1, NextCare: now.Add(-3 * time.Minute)},
+			false,
+		},
+		{
+			"should hold a care back during the quiet hours of its owner",
+			&domain.Care{Id: 3, UserId: 2, NextCare: now.Add(-5 * time.Second)},
+			false,
+		},
+		{
+			"should raise a care held back by quiet hours once they end",
+			&domain.Care{Id: 4, UserId: 3, NextCare: now.Add(-time.Hour)},
+			true,
+		},
+		{
+			"should skip the cares of users that no longer exist",
+			&domain.Care{Id: 5, UserId: 4, NextCare: now.Add(-5 * time.Second)},
+			false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			outbox := &dueOutboxStorer{}
+			scanner := NewDueScanner(&dueCareStorer{cares: []*domain.Care{tt.care}}, users, outbox)
+			// The previous scan ran before the quiet hours of user 3 ended.
+			scanner.from = now.Add(-2 * time.Minute)
+
+			scanner.RunOnce(context.Background())
+
+			if !tt.raised {
+				assert.Empty(t, outbox.events)
+				return
+			}
+			assert.Len(t, outbox.events, 1)
+			assert.Equal(t, domain.EventCareDue, outbox.events[0].Type)
+		})
+	}
+}
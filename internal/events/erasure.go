@@ -0,0 +1,66 @@
+package events
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/live"
+)
+
+// erasureScanCount is how many stream entries are read at a time when
+// looking for the events of an erased user.
+const erasureScanCount = 500
+
+// ErasureHandler removes the copies Redis keeps of the data of an erased
+// user: its live replay buffer, and its events in Stream and
+// DeadLetterStream. The user.erased events themselves are kept, as they only
+// hold ids and the other subscribers may still have to handle them.
+func ErasureHandler(client *redis.Client) Handler {
+	return func(ctx context.Context, event *domain.Event) error {
+		if err := client.Del(ctx, live.BufferKey(event.UserId)).Err(); err != nil {
+			return err
+		}
+
+		for _, stream := range []string{Stream, DeadLetterStream} {
+			if err := deleteUserEvents(ctx, client, stream, event.UserId); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// deleteUserEvents deletes the events of the user from stream, scanning it
+// from the oldest entry.
+func deleteUserEvents(ctx context.Context, client *redis.Client, stream string, userId int64) error {
+	start := "-"
+	for {
+		messages, err := client.XRangeN(ctx, stream, start, "+", erasureScanCount).Result()
+		if err != nil {
+			return err
+		}
+
+		var ids []string
+		for _, message := range messages {
+			event, err := decode(message)
+			if err != nil {
+				continue
+			}
+			if event.UserId == userId && event.Type != domain.EventUserErased {
+				ids = append(ids, message.ID)
+			}
+		}
+
+		if len(ids) > 0 {
+			if err := client.XDel(ctx, stream, ids...).Err(); err != nil {
+				return err
+			}
+		}
+
+		if len(messages) < erasureScanCount {
+			return nil
+		}
+		start = "(" + messages[len(messages)-1].ID
+	}
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/live"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErasureHandler(t *testing.T) {
+	ctx := context.Background()
+	bus := newTestBus(t)
+	client := bus.client
+
+	event := func(eventType string, userId int64) *domain.Event {
+		e, err := domain.NewEvent(eventType, "1", userId, map[string]any{"email": "fern@example.com"})
+		assert.NoError(t, err)
+		return e
+	}
+
+	var published []*domain.Event
+	for i := 0; i < erasureScanCount; i++ {
+		published = append(published, event(domain.EventPlantCreated, 2))
+	}
+	published = append(published,
+		event(domain.EventUserRegistered, 1),
+		event(domain.EventPlantCreated, 1),
+		event(domain.EventUserErased, 1),
+	)
+	assert.NoError(t, bus.Publish(ctx, published))
+
+	dead := event(domain.EventCareCreated, 1)
+	data, err := json.Marshal(dead)
+	assert.NoError(t, err)
+	assert.NoError(t, client.XAdd(ctx, &redis.XAddArgs{Stream: DeadLetterStream, Values: map[string]any{"event": string(data)}}).Err())
+
+	assert.NoError(t, client.XAdd(ctx, &redis.XAddArgs{Stream: live.BufferKey(1), Values: map[string]any{"type": domain.EventPlantCreated}}).Err())
+	assert.NoError(t, client.XAdd(ctx, &redis.XAddArgs{Stream: live.BufferKey(2), Values: map[string]any{"type": domain.EventPlantCreated}}).Err())
+
+	assert.NoError(t, ErasureHandler(client)(ctx, event(domain.EventUserErased, 1)))
+
+	messages, err := client.XRange(ctx, Stream, "-", "+").Result()
+	assert.NoError(t, err)
+	assert.Len(t, messages, erasureScanCount+1, "should keep the events of other users and user.erased")
+	last, err := decode(messages[len(messages)-1])
+	assert.NoError(t, err)
+	assert.Equal(t, domain.EventUserErased, last.Type)
+
+	deadLettered, err := client.XLen(ctx, DeadLetterStream).Result()
+	assert.NoError(t, err)
+	assert.Zero(t, deadLettered, "should delete the dead-lettered events of the user")
+
+	buffers, err := client.Exists(ctx, live.BufferKey(1), live.BufferKey(2)).Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), buffers, "should only delete the replay buffer of the user")
+}
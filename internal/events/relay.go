@@ -0,0 +1,80 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	l "github.com/mathehluiz/plant-care-tracker/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	relayBatchSize = 100
+
+	// outboxRetention is how long published events are kept in the outbox,
+	// to help debugging, before they are pruned.
+	outboxRetention = 7 * 24 * time.Hour
+	pruneInterval   = time.Hour
+)
+
+// Relay publishes the events saved to the outbox to the bus. An event is only
+// marked as published once the bus accepted it, so a crash in between
+// publishes it again.
+type Relay struct {
+	storer    domain.OutboxStorer
+	bus       *Bus
+	interval  time.Duration
+	lastPrune time.Time
+}
+
+func NewRelay(storer domain.OutboxStorer, bus *Bus) *Relay {
+	return &Relay{
+		storer:   storer,
+		bus:      bus,
+		interval: time.Second,
+	}
+}
+
+// Start polls the outbox until ctx is cancelled. It is meant to be run in its
+// own goroutine.
+func (r *Relay) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce publishes the outbox until it is empty, and prunes the published
+// events once every pruneInterval.
+func (r *Relay) RunOnce(ctx context.Context) {
+	for {
+		n, err := r.storer.PublishOutboxEvents(ctx, relayBatchSize, r.bus.Publish)
+		if err != nil {
+			l.Logger.Error("cannot publish outbox events", zap.Error(err))
+			break
+		}
+		if n < relayBatchSize {
+			break
+		}
+	}
+
+	now := time.Now().UTC()
+	if now.Sub(r.lastPrune) < pruneInterval {
+		return
+	}
+
+	if err := r.storer.DeletePublishedOutboxEvents(ctx, now.Add(-outboxRetention)); err != nil {
+		l.Logger.Error("cannot prune outbox events", zap.Error(err))
+		return
+	}
+	r.lastPrune = now
+}
@@ -0,0 +1,67 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/cache"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mailer"
+)
+
+// statsRetention is how long the daily event counters are kept.
+const statsRetention = 90 * 24 * time.Hour
+
+var errNoVerificationCode = errors.New("no verification code for the user")
+
+// MailerHandler sends the verification email with the code that the
+// handlers stored in the cache under the external id of the user. The code
+// is set right after the user is saved, so a missing code is retried rather
+// than skipped, and expires domain.VerificationCodeTTL after the event.
+func MailerHandler(cacher cache.ConnectionStorer, m mailer.Mailer) Handler {
+	return func(ctx context.Context, event *domain.Event) error {
+		var payload domain.UserEventPayload
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+
+		code, err := cacher.Get(ctx, payload.User.ExternalId)
+		if errors.Is(err, cache.ErrNil) {
+			return errNoVerificationCode
+		}
+		if err != nil {
+			return err
+		}
+
+		return mailer.Send(ctx, m, payload.User.Email, payload.User.Locale, mailer.Verification{
+			Username:  payload.User.Username,
+			Code:      code,
+			ExpiresAt: event.OccurredAt.Add(domain.VerificationCodeTTL),
+		})
+	}
+}
+
+// CacheInvalidationHandler drops the verification code of the users that no
+// longer need it. The code is stored under the external id of the user,
+// which is the aggregate id of the user events.
+func CacheInvalidationHandler(cacher cache.ConnectionStorer) Handler {
+	return func(ctx context.Context, event *domain.Event) error {
+		return cacher.Delete(ctx, event.AggregateId)
+	}
+}
+
+// StatsHandler counts the events of each type per day in the hash
+// stats:events:YYYY-MM-DD.
+func StatsHandler(client *redis.Client) Handler {
+	return func(ctx context.Context, event *domain.Event) error {
+		key := "stats:events:" + event.OccurredAt.UTC().Format("2006-01-02")
+
+		pipe := client.TxPipeline()
+		pipe.HIncrBy(ctx, key, event.Type, 1)
+		pipe.Expire(ctx, key, statsRetention)
+		_, err := pipe.Exec(ctx)
+		return err
+	}
+}
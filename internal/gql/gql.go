@@ -0,0 +1,67 @@
+// Package gql serves users, plants and cares, and their relations, over
+// GraphQL.
+package gql
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+)
+
+// Request is a GraphQL request as sent over HTTP.
+type Request struct {
+	Query         string         `json:"query" form:"query"`
+	OperationName string         `json:"operationName" form:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type Service struct {
+	schema  graphql.Schema
+	uStorer domain.UserStorer
+	pStorer domain.PlantStorer
+	cStorer domain.CareStorer
+}
+
+func NewService(uStorer domain.UserStorer, pStorer domain.PlantStorer, cStorer domain.CareStorer, policy *authz.Policy) (*Service, error) {
+	schema, err := newSchema(&resolver{uStorer: uStorer, pStorer: pStorer, cStorer: cStorer, policy: policy})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{schema: schema, uStorer: uStorer, pStorer: pStorer, cStorer: cStorer}, nil
+}
+
+// Do runs the request on behalf of subject. Queries over the depth or
+// complexity limits are rejected before anything is loaded.
+func (s *Service) Do(ctx context.Context, subject authz.Subject, req Request) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	validation := graphql.ValidateDocument(&s.schema, doc, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	if err := checkLimits(&s.schema, doc); err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	ctx = withRequest(ctx, &request{subject: subject, loaders: newLoaders(s.uStorer, s.pStorer, s.cStorer)})
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+}
@@ -0,0 +1,244 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+type userStorer struct {
+	domain.UserStorer
+	users map[int64]*domain.User
+}
+
+func (s *userStorer) GetUserByID(ctx context.Context, id int64) (*domain.User, error) {
+	if user, ok := s.users[id]; ok {
+		return user, nil
+	}
+	return nil, errs.ErrSelectNotMatch
+}
+
+func (s *userStorer) GetUserByExternalId(ctx context.Context, externalId string) (*domain.User, error) {
+	for _, user := range s.users {
+		if user.ExternalId == externalId {
+			return user, nil
+		}
+	}
+	return nil, errs.ErrSelectNotMatch
+}
+
+type plantStorer struct {
+	domain.PlantStorer
+	plants []*domain.Plant
+	calls  int
+}
+
+func (s *plantStorer) GetPlantsByUserID(ctx context.Context, userId int64, statuses ...string) ([]*domain.Plant, error) {
+	var plants []*domain.Plant
+	for _, plant := range s.plants {
+		if plant.UserId == userId {
+			plants = append(plants, plant)
+		}
+	}
+	return plants, nil
+}
+
+func (s *plantStorer) GetPlantsByIDs(ctx context.Context, ids []int64) ([]*domain.Plant, error) {
+	s.calls++
+
+	var plants []*domain.Plant
+	for _, plant := range s.plants {
+		for _, id := range ids {
+			if plant.Id == id {
+				plants = append(plants, plant)
+			}
+		}
+	}
+	return plants, nil
+}
+
+type careStorer struct {
+	domain.CareStorer
+	cares []*domain.Care
+	calls int
+}
+
+func (s *careStorer) GetCaresByUserID(ctx context.Context, userId int64) ([]*domain.Care, error) {
+	var cares []*domain.Care
+	for _, care := range s.cares {
+		if care.UserId == userId {
+			cares = append(cares, care)
+		}
+	}
+	return cares, nil
+}
+
+func (s *careStorer) GetCareByID(ctx context.Context, id int64) (*domain.Care, error) {
+	for _, care := range s.cares {
+		if care.Id == id {
+			return care, nil
+		}
+	}
+	return nil, errs.ErrSelectNotMatch
+}
+
+func (s *careStorer) GetCaresByPlantIDs(ctx context.Context, plantIds []int64) ([]*domain.Care, error) {
+	s.calls++
+
+	var cares []*domain.Care
+	for _, care := range s.cares {
+		for _, id := range plantIds {
+			if care.PlantId == id {
+				cares = append(cares, care)
+			}
+		}
+	}
+	return cares, nil
+}
+
+type auditLog struct {
+	domain.AuditStorer
+}
+
+func newTestService(t *testing.T) (*Service, *plantStorer, *careStorer) {
+	now := time.Now()
+
+	uStorer := &userStorer{users: map[int64]*domain.User{
+		1: {Id: 1, ExternalId: "u-1", Username: "ana", Roles: []string{"user"}},
+		2: {Id: 2, ExternalId: "u-2", Username: "bia", Roles: []string{"user"}},
+	}}
+	pStorer := &plantStorer{plants: []*domain.Plant{
+		{Id: 1, Name: "Fern", UserId: 1, Status: domain.PlantStatusActive, AcquisitionDate: now, CreatedAt: now, UpdatedAt: now},
+		{Id: 2, Name: "Cactus", UserId: 1, Status: domain.PlantStatusActive, AcquisitionDate: now, CreatedAt: now, UpdatedAt: now},
+		{Id: 3, Name: "Orchid", UserId: 2, Status: domain.PlantStatusActive, AcquisitionDate: now, CreatedAt: now, UpdatedAt: now},
+	}}
+	cStorer := &careStorer{cares: []*domain.Care{
+		{Id: 1, PlantId: 1, UserId: 1, Name: "Water", LastCare: now, NextCare: now, CreatedAt: now, UpdatedAt: now},
+		{Id: 2, PlantId: 2, UserId: 1, Name: "Water", LastCare: now, NextCare: now, CreatedAt: now, UpdatedAt: now},
+		{Id: 3, PlantId: 3, UserId: 2, Name: "Mist", LastCare: now, NextCare: now, CreatedAt: now, UpdatedAt: now},
+	}}
+
+	service, err := NewService(uStorer, pStorer, cStorer, authz.NewPolicy(&auditLog{}))
+	assert.NoError(t, err)
+
+	return service, pStorer, cStorer
+}
+
+func TestServiceBatchesLoads(t *testing.T) {
+	service, pStorer, cStorer := newTestService(t)
+
+	result := service.Do(context.Background(), authz.Subject{UserId: 1}, Request{
+		Query: `{ me { username plants { name cares { name plant { name } } } } }`,
+	})
+	assert.Empty(t, result.Errors)
+
+	assert.Equal(t, 1, cStorer.calls, "the cares of every plant should be loaded at once")
+	assert.Equal(t, 1, pStorer.calls, "the plant of every care should be loaded at once")
+	assert.JSONEq(t, `{"me": {"username": "ana", "plants": [
+		{"name": "Fern", "cares": [{"name": "Water", "plant": {"name": "Fern"}}]},
+		{"name": "Cactus", "cares": [{"name": "Water", "plant": {"name": "Cactus"}}]}
+	]}}`, mustJSON(t, result.Data))
+}
+
+func TestServiceHidesOtherUsersData(t *testing.T) {
+	service, _, _ := newTestService(t)
+
+	cases := []struct {
+		purpose string
+		query   string
+		want    string
+	}{
+		{
+			"should resolve a plant of another user to null",
+			`{ plant(id: "3") { name } }`,
+			`{"plant": null}`,
+		},
+		{
+			"should resolve a care of another user to null",
+			`{ care(id: "3") { name } }`,
+			`{"care": null}`,
+		},
+		{
+			"should resolve another user to null",
+			`{ user(id: "u-2") { username } }`,
+			`{"user": null}`,
+		},
+		{
+			"should only list the cares of the user",
+			`{ cares { id } }`,
+			`{"cares": [{"id": "1"}, {"id": "2"}]}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			result := service.Do(context.Background(), authz.Subject{UserId: 1}, Request{Query: tt.query})
+			assert.Empty(t, result.Errors)
+			assert.JSONEq(t, tt.want, mustJSON(t, result.Data))
+		})
+	}
+}
+
+func TestServiceLimits(t *testing.T) {
+	service, _, _ := newTestService(t)
+
+	cases := []struct {
+		purpose string
+		query   string
+		wantErr string
+	}{
+		{
+			"should accept a query within the limits",
+			`{ plants { name cares { name } } }`,
+			"",
+		},
+		{
+			"should reject a query nested too deeply",
+			`{ me { plants { cares { plant { owner { plants { cares { plant { name } } } } } } } } }`,
+			"levels deep",
+		},
+		{
+			"should reject a query nested too deeply through fragments",
+			`{ me { ...p } }
+			fragment p on User { plants { cares { plant { owner { plants { cares { plant { name } } } } } } } }`,
+			"levels deep",
+		},
+		{
+			"should reject a query that is too complex",
+			`{ plants { cares { plant { cares { name notes } } } } }`,
+			"complexity",
+		},
+		{
+			"should not count introspection",
+			`{ __schema { types { name fields { name type { name ofType { name ofType { name ofType { name } } } } } } } }`,
+			"",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			result := service.Do(context.Background(), authz.Subject{UserId: 1}, Request{Query: tt.query})
+			if tt.wantErr == "" {
+				assert.Empty(t, result.Errors)
+				return
+			}
+
+			assert.Len(t, result.Errors, 1)
+			assert.True(t, strings.Contains(result.Errors[0].Message, tt.wantErr), result.Errors[0].Message)
+			assert.Nil(t, result.Data)
+		})
+	}
+}
+
+func mustJSON(t *testing.T, v any) string {
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return string(b)
+}
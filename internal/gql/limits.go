@@ -0,0 +1,148 @@
+package gql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// MaxDepth is how deeply fields may be nested, e.g. 4 for
+	// "me { plants { cares { name } } }".
+	MaxDepth = 8
+	// MaxComplexity bounds the estimated cost of a query: every field costs
+	// 1, and the fields below a list are counted listFactor times.
+	MaxComplexity = 1000
+
+	listFactor = 10
+)
+
+// checkLimits rejects the queries of doc that nest deeper than MaxDepth or
+// cost more than MaxComplexity. Introspection fields are not counted, so that
+// tools can still load the schema. doc must have been validated.
+func checkLimits(schema *graphql.Schema, doc *ast.Document) error {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	w := &limitWalker{schema: schema, fragments: fragments}
+
+	for _, def := range doc.Definitions {
+		operation, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		root := schema.QueryType()
+		if operation.Operation != ast.OperationTypeQuery {
+			root = schema.MutationType()
+		}
+		if root == nil {
+			continue
+		}
+
+		depth, complexity := w.selectionSet(root, operation.SelectionSet)
+		if depth > MaxDepth {
+			return fmt.Errorf("query is nested %d levels deep, more than the %d allowed", depth, MaxDepth)
+		}
+		if complexity > MaxComplexity {
+			return fmt.Errorf("query has a complexity of %d, more than the %d allowed", complexity, MaxComplexity)
+		}
+	}
+
+	return nil
+}
+
+type limitWalker struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+}
+
+// selectionSet returns the depth and complexity of the selections made on
+// parent.
+func (w *limitWalker) selectionSet(parent *graphql.Object, set *ast.SelectionSet) (int, int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	depth, complexity := 0, 0
+	for _, selection := range set.Selections {
+		var d, c int
+
+		switch selection := selection.(type) {
+		case *ast.Field:
+			d, c = w.field(parent, selection)
+		case *ast.InlineFragment:
+			d, c = w.selectionSet(w.typeCondition(parent, selection.TypeCondition), selection.SelectionSet)
+		case *ast.FragmentSpread:
+			fragment, ok := w.fragments[selection.Name.Value]
+			if !ok {
+				continue
+			}
+			d, c = w.selectionSet(w.typeCondition(parent, fragment.TypeCondition), fragment.SelectionSet)
+		}
+
+		if d > depth {
+			depth = d
+		}
+		complexity += c
+	}
+
+	return depth, complexity
+}
+
+func (w *limitWalker) field(parent *graphql.Object, field *ast.Field) (int, int) {
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return 0, 0
+	}
+
+	def, ok := parent.Fields()[field.Name.Value]
+	if !ok {
+		return 1, 1
+	}
+
+	object, list := unwrap(def.Type)
+	if object == nil {
+		return 1, 1
+	}
+
+	depth, complexity := w.selectionSet(object, field.SelectionSet)
+	if list {
+		complexity *= listFactor
+	}
+	return depth + 1, complexity + 1
+}
+
+func (w *limitWalker) typeCondition(parent *graphql.Object, condition *ast.Named) *graphql.Object {
+	if condition == nil {
+		return parent
+	}
+	if object, ok := w.schema.Type(condition.Name.Value).(*graphql.Object); ok {
+		return object
+	}
+	return parent
+}
+
+// unwrap returns the object type of a field, if any, and whether the field
+// is a list of it.
+func unwrap(t graphql.Type) (*graphql.Object, bool) {
+	list := false
+	for {
+		switch wrapped := t.(type) {
+		case *graphql.NonNull:
+			t = wrapped.OfType
+		case *graphql.List:
+			list = true
+			t = wrapped.OfType
+		case *graphql.Object:
+			return wrapped, list
+		default:
+			return nil, list
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+// loader batches the lookups made while resolving one level of a query. Load
+// only queues the key and returns a thunk; the executor calls the thunks
+// once every field of the level was resolved, and the first of them fetches
+// every queued key at once. Results are cached for the rest of the request.
+type loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	values  map[K]V
+	errs    map[K]error
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:  fetch,
+		queued: make(map[K]bool),
+		values: make(map[K]V),
+		errs:   make(map[K]error),
+	}
+}
+
+// Load returns a thunk resolving to the value of key, or to nil when there is
+// none.
+func (l *loader[K, V]) Load(ctx context.Context, key K) func() (interface{}, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		value, ok, err := l.get(ctx, key)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return value, nil
+	}
+}
+
+func (l *loader[K, V]) get(ctx context.Context, key K) (V, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.pending) > 0 {
+		keys := l.pending
+		l.pending = nil
+
+		values, err := l.fetch(ctx, keys)
+		for _, k := range keys {
+			if err != nil {
+				l.errs[k] = err
+			} else if v, ok := values[k]; ok {
+				l.values[k] = v
+			}
+		}
+	}
+
+	if err := l.errs[key]; err != nil {
+		var zero V
+		return zero, false, err
+	}
+
+	value, ok := l.values[key]
+	return value, ok, nil
+}
+
+// loaders are the loaders of one request.
+type loaders struct {
+	plants       *loader[int64, *domain.Plant]
+	caresByPlant *loader[int64, []*domain.Care]
+	users        *loader[int64, *domain.User]
+}
+
+func newLoaders(uStorer domain.UserStorer, pStorer domain.PlantStorer, cStorer domain.CareStorer) *loaders {
+	return &loaders{
+		plants: newLoader(func(ctx context.Context, ids []int64) (map[int64]*domain.Plant, error) {
+			plants, err := pStorer.GetPlantsByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			byId := make(map[int64]*domain.Plant, len(plants))
+			for _, plant := range plants {
+				byId[plant.Id] = plant
+			}
+			return byId, nil
+		}),
+		caresByPlant: newLoader(func(ctx context.Context, plantIds []int64) (map[int64][]*domain.Care, error) {
+			cares, err := cStorer.GetCaresByPlantIDs(ctx, plantIds)
+			if err != nil {
+				return nil, err
+			}
+
+			// Plants without cares resolve to an empty list rather than null.
+			byPlant := make(map[int64][]*domain.Care, len(plantIds))
+			for _, id := range plantIds {
+				byPlant[id] = []*domain.Care{}
+			}
+			for _, care := range cares {
+				byPlant[care.PlantId] = append(byPlant[care.PlantId], care)
+			}
+			return byPlant, nil
+		}),
+		// Plants are almost always listed along with their owner, so the
+		// owners of a page are usually a single user; looking them up one by
+		// one, once each, is enough.
+		users: newLoader(func(ctx context.Context, ids []int64) (map[int64]*domain.User, error) {
+			users := make(map[int64]*domain.User, len(ids))
+			for _, id := range ids {
+				user, err := uStorer.GetUserByID(ctx, id)
+				if errors.Is(err, errs.ErrSelectNotMatch) {
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				users[id] = user
+			}
+			return users, nil
+		}),
+	}
+}
@@ -0,0 +1,324 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+type requestKey struct{}
+
+// request is what the resolvers of one request share: who makes it and the
+// loaders caching what was loaded so far.
+type request struct {
+	subject authz.Subject
+	loaders *loaders
+}
+
+func withRequest(ctx context.Context, r *request) context.Context {
+	return context.WithValue(ctx, requestKey{}, r)
+}
+
+func requestFrom(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+type resolver struct {
+	uStorer domain.UserStorer
+	pStorer domain.PlantStorer
+	cStorer domain.CareStorer
+	policy  *authz.Policy
+}
+
+func newSchema(r *resolver) (graphql.Schema, error) {
+	var userType, plantType, careType *graphql.Object
+
+	recurrenceType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Recurrence",
+		Fields: graphql.Fields{
+			"rrule":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"start":    &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"timezone": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"exdates":  &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.DateTime))},
+		},
+	})
+
+	checklistItemType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ChecklistItem",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+			"title":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"checked":   &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"checkedAt": &graphql.Field{Type: graphql.DateTime},
+		},
+	})
+
+	statusArgs := graphql.FieldConfigArgument{
+		"status": &graphql.ArgumentConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "Statuses to include. Only active plants are returned when omitted, and every plant for an empty list.",
+		},
+	}
+
+	userType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.ID),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source.(*domain.User).ExternalId, nil
+					},
+				},
+				"username": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"email":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"roles":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+				"active":   &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+				"verified": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+				"timezone": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"locale":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"plants": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(plantType))),
+					Args:    statusArgs,
+					Resolve: r.userPlants,
+				},
+			}
+		}),
+	})
+
+	plantType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Plant",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":              &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"name":            &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"species":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"location":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"acquisitionDate": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"careFrequency":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"status":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"statusReason":    &graphql.Field{Type: graphql.String},
+				"statusChangedAt": &graphql.Field{Type: graphql.DateTime},
+				"version":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"createdAt":       &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"updatedAt":       &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"owner": &graphql.Field{
+					Type: userType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return requestFrom(p.Context).loaders.users.Load(p.Context, p.Source.(*domain.Plant).UserId), nil
+					},
+				},
+				"cares": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(careType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return requestFrom(p.Context).loaders.caresByPlant.Load(p.Context, p.Source.(*domain.Plant).Id), nil
+					},
+				},
+			}
+		}),
+	})
+
+	careType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Care",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+				"name":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"notes":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"interval":    &graphql.Field{Type: graphql.Int},
+				"lastCare":    &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"nextCare":    &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"snoozedFrom": &graphql.Field{Type: graphql.DateTime},
+				"recurrence":  &graphql.Field{Type: recurrenceType},
+				"checklist":   &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(checklistItemType))},
+				"version":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"createdAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"updatedAt":   &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"plant": &graphql.Field{
+					Type: plantType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return requestFrom(p.Context).loaders.plants.Load(p.Context, p.Source.(*domain.Care).PlantId), nil
+					},
+				},
+			}
+		}),
+	})
+
+	idArgs := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+	}
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"me": &graphql.Field{
+				Type: graphql.NewNonNull(userType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					req := requestFrom(p.Context)
+					return req.loaders.users.Load(p.Context, req.subject.UserId), nil
+				},
+			},
+			"user": &graphql.Field{
+				Type:        userType,
+				Description: "A user by id. Users may only look themselves up, admins may look up anyone.",
+				Args:        idArgs,
+				Resolve:     r.user,
+			},
+			"plants": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(plantType))),
+				Args:    statusArgs,
+				Resolve: r.plants,
+			},
+			"plant": &graphql.Field{
+				Type:    plantType,
+				Args:    idArgs,
+				Resolve: r.plant,
+			},
+			"cares": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(careType))),
+				Resolve: r.cares,
+			},
+			"care": &graphql.Field{
+				Type:    careType,
+				Args:    idArgs,
+				Resolve: r.care,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+func (r *resolver) user(p graphql.ResolveParams) (interface{}, error) {
+	req := requestFrom(p.Context)
+
+	user, err := r.uStorer.GetUserByExternalId(p.Context, p.Args["id"].(string))
+	if errors.Is(err, errs.ErrSelectNotMatch) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if ok, err := r.visible(p.Context, req.subject, domain.AuditEntityUser, user.Id, user.Id); !ok {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *resolver) plants(p graphql.ResolveParams) (interface{}, error) {
+	req := requestFrom(p.Context)
+	return r.plantsOf(p, req.subject.UserId)
+}
+
+func (r *resolver) userPlants(p graphql.ResolveParams) (interface{}, error) {
+	return r.plantsOf(p, p.Source.(*domain.User).Id)
+}
+
+// plantsOf lists the plants of userId in the statuses of the status
+// argument.
+func (r *resolver) plantsOf(p graphql.ResolveParams, userId int64) (interface{}, error) {
+	req := requestFrom(p.Context)
+
+	statuses := []string{domain.PlantStatusActive}
+	if arg, ok := p.Args["status"].([]interface{}); ok {
+		statuses = make([]string, 0, len(arg))
+		for _, status := range arg {
+			if !domain.IsValidPlantStatus(status.(string)) {
+				return nil, errs.ErrInvalidPlantStatus
+			}
+			statuses = append(statuses, status.(string))
+		}
+	}
+
+	plants, err := r.pStorer.GetPlantsByUserID(p.Context, userId, statuses...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, plant := range plants {
+		if err := r.policy.Authorize(p.Context, req.subject, domain.AuditEntityPlant, plant.Id, plant.UserId); err != nil {
+			return nil, err
+		}
+	}
+
+	if plants == nil {
+		plants = []*domain.Plant{}
+	}
+	return plants, nil
+}
+
+func (r *resolver) plant(p graphql.ResolveParams) (interface{}, error) {
+	req := requestFrom(p.Context)
+
+	id, err := strconv.ParseInt(p.Args["id"].(string), 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+
+	load := req.loaders.plants.Load(p.Context, id)
+	return func() (interface{}, error) {
+		v, err := load()
+		if err != nil || v == nil {
+			return nil, err
+		}
+
+		plant := v.(*domain.Plant)
+		if ok, err := r.visible(p.Context, req.subject, domain.AuditEntityPlant, plant.Id, plant.UserId); !ok {
+			return nil, err
+		}
+		return plant, nil
+	}, nil
+}
+
+func (r *resolver) cares(p graphql.ResolveParams) (interface{}, error) {
+	req := requestFrom(p.Context)
+
+	cares, err := r.cStorer.GetCaresByUserID(p.Context, req.subject.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if cares == nil {
+		cares = []*domain.Care{}
+	}
+	return cares, nil
+}
+
+func (r *resolver) care(p graphql.ResolveParams) (interface{}, error) {
+	req := requestFrom(p.Context)
+
+	id, err := strconv.ParseInt(p.Args["id"].(string), 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+
+	care, err := r.cStorer.GetCareByID(p.Context, id)
+	if errors.Is(err, errs.ErrSelectNotMatch) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if ok, err := r.visible(p.Context, req.subject, domain.AuditEntityCare, care.Id, care.UserId); !ok {
+		return nil, err
+	}
+	return care, nil
+}
+
+// visible applies the policy to an entity looked up by id. As with the REST
+// API, entities the user may not access look missing: the field resolves to
+// null without an error.
+func (r *resolver) visible(ctx context.Context, subject authz.Subject, entityType string, entityId, ownerId int64) (bool, error) {
+	err := r.policy.Authorize(ctx, subject, entityType, entityId, ownerId)
+	if errors.Is(err, errs.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
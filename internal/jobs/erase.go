@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+)
+
+// erase removes the export archives of the user, then the user. Erasing the
+// user removes its jobs too, this one included.
+func (r *Runner) erase(ctx context.Context, job *domain.Job) error {
+	jobs, err := r.jStorer.GetJobsByUserID(ctx, job.UserId)
+	if err != nil {
+		return err
+	}
+
+	for _, j := range jobs {
+		if j.Kind != domain.JobKindExport || j.Result == "" {
+			continue
+		}
+		if err := os.Remove(j.Result); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	err = r.uStorer.EraseUser(ctx, job.UserId)
+	if err != nil && !errors.Is(err, errs.ErrNoRowsAffected) {
+		return err
+	}
+
+	job.Finish("", nil)
+	return nil
+}
@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mailer"
+)
+
+// ExportKey is the cache key holding the job id a download token points to.
+func ExportKey(token string) string {
+	return "export:" + token
+}
+
+// exportData holds everything the user owns. Checklists are part of their
+// cares.
+type exportData struct {
+	Profile   *domain.User               `json:"profile"`
+	Plants    []*domain.Plant            `json:"plants"`
+	Cares     []*domain.Care             `json:"cares"`
+	History   []*domain.CareHistoryEntry `json:"history"`
+	Notes     []*domain.NoteRevision     `json:"notes"`
+	Templates []*domain.CareTemplate     `json:"templates"`
+}
+
+func (r *Runner) export(ctx context.Context, job *domain.Job) error {
+	user, err := r.uStorer.GetUserByExternalId(ctx, job.UserExternalId)
+	if err != nil {
+		return err
+	}
+
+	if deliverAt := user.NextDeliveryTime(time.Now()); deliverAt.After(time.Now()) {
+		job.Defer(deliverAt)
+		return nil
+	}
+
+	plants, err := r.pStorer.GetPlantsByUserID(ctx, user.Id)
+	if err != nil {
+		return err
+	}
+
+	cares, err := r.cStorer.GetCaresByUserID(ctx, user.Id)
+	if err != nil {
+		return err
+	}
+
+	history, err := r.cStorer.GetCareHistoryByUserID(ctx, user.Id, time.Time{}, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var notes []*domain.NoteRevision
+	for _, plant := range plants {
+		revisions, err := r.nStorer.GetNoteRevisions(ctx, plant.Id)
+		if err != nil {
+			return err
+		}
+		notes = append(notes, revisions...)
+	}
+
+	visible, err := r.tStorer.GetCareTemplatesByUserID(ctx, user.Id)
+	if err != nil {
+		return err
+	}
+
+	var templates []*domain.CareTemplate
+	for _, template := range visible {
+		if !template.BuiltIn {
+			templates = append(templates, template)
+		}
+	}
+
+	path := filepath.Join(r.exportsDir, job.ExternalId+".zip")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := writeExport(f, exportData{
+		Profile:   user,
+		Plants:    plants,
+		Cares:     cares,
+		History:   history,
+		Notes:     notes,
+		Templates: templates,
+	}); err != nil {
+		return err
+	}
+
+	token := uuid.NewString()
+	if err := r.cacher.Set(ctx, ExportLinkTTL, ExportKey(token), job.ExternalId); err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(ExportLinkTTL)
+	link := fmt.Sprintf("%s/api/v1/exports/%s", r.baseURL, token)
+	if err := mailer.Send(ctx, r.mailer, user.Email, user.Locale, mailer.DataExport{Username: user.Username, Link: link, ExpiresAt: expiresAt.In(user.Location())}); err != nil {
+		return err
+	}
+
+	job.Finish(path, &expiresAt)
+	return nil
+}
+
+// writeExport writes the archive with one JSON document per data set.
+func writeExport(w io.Writer, data exportData) error {
+	zw := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		v    any
+	}{
+		{"profile.json", data.Profile},
+		{"plants.json", data.Plants},
+		{"cares.json", data.Cares},
+		{"history.json", data.History},
+		{"notes.json", data.Notes},
+		{"templates.json", data.Templates},
+	}
+
+	for _, file := range files {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.v); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
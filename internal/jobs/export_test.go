@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteExport(t *testing.T) {
+	cases := []struct {
+		purpose   string
+		data      exportData
+		wantFiles []string
+	}{
+		{
+			"should write one file per data set",
+			exportData{
+				Profile:   &domain.User{ExternalId: "abc", Username: "mathe", Email: "mathe@example.com"},
+				Plants:    []*domain.Plant{{Id: 1, Name: "Fern"}},
+				Cares:     []*domain.Care{{Id: 2, PlantId: 1, Name: "Water", Checklist: []*domain.ChecklistItem{{Id: 3, Title: "Check the soil"}}}},
+				History:   []*domain.CareHistoryEntry{{Id: 4, CareId: 2, Kind: domain.CareHistoryCompleted}},
+				Notes:     []*domain.NoteRevision{{Id: 5, PlantId: 1, Revision: 1, Body: "Likes shade"}},
+				Templates: []*domain.CareTemplate{{Id: 6, Name: "Ferns"}},
+			},
+			[]string{"profile.json", "plants.json", "cares.json", "history.json", "notes.json", "templates.json"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writeExport(&buf, tt.data)
+			assert.NoError(t, err)
+
+			zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			assert.NoError(t, err)
+
+			var names []string
+			for _, f := range zr.File {
+				names = append(names, f.Name)
+
+				rc, err := f.Open()
+				assert.NoError(t, err)
+
+				var v any
+				assert.NoError(t, json.NewDecoder(rc).Decode(&v))
+				rc.Close()
+			}
+			assert.Equal(t, tt.wantFiles, names)
+		})
+	}
+}
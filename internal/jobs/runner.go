@@ -0,0 +1,154 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/cache"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	l "github.com/mathehluiz/plant-care-tracker/pkg/logger"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mailer"
+	"go.uber.org/zap"
+)
+
+// ExportLinkTTL is how long a data export download link stays valid.
+var ExportLinkTTL = 24 * time.Hour
+
+const (
+	batchSize = 10
+
+	// jobLease is how long a claimed job may run before it is considered
+	// interrupted, e.g. by a crash, and claimed again. It must be longer
+	// than the slowest export.
+	jobLease = 10 * time.Minute
+)
+
+var errJobInterrupted = errors.New("job was interrupted")
+
+type Runner struct {
+	jStorer    domain.JobStorer
+	uStorer    domain.UserStorer
+	pStorer    domain.PlantStorer
+	cStorer    domain.CareStorer
+	tStorer    domain.CareTemplateStorer
+	nStorer    domain.NoteStorer
+	cacher     cache.ConnectionStorer
+	mailer     mailer.Mailer
+	exportsDir string
+	baseURL    string
+	interval   time.Duration
+}
+
+func NewRunner(jStorer domain.JobStorer, uStorer domain.UserStorer, pStorer domain.PlantStorer, cStorer domain.CareStorer, tStorer domain.CareTemplateStorer, nStorer domain.NoteStorer, cacher cache.ConnectionStorer, m mailer.Mailer) *Runner {
+	exportsDir := os.Getenv("EXPORTS_DIR")
+	if exportsDir == "" {
+		exportsDir = filepath.Join(os.TempDir(), "plant-care-tracker-exports")
+	}
+
+	baseURL := os.Getenv("APP_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+
+	return &Runner{
+		jStorer:    jStorer,
+		uStorer:    uStorer,
+		pStorer:    pStorer,
+		cStorer:    cStorer,
+		tStorer:    tStorer,
+		nStorer:    nStorer,
+		cacher:     cacher,
+		mailer:     m,
+		exportsDir: exportsDir,
+		baseURL:    baseURL,
+		interval:   30 * time.Second,
+	}
+}
+
+// Start polls for due jobs until ctx is cancelled. It is meant to be run in
+// its own goroutine.
+func (r *Runner) Start(ctx context.Context) {
+	if err := os.MkdirAll(r.exportsDir, 0o700); err != nil {
+		l.Logger.Error("cannot create exports dir", zap.Error(err), zap.String("dir", r.exportsDir))
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) RunOnce(ctx context.Context) {
+	now := time.Now()
+
+	jobs, err := r.jStorer.ClaimPendingJobs(ctx, now, jobLease, batchSize)
+	if err != nil {
+		l.Logger.Error("cannot claim pending jobs", zap.Error(err))
+		return
+	}
+
+	for _, job := range jobs {
+		r.run(ctx, job)
+	}
+
+	r.expireExports(ctx, now)
+}
+
+func (r *Runner) run(ctx context.Context, job *domain.Job) {
+	var err error
+
+	switch {
+	case job.Attempts > domain.JobMaxAttempts:
+		// Every attempt was interrupted before it could fail or finish.
+		err = errJobInterrupted
+	case job.Kind == domain.JobKindExport:
+		err = r.export(ctx, job)
+	case job.Kind == domain.JobKindErase:
+		err = r.erase(ctx, job)
+	default:
+		l.Logger.Error("unknown job kind", zap.String("kind", job.Kind), zap.String("job", job.ExternalId))
+		return
+	}
+
+	if err != nil {
+		l.Logger.Error("job failed", zap.Error(err), zap.String("kind", job.Kind), zap.String("job", job.ExternalId))
+		job.Fail(err)
+	}
+
+	// A finished erase job went away with the rest of the user's jobs.
+	if err := r.jStorer.UpdateJob(ctx, job); err != nil && !(job.Kind == domain.JobKindErase && errors.Is(err, errs.ErrNoRowsAffected)) {
+		l.Logger.Error("cannot update job", zap.Error(err), zap.String("job", job.ExternalId))
+	}
+}
+
+func (r *Runner) expireExports(ctx context.Context, now time.Time) {
+	jobs, err := r.jStorer.GetExpiredJobs(ctx, now)
+	if err != nil {
+		l.Logger.Error("cannot list expired jobs", zap.Error(err))
+		return
+	}
+
+	for _, job := range jobs {
+		if err := os.Remove(job.Result); err != nil && !os.IsNotExist(err) {
+			l.Logger.Error("cannot remove export", zap.Error(err), zap.String("job", job.ExternalId))
+			continue
+		}
+
+		job.Expire()
+		if err := r.jStorer.UpdateJob(ctx, job); err != nil {
+			l.Logger.Error("cannot update job", zap.Error(err), zap.String("job", job.ExternalId))
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Package live pushes the plant and care events of a user to the clients the
+// user has connected, on every replica.
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	l "github.com/mathehluiz/plant-care-tracker/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	channelPrefix = "live:user:"
+	bufferPrefix  = "live:buffer:"
+
+	// bufferSize and bufferTTL bound the replay buffer of each user: the
+	// last bufferSize messages, kept for bufferTTL after the last one.
+	bufferSize = 100
+	bufferTTL  = time.Hour
+
+	// clientBuffer is how many messages a slow client may fall behind
+	// before it is disconnected, to resume with Last-Event-ID.
+	clientBuffer = 64
+)
+
+// Types are the events pushed to the clients.
+var Types = []string{
+	domain.EventPlantCreated, domain.EventPlantUpdated, domain.EventPlantDeleted,
+	domain.EventCareCreated, domain.EventCareUpdated, domain.EventCareDeleted,
+	domain.EventCareCompleted, domain.EventCareSkipped, domain.EventCareSnoozed, domain.EventCareDue,
+}
+
+// Message is an event as pushed to the clients. Id is its position in the
+// replay buffer of the user, which only grows.
+type Message struct {
+	Id   string          `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Hub fans the messages of a user out to the clients connected to this
+// replica. Messages are published to a Redis channel per user, so clients
+// get them whichever replica handled the event.
+type Hub struct {
+	client  *redis.Client
+	mu      sync.Mutex
+	clients map[int64]map[chan Message]struct{}
+}
+
+func NewHub(client *redis.Client) *Hub {
+	return &Hub{
+		client:  client,
+		clients: make(map[int64]map[chan Message]struct{}),
+	}
+}
+
+// BufferKey is the Redis stream holding the replay buffer of the user.
+func BufferKey(userId int64) string {
+	return bufferPrefix + strconv.FormatInt(userId, 10)
+}
+
+// Handle is the bus handler of the hub: it appends the event to the replay
+// buffer of its owner and publishes it to every replica.
+func (h *Hub) Handle(ctx context.Context, event *domain.Event) error {
+	key := BufferKey(event.UserId)
+
+	id, err := h.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: bufferSize,
+		Values: map[string]any{"type": event.Type, "data": string(event.Payload)},
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	if err := h.client.Expire(ctx, key, bufferTTL).Err(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(Message{Id: id, Type: event.Type, Data: event.Payload})
+	if err != nil {
+		return err
+	}
+
+	return h.client.Publish(ctx, channelPrefix+strconv.FormatInt(event.UserId, 10), data).Err()
+}
+
+// Start relays the messages published by every replica to the local clients
+// until ctx is cancelled. It is meant to be run in its own goroutine.
+func (h *Hub) Start(ctx context.Context) {
+	pubsub := h.client.PSubscribe(ctx, channelPrefix+"*")
+	defer pubsub.Close()
+
+	channel := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case published, ok := <-channel:
+			if !ok {
+				return
+			}
+			h.dispatch(published)
+		}
+	}
+}
+
+func (h *Hub) dispatch(published *redis.Message) {
+	userId, err := strconv.ParseInt(strings.TrimPrefix(published.Channel, channelPrefix), 10, 64)
+	if err != nil {
+		return
+	}
+
+	var message Message
+	if err := json.Unmarshal([]byte(published.Payload), &message); err != nil {
+		l.Logger.Error("cannot decode live message", zap.Error(err), zap.String("channel", published.Channel))
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range h.clients[userId] {
+		select {
+		case client <- message:
+		default:
+			// The client fell behind: close it so that it reconnects and
+			// catches up from the replay buffer.
+			h.remove(userId, client)
+		}
+	}
+}
+
+// Subscribe returns the messages of the user pushed from now on, until
+// unsubscribe is called or the channel is closed because the client fell
+// behind.
+func (h *Hub) Subscribe(userId int64) (messages <-chan Message, unsubscribe func()) {
+	client := make(chan Message, clientBuffer)
+
+	h.mu.Lock()
+	if h.clients[userId] == nil {
+		h.clients[userId] = make(map[chan Message]struct{})
+	}
+	h.clients[userId][client] = struct{}{}
+	h.mu.Unlock()
+
+	return client, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(userId, client)
+	}
+}
+
+// remove closes the client channel, if still open. h.mu must be held.
+func (h *Hub) remove(userId int64, client chan Message) {
+	if _, ok := h.clients[userId][client]; !ok {
+		return
+	}
+
+	delete(h.clients[userId], client)
+	if len(h.clients[userId]) == 0 {
+		delete(h.clients, userId)
+	}
+	close(client)
+}
+
+// Replay returns the messages of the user still in the replay buffer that
+// came after lastId. An invalid lastId replays nothing.
+func (h *Hub) Replay(ctx context.Context, userId int64, lastId string) ([]Message, error) {
+	if !validId(lastId) {
+		return nil, nil
+	}
+
+	entries, err := h.client.XRange(ctx, BufferKey(userId), lastId, "+").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]Message, 0, len(entries))
+	for _, entry := range entries {
+		if entry.ID == lastId {
+			continue
+		}
+
+		eventType, _ := entry.Values["type"].(string)
+		data, _ := entry.Values["data"].(string)
+		messages = append(messages, Message{Id: entry.ID, Type: eventType, Data: json.RawMessage(data)})
+	}
+	return messages, nil
+}
+
+// After reports whether the message id comes after the id other, e.g. the
+// last one sent to a client. Every id comes after the empty one.
+func After(id, other string) bool {
+	ms, seq, ok := parseId(id)
+	if !ok {
+		return false
+	}
+
+	otherMs, otherSeq, ok := parseId(other)
+	if !ok {
+		return true
+	}
+
+	return ms > otherMs || (ms == otherMs && seq > otherSeq)
+}
+
+func validId(id string) bool {
+	_, _, ok := parseId(id)
+	return ok
+}
+
+// parseId splits a Redis stream id, "<milliseconds>-<sequence>".
+func parseId(id string) (uint64, uint64, bool) {
+	msPart, seqPart, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	seq, err := strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return ms, seq, true
+}
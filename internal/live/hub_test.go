@@ -0,0 +1,130 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHub(t *testing.T) *Hub {
+	mock, err := cache.StartMock()
+	assert.NoError(t, err)
+	t.Cleanup(func() { mock.Close() })
+
+	return NewHub(mock.Client())
+}
+
+func newTestEvent(t *testing.T, eventType string, userId int64) *domain.Event {
+	event, err := domain.NewEvent(eventType, "1", userId, map[string]any{"ok": true})
+	assert.NoError(t, err)
+	return event
+}
+
+func TestHubFanOut(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := newTestHub(t)
+	go hub.Start(ctx)
+
+	mine, unsubscribe := hub.Subscribe(1)
+	defer unsubscribe()
+	theirs, unsubscribeTheirs := hub.Subscribe(2)
+	defer unsubscribeTheirs()
+
+	// Wait for the pattern subscription to be active.
+	assert.Eventually(t, func() bool {
+		n, err := hub.client.PubSubNumPat(ctx).Result()
+		return err == nil && n == 1
+	}, time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, hub.Handle(ctx, newTestEvent(t, domain.EventPlantCreated, 1)))
+
+	select {
+	case message := <-mine:
+		assert.Equal(t, domain.EventPlantCreated, message.Type)
+		assert.JSONEq(t, `{"ok":true}`, string(message.Data))
+	case <-time.After(time.Second):
+		t.Fatal("the message was not pushed")
+	}
+
+	select {
+	case <-theirs:
+		t.Fatal("the message was pushed to another user")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHubReplay(t *testing.T) {
+	ctx := context.Background()
+	hub := newTestHub(t)
+
+	for _, eventType := range []string{domain.EventPlantCreated, domain.EventCareCreated, domain.EventCareDue} {
+		assert.NoError(t, hub.Handle(ctx, newTestEvent(t, eventType, 1)))
+	}
+	assert.NoError(t, hub.Handle(ctx, newTestEvent(t, domain.EventPlantDeleted, 2)))
+
+	all, err := hub.Replay(ctx, 1, "0-0")
+	assert.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	cases := []struct {
+		purpose   string
+		lastId    string
+		wantTypes []string
+	}{
+		{
+			"should replay the messages after the last one seen",
+			all[0].Id,
+			[]string{domain.EventCareCreated, domain.EventCareDue},
+		},
+		{
+			"should replay nothing when the client is up to date",
+			all[2].Id,
+			[]string{},
+		},
+		{
+			"should replay nothing for an invalid id",
+			"not-an-id",
+			[]string{},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			messages, err := hub.Replay(ctx, 1, tt.lastId)
+			assert.NoError(t, err)
+
+			types := []string{}
+			for _, message := range messages {
+				types = append(types, message.Type)
+			}
+			assert.Equal(t, tt.wantTypes, types)
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	cases := []struct {
+		purpose string
+		id      string
+		other   string
+		want    bool
+	}{
+		{"should come after an empty id", "1-0", "", true},
+		{"should compare the milliseconds first", "10-0", "9-5", true},
+		{"should compare the sequence on the same millisecond", "10-1", "10-2", false},
+		{"should not come after itself", "10-1", "10-1", false},
+		{"should not order invalid ids", "x", "10-1", false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			assert.Equal(t, tt.want, After(tt.id, tt.other))
+		})
+	}
+}
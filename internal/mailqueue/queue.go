@@ -0,0 +1,161 @@
+// Package mailqueue sends emails through a queue saved in the database, so
+// that a slow or failing provider neither holds up requests nor loses an
+// email.
+package mailqueue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	l "github.com/mathehluiz/plant-care-tracker/pkg/logger"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mailer"
+	"go.uber.org/zap"
+)
+
+const (
+	batchSize = 20
+
+	// emailRetention is how long the emails that left the queue are kept,
+	// for users to follow their delivery, before they are pruned.
+	emailRetention = 30 * 24 * time.Hour
+	pruneInterval  = time.Hour
+)
+
+// Queue is a mailer.Mailer that saves the emails for a Sender to send.
+type Queue struct {
+	eStorer domain.EmailStorer
+	uStorer domain.UserStorer
+}
+
+func NewQueue(eStorer domain.EmailStorer, uStorer domain.UserStorer) *Queue {
+	return &Queue{eStorer: eStorer, uStorer: uStorer}
+}
+
+// Deliver queues email, to be sent right away, or once the quiet hours of
+// the recipient end if it is a notification.
+func (q *Queue) Deliver(ctx context.Context, to string, email mailer.Email) error {
+	delivery := domain.NewEmailDelivery(to, email.Template, email.Subject, email.HTML, email.Text)
+	delivery.ExpiresAt = email.ExpiresAt
+
+	if mailer.IsNotification(email.Template) {
+		user, err := q.uStorer.GetUserByEmail(ctx, to)
+		if err != nil && !errors.Is(err, errs.ErrSelectNotMatch) {
+			return err
+		}
+		if user != nil {
+			delivery.NextAttemptAt = user.NextDeliveryTime(delivery.NextAttemptAt)
+		}
+	}
+
+	return q.eStorer.CreateEmailDelivery(ctx, delivery)
+}
+
+// Sender sends the queued emails with a mailer. Failed emails are retried
+// with an exponential backoff and marked as dead after
+// domain.EmailMaxAttempts, as are the emails that expire before they are
+// sent. The emails of a recipient that already got domain.EmailRateLimit
+// emails within domain.EmailRateWindow are postponed until they fall below
+// the limit.
+type Sender struct {
+	eStorer   domain.EmailStorer
+	mailer    mailer.Mailer
+	interval  time.Duration
+	lastPrune time.Time
+}
+
+func NewSender(eStorer domain.EmailStorer, m mailer.Mailer) *Sender {
+	return &Sender{
+		eStorer:  eStorer,
+		mailer:   m,
+		interval: 5 * time.Second,
+	}
+}
+
+// Start polls for queued emails until ctx is cancelled. It is meant to be
+// run in its own goroutine.
+func (s *Sender) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends the emails that are due, and prunes the ones that left the
+// queue once every pruneInterval.
+func (s *Sender) RunOnce(ctx context.Context) {
+	now := time.Now().UTC()
+
+	deliveries, err := s.eStorer.ClaimEmailDeliveries(ctx, now, domain.DeliveryLease, batchSize)
+	if err != nil {
+		l.Logger.Error("cannot claim email deliveries", zap.Error(err))
+		return
+	}
+
+	for _, delivery := range deliveries {
+		s.send(ctx, delivery)
+	}
+
+	if now.Sub(s.lastPrune) < pruneInterval {
+		return
+	}
+
+	if err := s.eStorer.DeleteEmailDeliveries(ctx, now.Add(-emailRetention)); err != nil {
+		l.Logger.Error("cannot prune email deliveries", zap.Error(err))
+		return
+	}
+	s.lastPrune = now
+}
+
+func (s *Sender) send(ctx context.Context, delivery *domain.EmailDelivery) {
+	since := time.Now().UTC().Add(-domain.EmailRateWindow)
+	sent, err := s.eStorer.CountSentEmails(ctx, delivery.Recipient, since)
+	if err != nil {
+		l.Logger.Error("cannot count sent emails", zap.Error(err), zap.String("email", delivery.ExternalId))
+		return
+	}
+
+	now := time.Now().UTC()
+	if delivery.Expired(now) {
+		// Such as a code that expired while the email was postponed.
+		delivery.Expire(now)
+		l.Logger.Warn("email expired before it was sent", zap.String("email", delivery.ExternalId), zap.String("template", delivery.Template))
+	} else if sent >= domain.EmailRateLimit {
+		// Checked again when the lease expires, by when some of the
+		// emails may have left the window.
+		delivery.Postpone(now.Add(domain.DeliveryLease), now)
+	} else if err := s.deliver(ctx, delivery); err != nil {
+		delivery.Fail(err, time.Now().UTC())
+		if delivery.Status == domain.EmailDead {
+			l.Logger.Warn("email is dead", zap.Error(err), zap.String("email", delivery.ExternalId), zap.String("template", delivery.Template))
+		}
+	} else {
+		delivery.Succeed(time.Now().UTC())
+	}
+
+	if err := s.eStorer.UpdateEmailDelivery(ctx, delivery); err != nil {
+		l.Logger.Error("cannot update email delivery", zap.Error(err), zap.String("email", delivery.ExternalId))
+	}
+}
+
+func (s *Sender) deliver(ctx context.Context, delivery *domain.EmailDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, domain.DeliveryLease/2)
+	defer cancel()
+
+	return s.mailer.Deliver(ctx, delivery.Recipient, mailer.Email{
+		Template: delivery.Template,
+		Subject:  delivery.Subject,
+		HTML:     delivery.HTML,
+		Text:     delivery.Text,
+	})
+}
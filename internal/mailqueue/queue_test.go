@@ -0,0 +1,309 @@
+package mailqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStorer keeps the queue in memory, claiming emails the way the
+// repository does.
+type memoryStorer struct {
+	mu         sync.Mutex
+	deliveries []*domain.EmailDelivery
+	prunes     []time.Time
+}
+
+func (s *memoryStorer) CreateEmailDelivery(ctx context.Context, d *domain.EmailDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d.Id = int64(len(s.deliveries) + 1)
+	d.ExternalId = fmt.Sprint("email-", d.Id)
+	copied := *d
+	s.deliveries = append(s.deliveries, &copied)
+	return nil
+}
+
+func (s *memoryStorer) GetEmailDeliveryByExternalId(ctx context.Context, id string) (*domain.EmailDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, d := range s.deliveries {
+		if d.ExternalId == id {
+			copied := *d
+			return &copied, nil
+		}
+	}
+	return nil, errs.ErrSelectNotMatch
+}
+
+func (s *memoryStorer) GetEmailDeliveries(ctx context.Context, filter domain.EmailFilter) ([]*domain.EmailDelivery, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *memoryStorer) ClaimEmailDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.EmailDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var claimed []*domain.EmailDelivery
+	for _, d := range s.deliveries {
+		if len(claimed) == limit {
+			break
+		}
+		if d.Status == domain.EmailQueued && !d.NextAttemptAt.After(now) {
+			d.Attempts++
+			d.NextAttemptAt = now.Add(lease)
+			copied := *d
+			claimed = append(claimed, &copied)
+		}
+	}
+	return claimed, nil
+}
+
+func (s *memoryStorer) UpdateEmailDelivery(ctx context.Context, d *domain.EmailDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *d
+	s.deliveries[d.Id-1] = &copied
+	return nil
+}
+
+func (s *memoryStorer) CountSentEmails(ctx context.Context, recipient string, since time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, d := range s.deliveries {
+		if d.Recipient == recipient && d.Status == domain.EmailSent && !d.SentAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// DeleteEmailDeliveries records the time it prunes before, keeping the
+// emails so that their ids still index deliveries.
+func (s *memoryStorer) DeleteEmailDeliveries(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prunes = append(s.prunes, before)
+	return nil
+}
+
+// due makes every queued email due again, as if its backoff had passed.
+func (s *memoryStorer) due() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, d := range s.deliveries {
+		d.NextAttemptAt = time.Time{}
+	}
+}
+
+// userStorer finds the users in users by email.
+type userStorer struct {
+	domain.UserStorer
+	users []*domain.User
+}
+
+func (s *userStorer) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, user := range s.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, errs.ErrSelectNotMatch
+}
+
+// fakeMailer records the emails it delivers and fails while err is set.
+type fakeMailer struct {
+	err       error
+	delivered []string
+}
+
+func (m *fakeMailer) Deliver(ctx context.Context, to string, email mailer.Email) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.delivered = append(m.delivered, to+": "+email.Subject)
+	return nil
+}
+
+func TestSenderDelivers(t *testing.T) {
+	storer := &memoryStorer{}
+	backend := &fakeMailer{}
+	queue := NewQueue(storer, &userStorer{})
+	sender := NewSender(storer, backend)
+	ctx := context.Background()
+
+	msg := mailer.Verification{Username: "fern", Code: "123456"}
+	assert.NoError(t, mailer.Send(ctx, queue, "fern@example.com", "en", msg))
+	assert.Empty(t, backend.delivered, "should only queue the email")
+
+	sender.RunOnce(ctx)
+	assert.Equal(t, []string{"fern@example.com: Confirm your email"}, backend.delivered)
+
+	d, err := storer.GetEmailDeliveryByExternalId(ctx, "email-1")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.EmailSent, d.Status)
+	assert.Equal(t, "verification", d.Template)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Empty(t, d.HTML)
+
+	sender.RunOnce(ctx)
+	assert.Len(t, backend.delivered, 1, "should send every email once")
+}
+
+func TestSenderRetriesThenDeadLetters(t *testing.T) {
+	storer := &memoryStorer{}
+	backend := &fakeMailer{err: errors.New("provider unavailable")}
+	sender := NewSender(storer, backend)
+	ctx := context.Background()
+
+	email := mailer.Email{Template: "verification", Subject: "Confirm your email", HTML: "<p>123456</p>", Text: "123456\n"}
+	assert.NoError(t, NewQueue(storer, &userStorer{}).Deliver(ctx, "fern@example.com", email))
+
+	sender.RunOnce(ctx)
+	d, _ := storer.GetEmailDeliveryByExternalId(ctx, "email-1")
+	assert.Equal(t, domain.EmailQueued, d.Status, "should retry a failed email")
+	assert.Equal(t, "provider unavailable", d.Error)
+	assert.True(t, d.NextAttemptAt.After(time.Now()), "should back off")
+
+	sender.RunOnce(ctx)
+	d, _ = storer.GetEmailDeliveryByExternalId(ctx, "email-1")
+	assert.Equal(t, 1, d.Attempts, "should wait for the backoff")
+
+	for i := 1; i < domain.EmailMaxAttempts; i++ {
+		storer.due()
+		sender.RunOnce(ctx)
+	}
+	d, _ = storer.GetEmailDeliveryByExternalId(ctx, "email-1")
+	assert.Equal(t, domain.EmailDead, d.Status, "should give up after the last attempt")
+	assert.Equal(t, domain.EmailMaxAttempts, d.Attempts)
+	assert.Empty(t, d.HTML, "should drop the bodies of a dead email")
+	assert.Empty(t, d.Text)
+
+	backend.err = nil
+	storer.due()
+	sender.RunOnce(ctx)
+	assert.Empty(t, backend.delivered, "should not send dead emails")
+}
+
+func TestSenderRateLimitsRecipients(t *testing.T) {
+	storer := &memoryStorer{}
+	backend := &fakeMailer{}
+	queue := NewQueue(storer, &userStorer{})
+	sender := NewSender(storer, backend)
+	ctx := context.Background()
+
+	for i := 0; i < domain.EmailRateLimit+1; i++ {
+		assert.NoError(t, queue.Deliver(ctx, "fern@example.com", mailer.Email{Subject: fmt.Sprint("email ", i)}))
+	}
+	assert.NoError(t, queue.Deliver(ctx, "ivy@example.com", mailer.Email{Subject: "email"}))
+
+	sender.RunOnce(ctx)
+	assert.Len(t, backend.delivered, domain.EmailRateLimit+1)
+	assert.Contains(t, backend.delivered, "ivy@example.com: email", "should not hold up other recipients")
+
+	postponed, _ := storer.GetEmailDeliveryByExternalId(ctx, fmt.Sprint("email-", domain.EmailRateLimit+1))
+	assert.Equal(t, domain.EmailQueued, postponed.Status)
+	assert.Zero(t, postponed.Attempts, "should not count a postponed attempt")
+	assert.True(t, postponed.NextAttemptAt.After(time.Now()))
+}
+
+func TestSenderDropsExpiredEmails(t *testing.T) {
+	storer := &memoryStorer{}
+	backend := &fakeMailer{}
+	queue := NewQueue(storer, &userStorer{})
+	sender := NewSender(storer, backend)
+	ctx := context.Background()
+
+	for i := 0; i < domain.EmailRateLimit; i++ {
+		assert.NoError(t, queue.Deliver(ctx, "fern@example.com", mailer.Email{Subject: fmt.Sprint("email ", i)}))
+	}
+	sender.RunOnce(ctx)
+
+	expiresAt := time.Now().UTC().Add(time.Minute)
+	msg := mailer.Verification{Username: "fern", Code: "123456", ExpiresAt: expiresAt}
+	assert.NoError(t, mailer.Send(ctx, queue, "fern@example.com", "en", msg))
+
+	sender.RunOnce(ctx)
+	id := fmt.Sprint("email-", domain.EmailRateLimit+1)
+	d, _ := storer.GetEmailDeliveryByExternalId(ctx, id)
+	assert.Equal(t, domain.EmailQueued, d.Status, "should postpone the code while the recipient is rate limited")
+	assert.Equal(t, expiresAt, *d.ExpiresAt)
+
+	expired := time.Now().UTC().Add(-time.Second)
+	storer.mu.Lock()
+	storer.deliveries[d.Id-1].ExpiresAt = &expired
+	storer.mu.Unlock()
+	storer.due()
+	sender.RunOnce(ctx)
+
+	d, _ = storer.GetEmailDeliveryByExternalId(ctx, id)
+	assert.Equal(t, domain.EmailDead, d.Status, "should give up on a code that expired meanwhile")
+	assert.Contains(t, d.Error, "expired")
+	assert.Empty(t, d.HTML)
+	assert.Empty(t, d.Text)
+	assert.Len(t, backend.delivered, domain.EmailRateLimit, "should not send the expired code")
+}
+
+func TestSenderPrunesOldEmails(t *testing.T) {
+	storer := &memoryStorer{}
+	sender := NewSender(storer, &fakeMailer{})
+	ctx := context.Background()
+
+	sender.RunOnce(ctx)
+	sender.RunOnce(ctx)
+
+	assert.Len(t, storer.prunes, 1, "should prune once every interval")
+	assert.WithinDuration(t, time.Now().Add(-emailRetention), storer.prunes[0], time.Minute)
+}
+
+func TestQueueDefersNotificationsThroughQuietHours(t *testing.T) {
+	now := time.Now().UTC()
+	clock := func(t time.Time) string { return t.Format("15:04") }
+	quiet := &domain.QuietHours{Start: clock(now.Add(-time.Hour)), End: clock(now.Add(2 * time.Hour))}
+
+	users := &userStorer{users: []*domain.User{
+		{Email: "fern@example.com", Timezone: "UTC", QuietHours: quiet},
+		{Email: "ivy@example.com", Timezone: "UTC"},
+	}}
+
+	cases := []struct {
+		purpose  string
+		to       string
+		template string
+		deferred bool
+	}{
+		{"should defer a notification until the quiet hours end", "fern@example.com", "care_reminder", true},
+		{"should not defer the emails the user waits for to sign in", "fern@example.com", "verification", false},
+		{"should not defer a notification to a user without quiet hours", "ivy@example.com", "digest", false},
+		{"should not defer a notification to an unknown address", "moss@example.com", "digest", false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			storer := &memoryStorer{}
+			queue := NewQueue(storer, users)
+			ctx := context.Background()
+
+			assert.NoError(t, queue.Deliver(ctx, tt.to, mailer.Email{Template: tt.template, Subject: "email"}))
+
+			d, err := storer.GetEmailDeliveryByExternalId(ctx, "email-1")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.deferred, d.NextAttemptAt.After(now.Add(time.Hour)))
+		})
+	}
+}
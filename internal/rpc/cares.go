@@ -0,0 +1,155 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/live"
+	"github.com/mathehluiz/plant-care-tracker/pkg/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type careService struct {
+	pb.UnimplementedCareServiceServer
+
+	pStorer domain.PlantStorer
+	cStorer domain.CareStorer
+	policy  *authz.Policy
+	hub     *live.Hub
+}
+
+func (s *careService) ListCares(ctx context.Context, req *pb.ListCaresRequest) (*pb.ListCaresResponse, error) {
+	caller := callerFrom(ctx)
+
+	var cares []*domain.Care
+	var err error
+	if plantId := req.GetPlantId(); plantId != 0 {
+		plant, err := s.pStorer.GetPlantByID(ctx, plantId)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		if err := s.policy.Authorize(ctx, caller.subject, domain.AuditEntityPlant, plant.Id, plant.UserId); err != nil {
+			return nil, toStatus(err)
+		}
+
+		cares, err = s.cStorer.GetPlantCares(ctx, plant.Id)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+	} else {
+		cares, err = s.cStorer.GetCaresByUserID(ctx, caller.user.Id)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+	}
+
+	resp := &pb.ListCaresResponse{Cares: make([]*pb.Care, 0, len(cares))}
+	for _, care := range cares {
+		resp.Cares = append(resp.Cares, toCare(care))
+	}
+	return resp, nil
+}
+
+func (s *careService) GetCare(ctx context.Context, req *pb.GetCareRequest) (*pb.Care, error) {
+	caller := callerFrom(ctx)
+
+	care, err := s.cStorer.GetCareByID(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	if err := s.policy.Authorize(ctx, caller.subject, domain.AuditEntityCare, care.Id, care.UserId); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toCare(care), nil
+}
+
+// WatchDueCares streams the cares of the caller that are due, then the
+// care.due events of the live hub. A client that falls too far behind is
+// disconnected with Unavailable and should call again.
+func (s *careService) WatchDueCares(req *pb.WatchDueCaresRequest, stream grpc.ServerStreamingServer[pb.Care]) error {
+	ctx := stream.Context()
+	caller := callerFrom(ctx)
+
+	// Subscribe before listing so that nothing coming due in between is
+	// missed; cares seen in both are only sent once.
+	messages, unsubscribe := s.hub.Subscribe(caller.user.Id)
+	defer unsubscribe()
+
+	due, err := s.dueCares(ctx, caller.user)
+	if err != nil {
+		return toStatus(err)
+	}
+
+	sent := make(map[int64]time.Time, len(due))
+	send := func(care *domain.Care) error {
+		if next, ok := sent[care.Id]; ok && next.Equal(care.NextCare) {
+			return nil
+		}
+		sent[care.Id] = care.NextCare
+		return stream.Send(toCare(care))
+	}
+
+	for _, care := range due {
+		if err := send(care); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-messages:
+			if !ok {
+				return status.Error(codes.Unavailable, "the stream fell behind, call again to resume")
+			}
+			if message.Type != domain.EventCareDue {
+				continue
+			}
+
+			var payload domain.CareEventPayload
+			if err := json.Unmarshal(message.Data, &payload); err != nil || payload.Care == nil {
+				continue
+			}
+			if err := send(payload.Care); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// dueCares returns the cares of the active plants of user that are due now.
+func (s *careService) dueCares(ctx context.Context, user *domain.User) ([]*domain.Care, error) {
+	plants, err := s.pStorer.GetPlantsByUserID(ctx, user.Id, domain.PlantStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make(map[int64]bool, len(plants))
+	for _, plant := range plants {
+		active[plant.Id] = true
+	}
+
+	cares, err := s.cStorer.GetCaresByUserID(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	loc := user.Location()
+
+	var due []*domain.Care
+	for _, care := range cares {
+		if active[care.PlantId] && care.IsDue(now, loc) {
+			due = append(due, care)
+		}
+	}
+	return due, nil
+}
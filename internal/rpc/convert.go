@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/pkg/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func toUser(user *domain.User) *pb.User {
+	return &pb.User{
+		Id:       user.ExternalId,
+		Username: user.Username,
+		Email:    user.Email,
+		Roles:    user.Roles,
+		Active:   user.Active,
+		Verified: user.Verified,
+		Timezone: user.Timezone,
+		Locale:   user.Locale,
+	}
+}
+
+func toPlant(plant *domain.Plant) *pb.Plant {
+	return &pb.Plant{
+		Id:              plant.Id,
+		Name:            plant.Name,
+		Species:         plant.Species,
+		Location:        plant.Location,
+		AcquisitionDate: timestamppb.New(plant.AcquisitionDate),
+		CareFrequency:   int32(plant.CareFrequency),
+		Status:          plant.Status,
+		StatusReason:    plant.StatusReason,
+		StatusChangedAt: timestamp(plant.StatusChangedAt),
+		Version:         int32(plant.Version),
+		CreatedAt:       timestamppb.New(plant.CreatedAt),
+		UpdatedAt:       timestamppb.New(plant.UpdatedAt),
+	}
+}
+
+func toCare(care *domain.Care) *pb.Care {
+	m := &pb.Care{
+		Id:          care.Id,
+		PlantId:     care.PlantId,
+		Name:        care.Name,
+		Notes:       care.Notes,
+		Interval:    int32(care.Interval),
+		LastCare:    timestamppb.New(care.LastCare),
+		NextCare:    timestamppb.New(care.NextCare),
+		SnoozedFrom: timestamp(care.SnoozedFrom),
+		Version:     int32(care.Version),
+		CreatedAt:   timestamppb.New(care.CreatedAt),
+		UpdatedAt:   timestamppb.New(care.UpdatedAt),
+	}
+
+	if r := care.Recurrence; r != nil {
+		m.Recurrence = &pb.Recurrence{Rrule: r.RRule, Start: timestamppb.New(r.Start), Timezone: r.Timezone}
+		for _, exdate := range r.ExDates {
+			m.Recurrence.Exdates = append(m.Recurrence.Exdates, timestamppb.New(exdate))
+		}
+	}
+
+	for _, item := range care.Checklist {
+		m.Checklist = append(m.Checklist, &pb.ChecklistItem{
+			Id:        item.Id,
+			Title:     item.Title,
+			Checked:   item.Checked,
+			CheckedAt: timestamp(item.CheckedAt),
+		})
+	}
+
+	return m
+}
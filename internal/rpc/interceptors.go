@@ -0,0 +1,144 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/audit"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/jwt"
+	l "github.com/mathehluiz/plant-care-tracker/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type callerKey struct{}
+
+// caller is the authenticated user of an RPC.
+type caller struct {
+	user    *domain.User
+	subject authz.Subject
+}
+
+func callerFrom(ctx context.Context) *caller {
+	return ctx.Value(callerKey{}).(*caller)
+}
+
+// authenticate reads the bearer token of the "authorization" metadata, as
+// sent in the Authorization header of the REST API, and loads its user. The
+// user is also set as the audit actor, as for REST requests, so that changes
+// and admin accesses are recorded with who made them.
+func authenticate(ctx context.Context, uStorer domain.UserStorer) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var token string
+	if values := md.Get("authorization"); len(values) > 0 {
+		token = strings.TrimPrefix(values[0], "Bearer ")
+	}
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "no authorization token provided")
+	}
+
+	id, _, roles, _, err := jwt.ValidateToken(token)
+	if err != nil {
+		if errors.Is(err, jwt.ErrExpiredToken) {
+			return nil, status.Error(codes.Unauthenticated, "token has expired")
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid token format")
+	}
+
+	user, err := domain.GetUserBySubject(ctx, uStorer, id)
+	if errors.Is(err, errs.ErrSelectNotMatch) {
+		return nil, status.Error(codes.Unauthenticated, "user no longer exists")
+	}
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	actor := &domain.AuditActor{Type: domain.AuditActorUser, Id: user.ExternalId}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		actor.IP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(actor.IP); err == nil {
+			actor.IP = host
+		}
+	}
+	if values := md.Get("user-agent"); len(values) > 0 {
+		actor.UserAgent = values[0]
+	}
+	ctx = audit.WithActor(ctx, actor)
+
+	return context.WithValue(ctx, callerKey{}, &caller{
+		user:    user,
+		subject: authz.Subject{UserId: user.Id, Roles: roles},
+	}), nil
+}
+
+func authUnary(uStorer domain.UserStorer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, uStorer)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authStream(uStorer domain.UserStorer) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), uStorer)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func logUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logCall(info.FullMethod, start, err)
+	return resp, err
+}
+
+func logStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logCall(info.FullMethod, start, err)
+	return err
+}
+
+func logCall(method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("code", code.String()),
+		zap.Duration("duration", time.Since(start)),
+	}
+
+	switch code {
+	case codes.OK, codes.Canceled:
+		l.Logger.Info("rpc finished", fields...)
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+		l.Logger.Error("rpc failed", append(fields, zap.Error(err))...)
+	default:
+		l.Logger.Warn("rpc failed", append(fields, zap.Error(err))...)
+	}
+}
@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type plantService struct {
+	pb.UnimplementedPlantServiceServer
+
+	pStorer domain.PlantStorer
+	policy  *authz.Policy
+}
+
+func (s *plantService) ListPlants(ctx context.Context, req *pb.ListPlantsRequest) (*pb.ListPlantsResponse, error) {
+	caller := callerFrom(ctx)
+
+	statuses := req.GetStatuses()
+	if len(statuses) == 0 {
+		statuses = []string{domain.PlantStatusActive}
+	}
+	for _, plantStatus := range statuses {
+		if !domain.IsValidPlantStatus(plantStatus) {
+			return nil, status.Error(codes.InvalidArgument, errs.ErrInvalidPlantStatus.Error())
+		}
+	}
+
+	plants, err := s.pStorer.GetPlantsByUserID(ctx, caller.user.Id, statuses...)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.ListPlantsResponse{Plants: make([]*pb.Plant, 0, len(plants))}
+	for _, plant := range plants {
+		resp.Plants = append(resp.Plants, toPlant(plant))
+	}
+	return resp, nil
+}
+
+func (s *plantService) GetPlant(ctx context.Context, req *pb.GetPlantRequest) (*pb.Plant, error) {
+	plant, err := s.getPlant(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toPlant(plant), nil
+}
+
+func (s *plantService) CreatePlant(ctx context.Context, req *pb.CreatePlantRequest) (*pb.Plant, error) {
+	caller := callerFrom(ctx)
+
+	if req.GetAcquisitionDate() == nil {
+		return nil, status.Error(codes.InvalidArgument, errs.ErrInvalidBody.Error())
+	}
+
+	plant, err := domain.NewPlant(req.GetName(), req.GetSpecies(), req.GetLocation(), req.GetAcquisitionDate().AsTime(), int(req.GetCareFrequency()), caller.user.Id)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	id, err := s.pStorer.CreatePlant(ctx, plant)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	plant.Id = id
+
+	return toPlant(plant), nil
+}
+
+func (s *plantService) DeletePlant(ctx context.Context, req *pb.DeletePlantRequest) (*emptypb.Empty, error) {
+	plant, err := s.getPlant(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.pStorer.DeletePlant(ctx, plant.Id, int(req.GetVersion())); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// getPlant loads a plant the caller may access.
+func (s *plantService) getPlant(ctx context.Context, id int64) (*domain.Plant, error) {
+	caller := callerFrom(ctx)
+
+	plant, err := s.pStorer.GetPlantByID(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	if err := s.policy.Authorize(ctx, caller.subject, domain.AuditEntityPlant, plant.Id, plant.UserId); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return plant, nil
+}
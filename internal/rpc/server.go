@@ -0,0 +1,98 @@
+// Package rpc serves users, plants and cares over gRPC, for the backend
+// services that consume plant data. The services are defined in
+// proto/plantcare/v1 and sit on the same storers as the REST API.
+package rpc
+
+import (
+	"errors"
+	"net"
+
+	"github.com/google/uuid"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/internal/live"
+	l "github.com/mathehluiz/plant-care-tracker/pkg/logger"
+	"github.com/mathehluiz/plant-care-tracker/pkg/pb"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Server struct {
+	server *grpc.Server
+}
+
+func NewServer(uStorer domain.UserStorer, pStorer domain.PlantStorer, cStorer domain.CareStorer, policy *authz.Policy, hub *live.Hub) *Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logUnary, authUnary(uStorer)),
+		grpc.ChainStreamInterceptor(logStream, authStream(uStorer)),
+	)
+
+	pb.RegisterUserServiceServer(server, &userService{uStorer: uStorer, policy: policy})
+	pb.RegisterPlantServiceServer(server, &plantService{pStorer: pStorer, policy: policy})
+	pb.RegisterCareServiceServer(server, &careService{pStorer: pStorer, cStorer: cStorer, policy: policy, hub: hub})
+
+	return &Server{server: server}
+}
+
+// Serve accepts connections on addr until Stop is called.
+func (s *Server) Serve(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.server.Serve(lis)
+}
+
+// Stop waits for the pending RPCs to finish and closes the listener.
+func (s *Server) Stop() {
+	s.server.GracefulStop()
+}
+
+// toStatus maps the errors of the storers and of the policy to a status.
+// Entities the user may not access get the same NotFound as missing ones.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrNotFound), errors.Is(err, errs.ErrSelectNotMatch), errors.Is(err, errs.ErrNoRowsAffected):
+		return status.Error(codes.NotFound, errs.ErrNotFound.Error())
+	case errors.Is(err, errs.ErrVersionConflict):
+		return status.Error(codes.FailedPrecondition, errs.ErrPreconditionFailed.Error())
+	default:
+		return internalError(err)
+	}
+}
+
+// internalError logs err under a new correlation id and returns a status
+// that only carries the id, so that the details of the failure stay out of
+// the response but a failure reported by a client can be found in the logs.
+func internalError(err error) error {
+	correlationId := uuid.NewString()
+	l.Logger.Error("rpc failed", zap.Error(err), zap.String("correlationId", correlationId))
+	return status.Errorf(codes.Internal, "internal server error, correlation id %s", correlationId)
+}
+
+// invalidArgument rejects a request, describing every field of errs.FieldErrors
+// as a BadRequest detail so that clients can tell which inputs are wrong.
+func invalidArgument(err error) error {
+	st := status.New(codes.InvalidArgument, err.Error())
+
+	var fields errs.FieldErrors
+	if !errors.As(err, &fields) {
+		return st.Err()
+	}
+
+	details := &errdetails.BadRequest{}
+	for _, f := range fields {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Message,
+		})
+	}
+	if withDetails, err := st.WithDetails(details); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
@@ -0,0 +1,303 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/audit"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/internal/cache"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/internal/live"
+	"github.com/mathehluiz/plant-care-tracker/pkg/jwt"
+	"github.com/mathehluiz/plant-care-tracker/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type userStorer struct {
+	domain.UserStorer
+	users []*domain.User
+}
+
+func (s *userStorer) GetUserByID(ctx context.Context, id int64) (*domain.User, error) {
+	for _, user := range s.users {
+		if user.Id == id {
+			return user, nil
+		}
+	}
+	return nil, errs.ErrSelectNotMatch
+}
+
+func (s *userStorer) GetUserByExternalId(ctx context.Context, id string) (*domain.User, error) {
+	for _, user := range s.users {
+		if user.ExternalId == id {
+			return user, nil
+		}
+	}
+	return nil, errs.ErrSelectNotMatch
+}
+
+type plantStorer struct {
+	domain.PlantStorer
+	plants []*domain.Plant
+}
+
+func (s *plantStorer) GetPlantByID(ctx context.Context, id int64) (*domain.Plant, error) {
+	for _, plant := range s.plants {
+		if plant.Id == id {
+			return plant, nil
+		}
+	}
+	return nil, errs.ErrSelectNotMatch
+}
+
+func (s *plantStorer) GetPlantsByUserID(ctx context.Context, userId int64, statuses ...string) ([]*domain.Plant, error) {
+	var plants []*domain.Plant
+	for _, plant := range s.plants {
+		if plant.UserId == userId {
+			plants = append(plants, plant)
+		}
+	}
+	return plants, nil
+}
+
+type careStorer struct {
+	domain.CareStorer
+	cares []*domain.Care
+}
+
+func (s *careStorer) GetCaresByUserID(ctx context.Context, userId int64) ([]*domain.Care, error) {
+	var cares []*domain.Care
+	for _, care := range s.cares {
+		if care.UserId == userId {
+			cares = append(cares, care)
+		}
+	}
+	return cares, nil
+}
+
+type auditLog struct {
+	domain.AuditStorer
+}
+
+type testServer struct {
+	conn  *grpc.ClientConn
+	hub   *live.Hub
+	redis *redis.Client
+}
+
+func newTestServer(t *testing.T) *testServer {
+	now := time.Now()
+
+	uStorer := &userStorer{users: []*domain.User{
+		{Id: 1, ExternalId: "u-1", Username: "ana", Roles: []string{"user"}},
+		{Id: 2, ExternalId: "u-2", Username: "bia", Roles: []string{"user"}},
+	}}
+	pStorer := &plantStorer{plants: []*domain.Plant{
+		{Id: 1, Name: "Fern", UserId: 1, Status: domain.PlantStatusActive},
+		{Id: 2, Name: "Orchid", UserId: 2, Status: domain.PlantStatusActive},
+	}}
+	cStorer := &careStorer{cares: []*domain.Care{
+		{Id: 1, PlantId: 1, UserId: 1, Name: "Water", NextCare: now.Add(-time.Hour)},
+		{Id: 2, PlantId: 1, UserId: 1, Name: "Feed", NextCare: now.Add(72 * time.Hour)},
+	}}
+
+	mock, err := cache.StartMock()
+	assert.NoError(t, err)
+	t.Cleanup(func() { mock.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	hub := live.NewHub(mock.Client())
+	go hub.Start(ctx)
+
+	server := NewServer(uStorer, pStorer, cStorer, authz.NewPolicy(&auditLog{}), hub)
+
+	lis := bufconn.Listen(1 << 20)
+	go server.server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return &testServer{conn: conn, hub: hub, redis: mock.Client()}
+}
+
+func withToken(t *testing.T, ctx context.Context, id any) context.Context {
+	token, err := jwt.GenerateToken(id, []string{"user"}, true, "en")
+	assert.NoError(t, err)
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func TestAuthentication(t *testing.T) {
+	s := newTestServer(t)
+	client := pb.NewUserServiceClient(s.conn)
+
+	cases := []struct {
+		purpose  string
+		ctx      context.Context
+		wantCode codes.Code
+		wantUser string
+	}{
+		{
+			"should refuse calls without a token",
+			context.Background(),
+			codes.Unauthenticated,
+			"",
+		},
+		{
+			"should refuse invalid tokens",
+			metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer nope"),
+			codes.Unauthenticated,
+			"",
+		},
+		{
+			"should authenticate tokens issued at login",
+			withToken(t, context.Background(), 1),
+			codes.OK,
+			"u-1",
+		},
+		{
+			"should authenticate tokens carrying the external id",
+			withToken(t, context.Background(), "u-2"),
+			codes.OK,
+			"u-2",
+		},
+		{
+			"should refuse tokens of users that no longer exist",
+			withToken(t, context.Background(), 3),
+			codes.Unauthenticated,
+			"",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			user, err := client.GetMe(tt.ctx, &pb.GetMeRequest{})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantUser != "" {
+				assert.Equal(t, tt.wantUser, user.GetId())
+			}
+		})
+	}
+}
+
+func TestGetPlant(t *testing.T) {
+	s := newTestServer(t)
+	client := pb.NewPlantServiceClient(s.conn)
+	ctx := withToken(t, context.Background(), 1)
+
+	cases := []struct {
+		purpose  string
+		id       int64
+		wantCode codes.Code
+	}{
+		{"should return a plant of the user", 1, codes.OK},
+		{"should hide the plants of other users", 2, codes.NotFound},
+		{"should not find missing plants", 3, codes.NotFound},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			plant, err := client.GetPlant(ctx, &pb.GetPlantRequest{Id: tt.id})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, tt.id, plant.GetId())
+			}
+		})
+	}
+}
+
+func TestWatchDueCares(t *testing.T) {
+	s := newTestServer(t)
+	client := pb.NewCareServiceClient(s.conn)
+
+	ctx, cancel := context.WithTimeout(withToken(t, context.Background(), 1), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchDueCares(ctx, &pb.WatchDueCaresRequest{})
+	assert.NoError(t, err)
+
+	care, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), care.GetId(), "the care due now should be sent first")
+
+	// Wait for the hub to listen before publishing.
+	assert.Eventually(t, func() bool {
+		n, err := s.redis.PubSubNumPat(ctx).Result()
+		return err == nil && n == 1
+	}, time.Second, 10*time.Millisecond)
+
+	event, err := domain.NewCareDueEvent(&domain.Care{Id: 2, PlantId: 1, UserId: 1, Name: "Feed", NextCare: time.Now()})
+	assert.NoError(t, err)
+	assert.NoError(t, s.hub.Handle(ctx, event))
+
+	care, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), care.GetId(), "cares coming due should be pushed")
+}
+
+func TestAuthenticateSetsAuditActor(t *testing.T) {
+	uStorer := &userStorer{users: []*domain.User{{Id: 1, ExternalId: "u-1"}}}
+
+	cases := []struct {
+		purpose string
+		subject string
+	}{
+		{"should record the external id of an external id token", "u-1"},
+		{"should record the external id of a login token", "1"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			token, err := jwt.GenerateToken(tt.subject, []string{"user"}, true, "en")
+			assert.NoError(t, err)
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token, "user-agent", "plant-sync/1.0"))
+			ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 52114}})
+
+			ctx, err = authenticate(ctx, uStorer)
+			assert.NoError(t, err)
+			assert.Equal(t, &domain.AuditActor{Type: domain.AuditActorUser, Id: "u-1", IP: "203.0.113.7", UserAgent: "plant-sync/1.0"}, audit.ActorFromContext(ctx))
+		})
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	cases := []struct {
+		purpose     string
+		err         error
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{"should report missing entities as not found", errs.ErrSelectNotMatch, codes.NotFound, errs.ErrNotFound.Error()},
+		{"should report version conflicts as a failed precondition", errs.ErrVersionConflict, codes.FailedPrecondition, errs.ErrPreconditionFailed.Error()},
+		{"should not echo internal errors", errors.New("pq: connection refused"), codes.Internal, "internal server error, correlation id "},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			st := status.Convert(toStatus(tt.err))
+			assert.Equal(t, tt.wantCode, st.Code())
+			assert.True(t, strings.HasPrefix(st.Message(), tt.wantMessage), "got %q", st.Message())
+			assert.NotContains(t, st.Message(), "pq:")
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
+	"github.com/mathehluiz/plant-care-tracker/pkg/pb"
+)
+
+type userService struct {
+	pb.UnimplementedUserServiceServer
+
+	uStorer domain.UserStorer
+	policy  *authz.Policy
+}
+
+func (s *userService) GetMe(ctx context.Context, req *pb.GetMeRequest) (*pb.User, error) {
+	return toUser(callerFrom(ctx).user), nil
+}
+
+func (s *userService) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	caller := callerFrom(ctx)
+
+	user, err := s.uStorer.GetUserByExternalId(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	if err := s.policy.Authorize(ctx, caller.subject, domain.AuditEntityUser, user.Id, user.Id); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toUser(user), nil
+}
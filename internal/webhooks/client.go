@@ -0,0 +1,92 @@
+package webhooks
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+var (
+	errAddressNotAllowed = errors.New("webhook address is not a public address")
+	errInsecureURL       = errors.New("webhook url must use https")
+)
+
+// blockedNetworks are the non-public ranges that the net.IP predicates do not
+// cover, such as the shared address space some clouds serve metadata from.
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"100.64.0.0/10",
+	"192.0.0.0/24",
+	"198.18.0.0/15",
+	"240.0.0.0/4",
+)
+
+// newClient returns the client deliveries are sent with. Unless
+// allowPrivate, it only connects to public addresses, checked once the host
+// is resolved so that a name pointing inside the network is refused too.
+// Redirects are never followed, as the target is only checked on the first
+// request; a redirect is reported as an unexpected status.
+func newClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !allowPrivate {
+		dialer.Control = rejectPrivateAddress
+	}
+
+	return &http.Client{
+		Timeout: 15 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// rejectPrivateAddress is a net.Dialer Control hook, called with the
+// resolved address of every connection.
+func rejectPrivateAddress(network, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !isPublic(ip) {
+		return errAddressNotAllowed
+	}
+	return nil
+}
+
+// isPublic reports whether ip may be reached from the internet, leaving out
+// loopback, private (RFC 1918 and unique local), link-local, which includes
+// the 169.254.169.254 metadata endpoint, and the other reserved ranges.
+func isPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
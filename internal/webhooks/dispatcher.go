@@ -0,0 +1,142 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	l "github.com/mathehluiz/plant-care-tracker/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const batchSize = 20
+
+// Dispatcher sends queued webhook deliveries. Outside of APP_ENV=development
+// it only posts to https URLs of public addresses, so that webhooks cannot be
+// used to reach the services of the private network; in development, local
+// receivers over http are allowed too.
+type Dispatcher struct {
+	wStorer      domain.WebhookStorer
+	client       *http.Client
+	requireHTTPS bool
+	interval     time.Duration
+}
+
+func NewDispatcher(wStorer domain.WebhookStorer) *Dispatcher {
+	development := os.Getenv("APP_ENV") == "development"
+
+	return &Dispatcher{
+		wStorer:      wStorer,
+		client:       newClient(development),
+		requireHTTPS: !development,
+		interval:     10 * time.Second,
+	}
+}
+
+// Start polls for deliveries until ctx is cancelled. It is
+// meant to be run in its own goroutine.
+func (d *Dispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) RunOnce(ctx context.Context) {
+	now := time.Now().UTC()
+
+	deliveries, err := d.wStorer.ClaimWebhookDeliveries(ctx, now, domain.DeliveryLease, batchSize)
+	if err != nil {
+		l.Logger.Error("cannot claim webhook deliveries", zap.Error(err))
+		return
+	}
+
+	webhooks := make(map[int64]*domain.Webhook)
+	for _, delivery := range deliveries {
+		webhook, ok := webhooks[delivery.WebhookId]
+		if !ok {
+			webhook, err = d.wStorer.GetWebhookByID(ctx, delivery.WebhookId)
+			if err != nil {
+				// A deleted webhook takes its deliveries with it.
+				if !errors.Is(err, errs.ErrSelectNotMatch) {
+					l.Logger.Error("cannot get webhook", zap.Error(err), zap.Int64("webhook", delivery.WebhookId))
+				}
+				continue
+			}
+			webhooks[delivery.WebhookId] = webhook
+		}
+
+		d.deliver(ctx, webhook, delivery)
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) {
+	if !webhook.Active {
+		delivery.Abandon(errs.ErrWebhookInactive, time.Now().UTC())
+	} else if status, err := d.send(ctx, webhook, delivery); errors.Is(err, errAddressNotAllowed) || errors.Is(err, errInsecureURL) {
+		// Retrying would not change the target.
+		delivery.Abandon(err, time.Now().UTC())
+	} else if err != nil {
+		delivery.Fail(status, err, time.Now().UTC())
+	} else {
+		delivery.Succeed(status, time.Now().UTC())
+	}
+
+	if err := d.wStorer.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		l.Logger.Error("cannot update webhook delivery", zap.Error(err), zap.Int64("delivery", delivery.Id))
+	}
+}
+
+// send posts the payload to the webhook. Any 2xx response is a success; the
+// response body is ignored.
+func (d *Dispatcher) send(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) (int, error) {
+	target, err := url.Parse(webhook.URL)
+	if err != nil {
+		return 0, err
+	}
+	if d.requireHTTPS && target.Scheme != "https" {
+		return 0, errInsecureURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "plant-care-tracker-webhooks")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatInt(delivery.Id, 10))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set("X-Webhook-Signature", domain.SignWebhookPayload(webhook.Secret, now, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
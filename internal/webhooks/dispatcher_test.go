@@ -0,0 +1,137 @@
+package webhooks
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+type deliveryStorer struct {
+	domain.WebhookStorer
+	updated *domain.WebhookDelivery
+}
+
+func (s *deliveryStorer) UpdateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	s.updated = delivery
+	return nil
+}
+
+func TestIsPublic(t *testing.T) {
+	cases := []struct {
+		purpose string
+		ip      string
+		want    bool
+	}{
+		{"should allow a public IPv4 address", "93.184.216.34", true},
+		{"should allow a public IPv6 address", "2606:2800:220:1:248:1893:25c8:1946", true},
+		{"should refuse loopback", "127.0.0.1", false},
+		{"should refuse IPv6 loopback", "::1", false},
+		{"should refuse RFC 1918 addresses", "10.1.2.3", false},
+		{"should refuse RFC 1918 addresses", "172.16.0.1", false},
+		{"should refuse RFC 1918 addresses", "192.168.1.1", false},
+		{"should refuse the metadata endpoint", "169.254.169.254", false},
+		{"should refuse the IPv6 metadata endpoint", "fd00:ec2::254", false},
+		{"should refuse the shared address space", "100.100.100.200", false},
+		{"should refuse the unspecified address", "0.0.0.0", false},
+		{"should refuse IPv4-mapped private addresses", "::ffff:10.0.0.1", false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			assert.Equal(t, tt.want, isPublic(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+func TestDispatcherDeliver(t *testing.T) {
+	var redirected atomic.Int32
+	elsewhere := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected.Add(1)
+	}))
+	defer elsewhere.Close()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, elsewhere.URL, http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	cases := []struct {
+		purpose      string
+		dispatcher   *Dispatcher
+		url          string
+		wantStatus   string
+		wantResponse int
+		wantError    string
+	}{
+		{
+			"should deliver to a receiver",
+			&Dispatcher{client: newClient(true)},
+			receiver.URL,
+			domain.WebhookDeliverySucceeded,
+			http.StatusNoContent,
+			"",
+		},
+		{
+			"should refuse to connect to a private address",
+			&Dispatcher{client: newClient(false)},
+			receiver.URL,
+			domain.WebhookDeliveryFailed,
+			0,
+			errAddressNotAllowed.Error(),
+		},
+		{
+			"should refuse a name resolving to a private address",
+			&Dispatcher{client: newClient(false)},
+			"http://localhost:" + strconv.Itoa(receiver.Listener.Addr().(*net.TCPAddr).Port),
+			domain.WebhookDeliveryFailed,
+			0,
+			errAddressNotAllowed.Error(),
+		},
+		{
+			"should refuse an http URL when https is required",
+			&Dispatcher{client: newClient(true), requireHTTPS: true},
+			receiver.URL,
+			domain.WebhookDeliveryFailed,
+			0,
+			errInsecureURL.Error(),
+		},
+		{
+			"should not follow redirects",
+			&Dispatcher{client: newClient(true)},
+			receiver.URL + "/redirect",
+			domain.WebhookDeliveryPending,
+			http.StatusFound,
+			"unexpected response status 302",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			storer := &deliveryStorer{}
+			tt.dispatcher.wStorer = storer
+
+			webhook := &domain.Webhook{Id: 1, URL: tt.url, Secret: "whsec_test", Active: true}
+			delivery := &domain.WebhookDelivery{Id: 1, WebhookId: 1, Event: domain.EventPlantCreated, Payload: []byte(`{}`), Attempts: 1}
+
+			tt.dispatcher.deliver(context.Background(), webhook, delivery)
+
+			assert.Same(t, delivery, storer.updated)
+			assert.Equal(t, tt.wantStatus, delivery.Status)
+			assert.Equal(t, tt.wantResponse, delivery.ResponseStatus)
+			assert.Contains(t, delivery.Error, tt.wantError)
+		})
+	}
+
+	assert.Zero(t, redirected.Load(), "should not reach the redirect target")
+}
@@ -0,0 +1,43 @@
+// Package webhooks queues plant and care events for the webhooks subscribed
+// to them and delivers them.
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/domain"
+)
+
+// Emitter queues events for the webhooks of their owner.
+type Emitter struct {
+	storer domain.WebhookStorer
+}
+
+func NewEmitter(storer domain.WebhookStorer) *Emitter {
+	return &Emitter{storer: storer}
+}
+
+// Handle queues a domain event from the bus for the webhooks of its owner.
+// The webhook event keeps the id of the domain event, so handling it again
+// queues nothing new.
+func (e *Emitter) Handle(ctx context.Context, event *domain.Event) error {
+	return e.emit(ctx, event.Id, event.UserId, event.Type, event.Payload, event.OccurredAt)
+}
+
+// emit queues the event with the given id, with data as its payload, for the
+// webhooks of the user that subscribed to it. Emitting an id again is a no-op
+// for the webhooks that already have it queued.
+func (e *Emitter) emit(ctx context.Context, id string, userId int64, event string, data any, now time.Time) error {
+	webhooks, err := e.storer.GetWebhooksByUserID(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := domain.NewWebhookDeliveries(webhooks, domain.WebhookEvent{Id: id, Event: event, CreatedAt: now, Data: data})
+	if err != nil || len(deliveries) == 0 {
+		return err
+	}
+
+	return e.storer.CreateWebhookDeliveries(ctx, deliveries)
+}
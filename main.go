@@ -5,9 +5,17 @@ import (
 	"os"
 
 	"github.com/mathehluiz/plant-care-tracker/api"
+	"github.com/mathehluiz/plant-care-tracker/domain"
+	"github.com/mathehluiz/plant-care-tracker/internal/authz"
 	"github.com/mathehluiz/plant-care-tracker/internal/cache"
 	"github.com/mathehluiz/plant-care-tracker/internal/db"
 	"github.com/mathehluiz/plant-care-tracker/internal/db/repositories"
+	"github.com/mathehluiz/plant-care-tracker/internal/events"
+	"github.com/mathehluiz/plant-care-tracker/internal/jobs"
+	"github.com/mathehluiz/plant-care-tracker/internal/live"
+	"github.com/mathehluiz/plant-care-tracker/internal/mailqueue"
+	"github.com/mathehluiz/plant-care-tracker/internal/rpc"
+	"github.com/mathehluiz/plant-care-tracker/internal/webhooks"
 	l "github.com/mathehluiz/plant-care-tracker/pkg/logger"
 	"github.com/mathehluiz/plant-care-tracker/pkg/mailer"
 	"go.uber.org/zap"
@@ -23,7 +31,7 @@ func main() {
 	defer client.Close()
 
 	l.Logger.Info("Connected with Database successfully 🚀")
-	
+
 	cacheClient, err := cache.Start(ctx)
 	if err != nil {
 		l.Logger.Fatal("Cannot start cache", zap.Error(err))
@@ -32,12 +40,66 @@ func main() {
 
 	defer cacheClient.Close()
 
+	auditStorage := repositories.NewAuditRepository(client)
 	userStorage := repositories.NewUserRepository(client)
+	webhookStorage := repositories.NewWebhookRepository(client)
 	plantStorage := repositories.NewPlantRepository(client)
 	careStorage := repositories.NewCareRepository(client)
+	outboxStorage := repositories.NewOutboxRepository(client)
+	jobStorage := repositories.NewJobRepository(client)
+	careTemplateStorage := repositories.NewCareTemplateRepository(client)
+	noteStorage := repositories.NewNoteRepository(client)
+	emailStorage := repositories.NewEmailRepository(client)
+
+	mail, err := mailer.FromEnv()
+	if err != nil {
+		l.Logger.Fatal("Cannot start mailer", zap.Error(err))
+	}
+	mailbox, _ := mail.(*mailer.Outbox)
+	if mailbox != nil {
+		l.Logger.Warn("Emails are kept in the outbox instead of being sent, read them at /dev/mailbox")
+	}
+
+	sender := mailqueue.NewSender(emailStorage, mail)
+	go sender.Start(ctx)
+	queue := mailqueue.NewQueue(emailStorage, userStorage)
+
+	runner := jobs.NewRunner(jobStorage, userStorage, plantStorage, careStorage, careTemplateStorage, noteStorage, cacheClient, queue)
+	go runner.Start(ctx)
+
+	dispatcher := webhooks.NewDispatcher(webhookStorage)
+	go dispatcher.Start(ctx)
+
+	bus := events.NewBus(cacheClient.Client())
+	bus.Subscribe("mailer", events.MailerHandler(cacheClient, queue), domain.EventUserRegistered, domain.EventUserVerificationRequested)
+	bus.Subscribe("cache", events.CacheInvalidationHandler(cacheClient), domain.EventUserVerified, domain.EventUserDeleted, domain.EventUserErased)
+	bus.Subscribe("stats", events.StatsHandler(cacheClient.Client()))
+	bus.Subscribe("erasure", events.ErasureHandler(cacheClient.Client()), domain.EventUserErased)
+	bus.Subscribe("webhooks", webhooks.NewEmitter(webhookStorage).Handle, domain.WebhookEvents...)
+
+	hub := live.NewHub(cacheClient.Client())
+	bus.Subscribe("live", hub.Handle, live.Types...)
+	go hub.Start(ctx)
+	go bus.Start(ctx)
+
+	relay := events.NewRelay(outboxStorage, bus)
+	go relay.Start(ctx)
+
+	dueScanner := events.NewDueScanner(careStorage, userStorage, outboxStorage)
+	go dueScanner.Start(ctx)
+
+	grpcAddr := os.Getenv("GRPC_ADDR")
+	if grpcAddr == "" {
+		grpcAddr = ":9090"
+	}
 
-	mailer.Init(os.Getenv("RESEND_API_KEY"))
+	rpcServer := rpc.NewServer(userStorage, plantStorage, careStorage, authz.NewPolicy(auditStorage), hub)
+	go func() {
+		if err := rpcServer.Serve(grpcAddr); err != nil {
+			l.Logger.Fatal("Cannot start gRPC server", zap.Error(err))
+		}
+	}()
 
-	sv := api.NewServer(userStorage, plantStorage, careStorage, cacheClient)
+	sv := api.NewServer(userStorage, plantStorage, careStorage, jobStorage, careTemplateStorage, noteStorage, auditStorage, webhookStorage, outboxStorage, emailStorage, hub, cacheClient, queue, mailbox)
 	sv.Start()
 }
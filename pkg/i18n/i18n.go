@@ -0,0 +1,108 @@
+// Package i18n holds the message catalogs of the supported locales and
+// picks the locale to answer a request or write to a user in.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Default is the locale of last resort, whose catalog every other one falls
+// back to.
+const Default = "en"
+
+// Supported lists the locales with a catalog.
+var Supported = []string{"en", "pt_BR", "es"}
+
+//go:embed locales/*.json
+var files embed.FS
+
+var catalogs = make(map[string]map[string]string)
+
+var matcher = language.NewMatcher([]language.Tag{
+	language.English,
+	language.BrazilianPortuguese,
+	language.Spanish,
+})
+
+func init() {
+	for _, locale := range Supported {
+		data, err := files.ReadFile("locales/" + locale + ".json")
+		if err != nil {
+			panic(err)
+		}
+
+		catalog := make(map[string]string)
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic("i18n: " + locale + ": " + err.Error())
+		}
+		catalogs[locale] = catalog
+	}
+}
+
+// IsSupported reports whether locale has a catalog.
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Tag writes locale as a BCP 47 language tag, such as pt-BR, for the
+// Content-Language header.
+func Tag(locale string) string {
+	return strings.ReplaceAll(locale, "_", "-")
+}
+
+// Negotiate picks the supported locale that best matches an Accept-Language
+// header, such as "pt-PT,pt;q=0.9,en;q=0.8". It reports false when the
+// header is empty or asks for none of them.
+func Negotiate(acceptLanguage string) (string, bool) {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return "", false
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return "", false
+	}
+	return Supported[index], true
+}
+
+// Chain lists the locales to look a message up in: the locale itself, its
+// language without the region, then Default.
+func Chain(locale string) []string {
+	locales := []string{locale}
+	if base, _, ok := strings.Cut(locale, "_"); ok {
+		locales = append(locales, base)
+	}
+	return append(locales, Default)
+}
+
+// Lookup returns the message of key in locale, following the fallback chain,
+// and reports whether any catalog has it.
+func Lookup(locale, key string) (string, bool) {
+	for _, l := range Chain(locale) {
+		if message, ok := catalogs[l][key]; ok {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// T returns the message of key in locale with {0}, {1}... replaced by args.
+// Keys missing from every catalog are returned as is.
+func T(locale, key string, args ...string) string {
+	message, ok := Lookup(locale, key)
+	if !ok {
+		return key
+	}
+
+	for i, arg := range args {
+		message = strings.ReplaceAll(message, "{"+strconv.Itoa(i)+"}", arg)
+	}
+	return message
+}
@@ -0,0 +1,70 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := []struct {
+		purpose        string
+		acceptLanguage string
+		want           string
+		wantOk         bool
+	}{
+		{"should pick the exact locale", "pt-BR", "pt_BR", true},
+		{"should pick the locale of another region", "es-MX,es;q=0.9", "es", true},
+		{"should follow the quality order", "fr;q=0.9,en;q=0.8,es;q=0.5", "en", true},
+		{"should fall back to Portuguese for Portugal", "pt-PT", "pt_BR", true},
+		{"should report languages without a catalog", "fr,de", "", false},
+		{"should report a missing header", "", "", false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			got, ok := Negotiate(tt.acceptLanguage)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	cases := []struct {
+		purpose string
+		locale  string
+		key     string
+		args    []string
+		want    string
+	}{
+		{"should translate to the locale", "es", "emails.verification.subject", nil, "Confirma tu correo"},
+		{"should replace the arguments", "pt_BR", "validation.datetime", []string{"start", "15:04"}, "start deve estar no formato 15:04"},
+		{"should fall back to the language without the region", "es_AR", "emails.verification.subject", nil, "Confirma tu correo"},
+		{"should fall back to the default locale", "fr", "emails.verification.subject", nil, "Confirm your email"},
+		{"should return unknown keys as is", "en", "emails.unknown", nil, "emails.unknown"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			assert.Equal(t, tt.want, T(tt.locale, tt.key, tt.args...))
+		})
+	}
+}
+
+// The English messages of the errors live in package errs, every other
+// message must be in every catalog.
+func TestCatalogsHaveTheSameKeys(t *testing.T) {
+	for _, locale := range Supported {
+		for key := range catalogs[locale] {
+			for _, other := range Supported {
+				if other == Default && strings.HasPrefix(key, "errors.") {
+					continue
+				}
+				_, ok := catalogs[other][key]
+				assert.True(t, ok, "%s is missing from %s", key, other)
+			}
+		}
+	}
+}
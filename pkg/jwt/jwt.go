@@ -15,9 +15,10 @@ type customClaims struct {
 	jwt.StandardClaims
 	Roles    []string
 	Verified bool
+	Locale   string
 }
 
-func GenerateToken(id interface{}, roles []string, verified bool) (string, error) {
+func GenerateToken(id interface{}, roles []string, verified bool, locale string) (string, error) {
 	stdClaims := customClaims{
 		jwt.StandardClaims{
 			Subject:   fmt.Sprintf("%v", id),
@@ -27,6 +28,7 @@ func GenerateToken(id interface{}, roles []string, verified bool) (string, error
 		},
 		roles,
 		verified,
+		locale,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, stdClaims)
@@ -37,28 +39,30 @@ func GenerateToken(id interface{}, roles []string, verified bool) (string, error
 	return ss, nil
 }
 
-func ValidateToken(token string) (string, bool, []string, error) {
+// ValidateToken returns the subject, verified flag, roles and saved locale
+// of a token.
+func ValidateToken(token string) (string, bool, []string, string, error) {
 	parsedToken, err := jwt.ParseWithClaims(token, &customClaims{},
 		func(token *jwt.Token) (interface{}, error) {
 			return []byte(secret), nil
 		})
 	if err != nil {
 		if err.(*jwt.ValidationError).Errors == jwt.ValidationErrorExpired {
-			return "", false, nil, ErrExpiredToken
+			return "", false, nil, "", ErrExpiredToken
 		}
-		return "", false, nil, err
+		return "", false, nil, "", err
 	}
 	claims, ok := parsedToken.Claims.(*customClaims)
 	if !ok {
-		return "", false, nil, errors.New("error on parsing the claims")
+		return "", false, nil, "", errors.New("error on parsing the claims")
 	}
 	if claims.ExpiresAt < time.Now().Local().Unix() {
-		return "", false, nil, ErrExpiredToken
+		return "", false, nil, "", ErrExpiredToken
 	}
 	validErr := claims.Valid()
 	if validErr != nil {
-		return "", false, nil, validErr
+		return "", false, nil, "", validErr
 	}
 
-	return claims.Subject, claims.Verified, claims.Roles, nil
+	return claims.Subject, claims.Verified, claims.Roles, claims.Locale, nil
 }
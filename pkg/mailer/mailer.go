@@ -0,0 +1,59 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// defaultFrom is the sender of the emails when MAIL_FROM is not set.
+const defaultFrom = "onboarding@resend.dev"
+
+// Mailer delivers rendered emails to one recipient.
+type Mailer interface {
+	Deliver(ctx context.Context, to string, email Email) error
+}
+
+// Send writes msg in locale and delivers it to the given address with m.
+func Send(ctx context.Context, m Mailer, to, locale string, msg Message) error {
+	email, err := Render(locale, msg)
+	if err != nil {
+		return err
+	}
+	return m.Deliver(ctx, to, email)
+}
+
+// FromEnv builds the Mailer picked by MAILER: resend, which needs
+// RESEND_API_KEY; smtp, which sends through SMTP_ADDR as SMTP_USERNAME with
+// SMTP_PASSWORD; or outbox, which keeps the emails to be read locally and
+// writes them to MAILBOX_DIR, if set. Unset, it is resend when
+// RESEND_API_KEY is set. As the outbox sends nothing and shows every code,
+// it is only built with APP_ENV=development. Emails are sent from MAIL_FROM.
+func FromEnv() (Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = defaultFrom
+	}
+
+	kind := os.Getenv("MAILER")
+	if kind == "" {
+		if os.Getenv("RESEND_API_KEY") == "" {
+			return nil, errors.New("mailer: MAILER is not set, expected resend, smtp or outbox")
+		}
+		kind = "resend"
+	}
+
+	switch kind {
+	case "resend":
+		return NewResend(os.Getenv("RESEND_API_KEY"), from)
+	case "smtp":
+		return NewSMTP(os.Getenv("SMTP_ADDR"), os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+	case "outbox":
+		if os.Getenv("APP_ENV") != "development" {
+			return nil, errors.New("mailer: the outbox only runs with APP_ENV=development")
+		}
+		return NewOutbox(os.Getenv("MAILBOX_DIR"), from)
+	}
+	return nil, fmt.Errorf("mailer: unknown MAILER %q, expected resend, smtp or outbox", kind)
+}
@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromEnv(t *testing.T) {
+	cases := []struct {
+		purpose   string
+		env       map[string]string
+		want      Mailer
+		wantError string
+	}{
+		{"should send with resend when its key is set", map[string]string{"RESEND_API_KEY": "re_test"}, &Resend{}, ""},
+		{"should send through smtp", map[string]string{"MAILER": "smtp", "SMTP_ADDR": "localhost:1025"}, &SMTP{}, ""},
+		{"should keep emails in the outbox in development", map[string]string{"MAILER": "outbox", "APP_ENV": "development"}, &Outbox{}, ""},
+		{"should refuse to start without a mailer", map[string]string{}, nil, "MAILER is not set"},
+		{"should not fall back to the outbox in development", map[string]string{"APP_ENV": "development"}, nil, "MAILER is not set"},
+		{"should refuse the outbox outside of development", map[string]string{"MAILER": "outbox"}, nil, "APP_ENV=development"},
+		{"should refuse an unknown mailer", map[string]string{"MAILER": "pigeon"}, nil, "unknown MAILER"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			for _, key := range []string{"APP_ENV", "MAILER", "MAILBOX_DIR", "RESEND_API_KEY", "SMTP_ADDR"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			m, err := FromEnv()
+
+			if tt.wantError != "" {
+				assert.ErrorContains(t, err, tt.wantError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.IsType(t, tt.want, m)
+		})
+	}
+}
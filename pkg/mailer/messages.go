@@ -0,0 +1,124 @@
+package mailer
+
+import "time"
+
+// Message is the data of one of the emails sent to users. Its type picks the
+// template it is rendered with.
+type Message interface {
+	template() string
+}
+
+// Verification carries the code that verifies the email of a user, which
+// can be used until ExpiresAt, if set.
+type Verification struct {
+	Username  string
+	Code      string
+	ExpiresAt time.Time
+}
+
+// PasswordReset carries the code that resets the password of a user, which
+// can be used for ValidFor.
+type PasswordReset struct {
+	Username string
+	Code     string
+	ValidFor time.Duration
+}
+
+// DueCare is a care listed in reminders and digests, with Due in the
+// timezone of the user.
+type DueCare struct {
+	Plant string
+	Care  string
+	Due   time.Time
+}
+
+// CareReminder lists the cares due for a user.
+type CareReminder struct {
+	Username string
+	Cares    []DueCare
+}
+
+// Digest sums up the cares of a user between From and To and lists the
+// ones that are overdue or coming up next.
+type Digest struct {
+	Username  string
+	From      time.Time
+	To        time.Time
+	Completed int
+	Skipped   int
+	Overdue   []DueCare
+	Upcoming  []DueCare
+}
+
+// DataExport carries the link to download an export of the user data,
+// which stops working at ExpiresAt.
+type DataExport struct {
+	Username  string
+	Link      string
+	ExpiresAt time.Time
+}
+
+// expiring is implemented by the messages that are of no use after some
+// time, such as the ones carrying a code.
+type expiring interface {
+	expiresAt(now time.Time) time.Time
+}
+
+func (v Verification) expiresAt(now time.Time) time.Time  { return v.ExpiresAt }
+func (p PasswordReset) expiresAt(now time.Time) time.Time { return now.Add(p.ValidFor) }
+
+func (Verification) template() string  { return "verification" }
+func (PasswordReset) template() string { return "password_reset" }
+func (CareReminder) template() string  { return "care_reminder" }
+func (Digest) template() string        { return "digest" }
+func (DataExport) template() string    { return "data_export" }
+
+// Templates lists the names of the email templates.
+var Templates = []string{"verification", "password_reset", "care_reminder", "digest", "data_export"}
+
+// IsNotification reports whether the emails of template are notifications,
+// which may wait for the quiet hours of the user to end, as opposed to the
+// emails the user is waiting for to sign in.
+func IsNotification(template string) bool {
+	switch template {
+	case "care_reminder", "digest", "data_export":
+		return true
+	}
+	return false
+}
+
+// Sample returns a message rendered with the template name, filled with
+// made-up data to preview it.
+func Sample(name string) (Message, bool) {
+	due := time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC)
+	cares := []DueCare{
+		{Plant: "Monstera", Care: "Water", Due: due},
+		{Plant: "Fiddle leaf fig", Care: "Fertilize", Due: due.Add(2 * time.Hour)},
+	}
+
+	switch name {
+	case "verification":
+		return Verification{Username: "fern", Code: "123456"}, true
+	case "password_reset":
+		return PasswordReset{Username: "fern", Code: "654321", ValidFor: 5 * time.Minute}, true
+	case "care_reminder":
+		return CareReminder{Username: "fern", Cares: cares}, true
+	case "digest":
+		return Digest{
+			Username:  "fern",
+			From:      due.AddDate(0, 0, -7),
+			To:        due,
+			Completed: 12,
+			Skipped:   1,
+			Overdue:   cares[:1],
+			Upcoming:  cares[1:],
+		}, true
+	case "data_export":
+		return DataExport{
+			Username:  "fern",
+			Link:      "https://plants.example.com/api/v1/exports/3f0c1a52-8d7e-4b1e-9a43-0c6f2f1d9b7e",
+			ExpiresAt: due.Add(48 * time.Hour),
+		}, true
+	}
+	return nil, false
+}
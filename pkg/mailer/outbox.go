@@ -0,0 +1,95 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// outboxCapacity is how many emails the outbox keeps in memory; older ones
+// are dropped.
+const outboxCapacity = 100
+
+// OutboxEmail is an email kept by the outbox.
+type OutboxEmail struct {
+	Id     string    `json:"id"`
+	To     string    `json:"to"`
+	SentAt time.Time `json:"sentAt"`
+	Email
+}
+
+// Outbox keeps the emails instead of sending them, to be read locally, such
+// as through the /dev/mailbox viewer. When it has a directory, each email is
+// also written there as an .eml file.
+type Outbox struct {
+	dir  string
+	from string
+
+	mu     sync.RWMutex
+	emails []OutboxEmail
+}
+
+func NewOutbox(dir, from string) (*Outbox, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Outbox{dir: dir, from: from}, nil
+}
+
+func (o *Outbox) Deliver(ctx context.Context, to string, email Email) error {
+	sent := OutboxEmail{Id: uuid.NewString(), To: to, SentAt: time.Now(), Email: email}
+
+	if o.dir != "" {
+		var msg bytes.Buffer
+		if err := writeMessage(&msg, o.from, to, email, sent.SentAt); err != nil {
+			return err
+		}
+
+		name := sent.SentAt.UTC().Format("20060102T150405") + "-" + sent.Id + ".eml"
+		if err := os.WriteFile(filepath.Join(o.dir, name), msg.Bytes(), 0o600); err != nil {
+			return err
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.emails = append(o.emails, sent)
+	if len(o.emails) > outboxCapacity {
+		o.emails = o.emails[len(o.emails)-outboxCapacity:]
+	}
+	return nil
+}
+
+// Emails lists the kept emails, newest first.
+func (o *Outbox) Emails() []OutboxEmail {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	emails := make([]OutboxEmail, 0, len(o.emails))
+	for i := len(o.emails) - 1; i >= 0; i-- {
+		emails = append(emails, o.emails[i])
+	}
+	return emails
+}
+
+// Email returns the kept email with the given id.
+func (o *Outbox) Email(id string) (OutboxEmail, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	for _, email := range o.emails {
+		if email.Id == id {
+			return email, true
+		}
+	}
+	return OutboxEmail{}, false
+}
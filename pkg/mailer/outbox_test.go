@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutbox(t *testing.T) {
+	dir := t.TempDir()
+	outbox, err := NewOutbox(dir, "plants@example.com")
+	assert.NoError(t, err)
+
+	for i := 0; i < outboxCapacity+2; i++ {
+		email := Email{Subject: fmt.Sprint("email ", i), HTML: "<p>hi</p>", Text: "hi"}
+		assert.NoError(t, outbox.Deliver(context.Background(), "fern@example.com", email))
+	}
+
+	emails := outbox.Emails()
+	assert.Len(t, emails, outboxCapacity, "should keep the latest emails only")
+	assert.Equal(t, fmt.Sprint("email ", outboxCapacity+1), emails[0].Subject, "should list the newest first")
+	assert.Equal(t, "email 2", emails[len(emails)-1].Subject)
+
+	got, ok := outbox.Email(emails[0].Id)
+	assert.True(t, ok)
+	assert.Equal(t, emails[0], got)
+
+	_, ok = outbox.Email("unknown")
+	assert.False(t, ok)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.NoError(t, err)
+	assert.Len(t, files, outboxCapacity+2, "should write every email to the directory")
+
+	data, err := os.ReadFile(files[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "To: fern@example.com\r\n")
+}
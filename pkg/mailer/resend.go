@@ -1,38 +1,33 @@
 package mailer
 
 import (
-	"fmt"
+	"context"
+	"errors"
 
 	"github.com/resend/resend-go/v2"
 )
 
-var client *resend.Client
+// Resend delivers emails through the Resend API.
+type Resend struct {
+	client *resend.Client
+	from   string
+}
 
-func Init(key string) {
+func NewResend(key, from string) (*Resend, error) {
 	if key == "" {
-		panic("RESEND_API_KEY is not set")
+		return nil, errors.New("mailer: RESEND_API_KEY is not set")
 	}
 
-	client = resend.NewClient(key)
-}
-
-func SendConfirmationEmail(to string, code string) error {
-	_, err := client.Emails.Send(&resend.SendEmailRequest{
-		From:    "onboarding@resend.dev",
-		To:      []string{to},
-		Html:    fmt.Sprintf("<h1>%s</h1>", code),
-		Subject: "Confirm your email",
-	})
-
-	return err
+	return &Resend{client: resend.NewClient(key), from: from}, nil
 }
 
-func SendPasswordResetEmail(to string, code string) error {
-	_, err := client.Emails.Send(&resend.SendEmailRequest{
-		From:    "onboarding@resend.dev",
+func (r *Resend) Deliver(ctx context.Context, to string, email Email) error {
+	_, err := r.client.Emails.SendWithContext(ctx, &resend.SendEmailRequest{
+		From:    r.from,
 		To:      []string{to},
-		Html:    fmt.Sprintf("<h1>%s</h1>", code),
-		Subject: "Confirm your email",
+		Html:    email.HTML,
+		Text:    email.Text,
+		Subject: email.Subject,
 	})
 
 	return err
@@ -0,0 +1,159 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"time"
+)
+
+// smtpTimeout bounds a whole delivery through SMTP.
+const smtpTimeout = 30 * time.Second
+
+// SMTP delivers emails through an SMTP server, over TLS when the server
+// offers STARTTLS.
+type SMTP struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTP sends through the server at addr, as host:port, authenticating
+// with PLAIN when a username is given.
+func NewSMTP(addr, username, password, from string) (*SMTP, error) {
+	if addr == "" {
+		return nil, errors.New("mailer: SMTP_ADDR is not set")
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("mailer: SMTP_ADDR: %w", err)
+	}
+
+	s := &SMTP{addr: addr, host: host, from: from}
+	if username != "" {
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s, nil
+}
+
+func (s *SMTP) Deliver(ctx context.Context, to string, email Email) error {
+	var msg bytes.Buffer
+	if err := writeMessage(&msg, s.from, to, email, time.Now()); err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(smtpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.auth != nil {
+		if err := c.Auth(s.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.from); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg.Bytes()); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// writeMessage writes email as a multipart/alternative MIME message, with
+// the plain-text body first so that clients prefer the HTML one.
+func writeMessage(w io.Writer, from, to string, email Email, date time.Time) error {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", email.Text},
+		{"text/html; charset=utf-8", email.HTML},
+	}
+	for _, part := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return err
+		}
+
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := io.WriteString(qw, part.content); err != nil {
+			return err
+		}
+		if err := qw.Close(); err != nil {
+			return err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	headers := []struct{ name, value string }{
+		{"From", from},
+		{"To", to},
+		{"Subject", mime.QEncoding.Encode("utf-8", email.Subject)},
+		{"Date", date.Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + mw.Boundary()},
+	}
+	for _, h := range headers {
+		if _, err := fmt.Fprintf(w, "%s: %s\r\n", h.name, h.value); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(w, "\r\n"); err != nil {
+		return err
+	}
+
+	_, err := body.WriteTo(w)
+	return err
+}
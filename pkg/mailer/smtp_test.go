@@ -0,0 +1,117 @@
+package mailer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testEmail = Email{
+	Subject: "Confirme seu email",
+	HTML:    "<p>Olá, fern,</p><p>123456</p>",
+	Text:    "Olá, fern,\n\n    123456\n",
+}
+
+// assertMessage checks that raw is testEmail sent to fern@example.com.
+func assertMessage(t *testing.T, raw []byte) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	assert.NoError(t, err)
+	assert.Equal(t, "fern@example.com", msg.Header.Get("To"))
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.NoError(t, err)
+	assert.Equal(t, testEmail.Subject, subject)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	var bodies []string
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := mr.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		assert.NoError(t, err)
+		bodies = append(bodies, part.Header.Get("Content-Type")+"|"+strings.ReplaceAll(string(body), "\r\n", "\n"))
+	}
+
+	assert.Equal(t, []string{
+		"text/plain; charset=utf-8|" + testEmail.Text,
+		"text/html; charset=utf-8|" + testEmail.HTML,
+	}, bodies)
+}
+
+func TestWriteMessage(t *testing.T) {
+	var raw bytes.Buffer
+	assert.NoError(t, writeMessage(&raw, "plants@example.com", "fern@example.com", testEmail, time.Now()))
+	assertMessage(t, raw.Bytes())
+}
+
+func TestSMTPDeliver(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan []byte, 1)
+	go serveSMTP(t, ln, received)
+
+	s, err := NewSMTP(ln.Addr().String(), "", "", "plants@example.com")
+	assert.NoError(t, err)
+	assert.NoError(t, s.Deliver(context.Background(), "fern@example.com", testEmail))
+
+	assertMessage(t, <-received)
+}
+
+// serveSMTP answers one SMTP session, just enough for net/smtp, and sends
+// the received message data to received.
+func serveSMTP(t *testing.T, ln net.Listener, received chan<- []byte) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ready")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+
+		switch cmd, _, _ := strings.Cut(line, " "); strings.ToUpper(cmd) {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250 localhost")
+		case "MAIL", "RCPT":
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			data, err := io.ReadAll(bufio.NewReader(tp.DotReader()))
+			assert.NoError(t, err)
+			received <- data
+			tp.PrintfLine("250 OK")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 not implemented")
+		}
+	}
+}
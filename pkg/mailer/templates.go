@@ -0,0 +1,173 @@
+package mailer
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/pkg/i18n"
+)
+
+// The templates of every email are <name>.html and <name>.txt, which define
+// the "subject", in the text one only, and the "content" blocks of the
+// layout.html and layout.txt layouts. A file in templates/<locale> replaces
+// the one of the same name for that locale; otherwise the texts come from
+// the emails.* messages of the i18n catalogs.
+//
+//go:embed templates
+var templateFiles embed.FS
+
+// Email is a message rendered for one recipient, with the plain-text
+// alternative of its HTML body. Template names the template it was
+// rendered with, and ExpiresAt, if set, is when the email is of no use
+// anymore and should no longer be sent.
+type Email struct {
+	Template  string     `json:"template"`
+	Subject   string     `json:"subject"`
+	HTML      string     `json:"html"`
+	Text      string     `json:"text"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type emailTemplate struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// templates holds the parsed templates by locale, then name.
+var templates map[string]map[string]emailTemplate
+
+func init() {
+	sub, err := fs.Sub(templateFiles, "templates")
+	if err != nil {
+		panic(err)
+	}
+
+	if templates, err = parseTemplates(sub); err != nil {
+		panic(err)
+	}
+}
+
+func parseTemplates(fsys fs.FS) (map[string]map[string]emailTemplate, error) {
+	parsed := make(map[string]map[string]emailTemplate, len(i18n.Supported))
+	for _, locale := range i18n.Supported {
+		parsed[locale] = make(map[string]emailTemplate, len(Templates))
+		for _, name := range Templates {
+			tmpl, err := parseTemplate(fsys, locale, name)
+			if err != nil {
+				return nil, fmt.Errorf("mailer: %s/%s: %w", locale, name, err)
+			}
+			parsed[locale][name] = tmpl
+		}
+	}
+	return parsed, nil
+}
+
+func parseTemplate(fsys fs.FS, locale, name string) (emailTemplate, error) {
+	funcs := templateFuncs(locale)
+	html := htmltemplate.New("layout").Funcs(funcs)
+	text := texttemplate.New("layout").Funcs(funcs)
+
+	for _, file := range []string{"layout", name} {
+		src, err := readTemplate(fsys, locale, file+".html")
+		if err != nil {
+			return emailTemplate{}, err
+		}
+		if _, err := html.Parse(src); err != nil {
+			return emailTemplate{}, err
+		}
+
+		if src, err = readTemplate(fsys, locale, file+".txt"); err != nil {
+			return emailTemplate{}, err
+		}
+		if _, err := text.Parse(src); err != nil {
+			return emailTemplate{}, err
+		}
+	}
+
+	if text.Lookup("subject") == nil {
+		return emailTemplate{}, errors.New("no subject defined")
+	}
+	return emailTemplate{html: html, text: text}, nil
+}
+
+// readTemplate reads file from the directory of the first locale of the
+// fallback chain that has it, or from the root.
+func readTemplate(fsys fs.FS, locale, file string) (string, error) {
+	for _, l := range i18n.Chain(locale) {
+		data, err := fs.ReadFile(fsys, l+"/"+file)
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	data, err := fs.ReadFile(fsys, file)
+	return string(data), err
+}
+
+func templateFuncs(locale string) map[string]any {
+	return map[string]any{
+		"t": func(key string, args ...any) string {
+			strs := make([]string, len(args))
+			for i, arg := range args {
+				strs[i] = fmt.Sprint(arg)
+			}
+			return i18n.T(locale, key, strs...)
+		},
+		"date": func(t time.Time) string {
+			return t.Format(i18n.T(locale, "emails.format.date"))
+		},
+		"datetime": func(t time.Time) string {
+			return t.Format(i18n.T(locale, "emails.format.datetime"))
+		},
+		"minutes": func(d time.Duration) int {
+			return int(d.Minutes())
+		},
+	}
+}
+
+// Render writes msg in locale, or in i18n.Default when it is not supported.
+func Render(locale string, msg Message) (Email, error) {
+	byName, ok := templates[locale]
+	if !ok {
+		byName = templates[i18n.Default]
+	}
+
+	tmpl, ok := byName[msg.template()]
+	if !ok {
+		return Email{}, fmt.Errorf("mailer: no template %q", msg.template())
+	}
+
+	var subject, text, html strings.Builder
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", msg); err != nil {
+		return Email{}, err
+	}
+	if err := tmpl.text.Execute(&text, msg); err != nil {
+		return Email{}, err
+	}
+	if err := tmpl.html.Execute(&html, msg); err != nil {
+		return Email{}, err
+	}
+
+	email := Email{
+		Template: msg.template(),
+		Subject:  strings.TrimSpace(subject.String()),
+		HTML:     html.String(),
+		Text:     strings.TrimSpace(text.String()) + "\n",
+	}
+
+	if m, ok := msg.(expiring); ok {
+		if expiresAt := m.expiresAt(time.Now().UTC()); !expiresAt.IsZero() {
+			email.ExpiresAt = &expiresAt
+		}
+	}
+	return email, nil
+}
@@ -0,0 +1,138 @@
+package mailer
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/mathehluiz/plant-care-tracker/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	for _, locale := range i18n.Supported {
+		for _, name := range Templates {
+			t.Run(locale+"/"+name, func(t *testing.T) {
+				msg, ok := Sample(name)
+				assert.True(t, ok)
+
+				email, err := Render(locale, msg)
+				assert.NoError(t, err)
+				assert.NotEmpty(t, email.Subject)
+				assert.NotContains(t, email.Subject, "emails.")
+				assert.Contains(t, email.HTML, "<html>")
+				assert.NotContains(t, email.HTML, "emails.")
+				assert.NotContains(t, email.Text, "<")
+				assert.NotContains(t, email.Text, "emails.")
+				assert.Contains(t, email.Text, "fern")
+			})
+		}
+	}
+}
+
+func TestRenderMessages(t *testing.T) {
+	cases := []struct {
+		purpose     string
+		locale      string
+		msg         Message
+		wantSubject string
+		wantHTML    string
+		wantText    string
+	}{
+		{
+			"should write the password reset in its own words",
+			"en",
+			PasswordReset{Username: "fern", Code: "654321", ValidFor: 5 * time.Minute},
+			"Reset your password",
+			"654321",
+			"The code expires in 5 minutes.",
+		},
+		{
+			"should write in the locale of the user",
+			"pt_BR",
+			Verification{Username: "fern", Code: "123456"},
+			"Confirme seu email",
+			"Olá, fern,",
+			"123456",
+		},
+		{
+			"should fall back to the default locale",
+			"fr",
+			Verification{Username: "fern", Code: "123456"},
+			"Confirm your email",
+			"Hi fern,",
+			"123456",
+		},
+		{
+			"should escape the data in the HTML body only",
+			"en",
+			Verification{Username: "<b>fern</b>", Code: "123456"},
+			"Confirm your email",
+			"Hi &lt;b&gt;fern&lt;/b&gt;,",
+			"Hi <b>fern</b>,",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			email, err := Render(tt.locale, tt.msg)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSubject, email.Subject)
+			assert.Contains(t, email.HTML, tt.wantHTML)
+			assert.Contains(t, email.Text, tt.wantText)
+		})
+	}
+}
+
+func TestRenderExpiry(t *testing.T) {
+	expiresAt := time.Now().UTC().Add(15 * time.Minute)
+
+	cases := []struct {
+		purpose string
+		msg     Message
+		want    time.Time
+	}{
+		{"should expire a verification with its code", Verification{Username: "fern", Code: "123456", ExpiresAt: expiresAt}, expiresAt},
+		{"should expire a password reset once its code is no longer valid", PasswordReset{Username: "fern", Code: "654321", ValidFor: 5 * time.Minute}, time.Now().Add(5 * time.Minute)},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			email, err := Render("en", tt.msg)
+			assert.NoError(t, err)
+			if assert.NotNil(t, email.ExpiresAt) {
+				assert.WithinDuration(t, tt.want, *email.ExpiresAt, time.Second)
+			}
+		})
+	}
+
+	email, err := Render("en", Digest{Username: "fern"})
+	assert.NoError(t, err)
+	assert.Nil(t, email.ExpiresAt, "should not expire a notification")
+}
+
+func TestParseTemplatesLocaleVariants(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for _, name := range append([]string{"layout"}, Templates...) {
+		for _, ext := range []string{".html", ".txt"} {
+			data, err := fs.ReadFile(templateFiles, "templates/"+name+ext)
+			assert.NoError(t, err)
+			fsys[name+ext] = &fstest.MapFile{Data: data}
+		}
+	}
+	fsys["pt/verification.txt"] = &fstest.MapFile{Data: []byte(`{{define "subject"}}Bem-vindo{{end}}{{define "content"}}{{.Code}}{{end}}`)}
+
+	parsed, err := parseTemplates(fsys)
+	assert.NoError(t, err)
+
+	subject := func(locale string) string {
+		var b strings.Builder
+		assert.NoError(t, parsed[locale]["verification"].text.ExecuteTemplate(&b, "subject", Verification{}))
+		return b.String()
+	}
+	assert.Equal(t, "Bem-vindo", subject("pt_BR"))
+	assert.Equal(t, "Confirm your email", subject("en"))
+	assert.Equal(t, "Confirma tu correo", subject("es"))
+}
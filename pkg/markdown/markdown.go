@@ -0,0 +1,26 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	md     = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	policy = bluemonday.UGCPolicy()
+)
+
+// Render converts GitHub flavoured Markdown to HTML and strips anything that
+// is not safe to embed in a page, such as scripts, event handlers and
+// javascript: links.
+func Render(src string) (string, error) {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(src), &buf); err != nil {
+		return "", err
+	}
+
+	return policy.Sanitize(buf.String()), nil
+}
@@ -0,0 +1,39 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		purpose string
+		src     string
+		want    string
+	}{
+		{
+			"should render markdown",
+			"# Monstera\n\nWater **weekly**.",
+			"<h1>Monstera</h1>\n<p>Water <strong>weekly</strong>.</p>\n",
+		},
+		{
+			"should strip script tags",
+			"Hello <script>alert(1)</script>",
+			"<p>Hello alert(1)</p>\n",
+		},
+		{
+			"should strip javascript links",
+			"[click](javascript:alert(1))",
+			"<p>click</p>\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.purpose, func(t *testing.T) {
+			got, err := Render(c.src)
+			assert.NoError(t, err)
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
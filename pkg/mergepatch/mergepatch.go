@@ -0,0 +1,61 @@
+// Package mergepatch reads RFC 7396 JSON Merge Patch documents: members that
+// are present replace the target value, members set to null remove it, and
+// absent members leave it unchanged.
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+)
+
+// ContentType is the media type of merge patch documents.
+const ContentType = "application/merge-patch+json"
+
+var ErrNotObject = errors.New("merge patch must be a JSON object")
+
+// Document is a parsed merge patch, keyed by member name.
+type Document map[string]json.RawMessage
+
+// Parse reads a merge patch. Only objects are accepted, since every patched
+// resource is an object.
+func Parse(body []byte) (Document, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '{' {
+		return nil, ErrNotObject
+	}
+
+	var doc Document
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Has reports whether the member was sent, null or not.
+func (d Document) Has(field string) bool {
+	_, ok := d[field]
+	return ok
+}
+
+// IsNull reports whether the member was sent as null.
+func (d Document) IsNull(field string) bool {
+	v, ok := d[field]
+	return ok && bytes.Equal(bytes.TrimSpace(v), []byte("null"))
+}
+
+// Decode unmarshals the member into v.
+func (d Document) Decode(field string, v any) error {
+	return json.Unmarshal(d[field], v)
+}
+
+// Fields returns the names of the members sent, sorted.
+func (d Document) Fields() []string {
+	fields := make([]string, 0, len(d))
+	for field := range d {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,738 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v4.25.3
+// source: plantcare/v1/cares.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Care struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PlantId     int64                  `protobuf:"varint,2,opt,name=plant_id,json=plantId,proto3" json:"plant_id,omitempty"`
+	Name        string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Notes       string                 `protobuf:"bytes,4,opt,name=notes,proto3" json:"notes,omitempty"`
+	Interval    int32                  `protobuf:"varint,5,opt,name=interval,proto3" json:"interval,omitempty"`
+	LastCare    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_care,json=lastCare,proto3" json:"last_care,omitempty"`
+	NextCare    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=next_care,json=nextCare,proto3" json:"next_care,omitempty"`
+	SnoozedFrom *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=snoozed_from,json=snoozedFrom,proto3" json:"snoozed_from,omitempty"`
+	Recurrence  *Recurrence            `protobuf:"bytes,9,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	Checklist   []*ChecklistItem       `protobuf:"bytes,10,rep,name=checklist,proto3" json:"checklist,omitempty"`
+	Version     int32                  `protobuf:"varint,11,opt,name=version,proto3" json:"version,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *Care) Reset() {
+	*x = Care{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_plantcare_v1_cares_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Care) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Care) ProtoMessage() {}
+
+func (x *Care) ProtoReflect() protoreflect.Message {
+	mi := &file_plantcare_v1_cares_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Care.ProtoReflect.Descriptor instead.
+func (*Care) Descriptor() ([]byte, []int) {
+	return file_plantcare_v1_cares_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Care) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Care) GetPlantId() int64 {
+	if x != nil {
+		return x.PlantId
+	}
+	return 0
+}
+
+func (x *Care) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Care) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *Care) GetInterval() int32 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+func (x *Care) GetLastCare() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastCare
+	}
+	return nil
+}
+
+func (x *Care) GetNextCare() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextCare
+	}
+	return nil
+}
+
+func (x *Care) GetSnoozedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SnoozedFrom
+	}
+	return nil
+}
+
+func (x *Care) GetRecurrence() *Recurrence {
+	if x != nil {
+		return x.Recurrence
+	}
+	return nil
+}
+
+func (x *Care) GetChecklist() []*ChecklistItem {
+	if x != nil {
+		return x.Checklist
+	}
+	return nil
+}
+
+func (x *Care) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Care) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Care) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Recurrence struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rrule    string                   `protobuf:"bytes,1,opt,name=rrule,proto3" json:"rrule,omitempty"`
+	Start    *timestamppb.Timestamp   `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	Timezone string                   `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Exdates  []*timestamppb.Timestamp `protobuf:"bytes,4,rep,name=exdates,proto3" json:"exdates,omitempty"`
+}
+
+func (x *Recurrence) Reset() {
+	*x = Recurrence{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_plantcare_v1_cares_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Recurrence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Recurrence) ProtoMessage() {}
+
+func (x *Recurrence) ProtoReflect() protoreflect.Message {
+	mi := &file_plantcare_v1_cares_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Recurrence.ProtoReflect.Descriptor instead.
+func (*Recurrence) Descriptor() ([]byte, []int) {
+	return file_plantcare_v1_cares_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Recurrence) GetRrule() string {
+	if x != nil {
+		return x.Rrule
+	}
+	return ""
+}
+
+func (x *Recurrence) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Recurrence) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Recurrence) GetExdates() []*timestamppb.Timestamp {
+	if x != nil {
+		return x.Exdates
+	}
+	return nil
+}
+
+type ChecklistItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title     string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Checked   bool                   `protobuf:"varint,3,opt,name=checked,proto3" json:"checked,omitempty"`
+	CheckedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+}
+
+func (x *ChecklistItem) Reset() {
+	*x = ChecklistItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_plantcare_v1_cares_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChecklistItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChecklistItem) ProtoMessage() {}
+
+func (x *ChecklistItem) ProtoReflect() protoreflect.Message {
+	mi := &file_plantcare_v1_cares_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChecklistItem.ProtoReflect.Descriptor instead.
+func (*ChecklistItem) Descriptor() ([]byte, []int) {
+	return file_plantcare_v1_cares_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ChecklistItem) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ChecklistItem) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ChecklistItem) GetChecked() bool {
+	if x != nil {
+		return x.Checked
+	}
+	return false
+}
+
+func (x *ChecklistItem) GetCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
+}
+
+type ListCaresRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PlantId int64 `protobuf:"varint,1,opt,name=plant_id,json=plantId,proto3" json:"plant_id,omitempty"`
+}
+
+func (x *ListCaresRequest) Reset() {
+	*x = ListCaresRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_plantcare_v1_cares_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCaresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCaresRequest) ProtoMessage() {}
+
+func (x *ListCaresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plantcare_v1_cares_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCaresRequest.ProtoReflect.Descriptor instead.
+func (*ListCaresRequest) Descriptor() ([]byte, []int) {
+	return file_plantcare_v1_cares_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListCaresRequest) GetPlantId() int64 {
+	if x != nil {
+		return x.PlantId
+	}
+	return 0
+}
+
+type ListCaresResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cares []*Care `protobuf:"bytes,1,rep,name=cares,proto3" json:"cares,omitempty"`
+}
+
+func (x *ListCaresResponse) Reset() {
+	*x = ListCaresResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_plantcare_v1_cares_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCaresResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCaresResponse) ProtoMessage() {}
+
+func (x *ListCaresResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plantcare_v1_cares_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCaresResponse.ProtoReflect.Descriptor instead.
+func (*ListCaresResponse) Descriptor() ([]byte, []int) {
+	return file_plantcare_v1_cares_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListCaresResponse) GetCares() []*Care {
+	if x != nil {
+		return x.Cares
+	}
+	return nil
+}
+
+type GetCareRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetCareRequest) Reset() {
+	*x = GetCareRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_plantcare_v1_cares_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCareRequest) ProtoMessage() {}
+
+func (x *GetCareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plantcare_v1_cares_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCareRequest.ProtoReflect.Descriptor instead.
+func (*GetCareRequest) Descriptor() ([]byte, []int) {
+	return file_plantcare_v1_cares_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetCareRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type WatchDueCaresRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WatchDueCaresRequest) Reset() {
+	*x = WatchDueCaresRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_plantcare_v1_cares_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchDueCaresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDueCaresRequest) ProtoMessage() {}
+
+func (x *WatchDueCaresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plantcare_v1_cares_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDueCaresRequest.ProtoReflect.Descriptor instead.
+func (*WatchDueCaresRequest) Descriptor() ([]byte, []int) {
+	return file_plantcare_v1_cares_proto_rawDescGZIP(), []int{6}
+}
+
+var File_plantcare_v1_cares_proto protoreflect.FileDescriptor
+
+var file_plantcare_v1_cares_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x63,
+	0x61, 0x72, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x70, 0x6c, 0x61, 0x6e,
+	0x74, 0x63, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xad, 0x04, 0x0a, 0x04, 0x43, 0x61,
+	0x72, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x12, 0x37, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x63, 0x61, 0x72, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x43, 0x61, 0x72, 0x65, 0x12, 0x37, 0x0a, 0x09,
+	0x6e, 0x65, 0x78, 0x74, 0x5f, 0x63, 0x61, 0x72, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x6e, 0x65, 0x78,
+	0x74, 0x43, 0x61, 0x72, 0x65, 0x12, 0x3d, 0x0a, 0x0c, 0x73, 0x6e, 0x6f, 0x6f, 0x7a, 0x65, 0x64,
+	0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x73, 0x6e, 0x6f, 0x6f, 0x7a, 0x65, 0x64,
+	0x46, 0x72, 0x6f, 0x6d, 0x12, 0x38, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74,
+	0x63, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x39,
+	0x0a, 0x09, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x0a, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x09,
+	0x63, 0x68, 0x65, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39,
+	0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xa6, 0x01, 0x0a, 0x0a, 0x52, 0x65,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x72, 0x75, 0x6c,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x72, 0x75, 0x6c, 0x65, 0x12, 0x30,
+	0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x34, 0x0a, 0x07,
+	0x65, 0x78, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x78, 0x64, 0x61, 0x74,
+	0x65, 0x73, 0x22, 0x8a, 0x01, 0x0a, 0x0d, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74,
+	0x49, 0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68,
+	0x65, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x65, 0x64, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x41, 0x74, 0x22,
+	0x2d, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x3d,
+	0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x63, 0x61, 0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x61, 0x72, 0x65, 0x52, 0x05, 0x63, 0x61, 0x72, 0x65, 0x73, 0x22, 0x20, 0x0a,
+	0x0e, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0x16, 0x0a, 0x14, 0x57, 0x61, 0x74, 0x63, 0x68, 0x44, 0x75, 0x65, 0x43, 0x61, 0x72, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x32, 0xe3, 0x01, 0x0a, 0x0b, 0x43, 0x61, 0x72, 0x65,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4c, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x43,
+	0x61, 0x72, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x65,
+	0x12, 0x1c, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12,
+	0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61,
+	0x72, 0x65, 0x12, 0x49, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x44, 0x75, 0x65, 0x43, 0x61,
+	0x72, 0x65, 0x73, 0x12, 0x22, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x44, 0x75, 0x65, 0x43, 0x61, 0x72, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63,
+	0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x72, 0x65, 0x30, 0x01, 0x42, 0x31, 0x5a,
+	0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x61, 0x74, 0x68,
+	0x65, 0x68, 0x6c, 0x75, 0x69, 0x7a, 0x2f, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x2d, 0x63, 0x61, 0x72,
+	0x65, 0x2d, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x62,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_plantcare_v1_cares_proto_rawDescOnce sync.Once
+	file_plantcare_v1_cares_proto_rawDescData = file_plantcare_v1_cares_proto_rawDesc
+)
+
+func file_plantcare_v1_cares_proto_rawDescGZIP() []byte {
+	file_plantcare_v1_cares_proto_rawDescOnce.Do(func() {
+		file_plantcare_v1_cares_proto_rawDescData = protoimpl.X.CompressGZIP(file_plantcare_v1_cares_proto_rawDescData)
+	})
+	return file_plantcare_v1_cares_proto_rawDescData
+}
+
+var file_plantcare_v1_cares_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_plantcare_v1_cares_proto_goTypes = []any{
+	(*Care)(nil),                  // 0: plantcare.v1.Care
+	(*Recurrence)(nil),            // 1: plantcare.v1.Recurrence
+	(*ChecklistItem)(nil),         // 2: plantcare.v1.ChecklistItem
+	(*ListCaresRequest)(nil),      // 3: plantcare.v1.ListCaresRequest
+	(*ListCaresResponse)(nil),     // 4: plantcare.v1.ListCaresResponse
+	(*GetCareRequest)(nil),        // 5: plantcare.v1.GetCareRequest
+	(*WatchDueCaresRequest)(nil),  // 6: plantcare.v1.WatchDueCaresRequest
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+}
+var file_plantcare_v1_cares_proto_depIdxs = []int32{
+	7,  // 0: plantcare.v1.Care.last_care:type_name -> google.protobuf.Timestamp
+	7,  // 1: plantcare.v1.Care.next_care:type_name -> google.protobuf.Timestamp
+	7,  // 2: plantcare.v1.Care.snoozed_from:type_name -> google.protobuf.Timestamp
+	1,  // 3: plantcare.v1.Care.recurrence:type_name -> plantcare.v1.Recurrence
+	2,  // 4: plantcare.v1.Care.checklist:type_name -> plantcare.v1.ChecklistItem
+	7,  // 5: plantcare.v1.Care.created_at:type_name -> google.protobuf.Timestamp
+	7,  // 6: plantcare.v1.Care.updated_at:type_name -> google.protobuf.Timestamp
+	7,  // 7: plantcare.v1.Recurrence.start:type_name -> google.protobuf.Timestamp
+	7,  // 8: plantcare.v1.Recurrence.exdates:type_name -> google.protobuf.Timestamp
+	7,  // 9: plantcare.v1.ChecklistItem.checked_at:type_name -> google.protobuf.Timestamp
+	0,  // 10: plantcare.v1.ListCaresResponse.cares:type_name -> plantcare.v1.Care
+	3,  // 11: plantcare.v1.CareService.ListCares:input_type -> plantcare.v1.ListCaresRequest
+	5,  // 12: plantcare.v1.CareService.GetCare:input_type -> plantcare.v1.GetCareRequest
+	6,  // 13: plantcare.v1.CareService.WatchDueCares:input_type -> plantcare.v1.WatchDueCaresRequest
+	4,  // 14: plantcare.v1.CareService.ListCares:output_type -> plantcare.v1.ListCaresResponse
+	0,  // 15: plantcare.v1.CareService.GetCare:output_type -> plantcare.v1.Care
+	0,  // 16: plantcare.v1.CareService.WatchDueCares:output_type -> plantcare.v1.Care
+	14, // [14:17] is the sub-list for method output_type
+	11, // [11:14] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_plantcare_v1_cares_proto_init() }
+func file_plantcare_v1_cares_proto_init() {
+	if File_plantcare_v1_cares_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_plantcare_v1_cares_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Care); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_plantcare_v1_cares_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*Recurrence); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_plantcare_v1_cares_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*ChecklistItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_plantcare_v1_cares_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*ListCaresRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_plantcare_v1_cares_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*ListCaresResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_plantcare_v1_cares_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*GetCareRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_plantcare_v1_cares_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchDueCaresRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_plantcare_v1_cares_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_plantcare_v1_cares_proto_goTypes,
+		DependencyIndexes: file_plantcare_v1_cares_proto_depIdxs,
+		MessageInfos:      file_plantcare_v1_cares_proto_msgTypes,
+	}.Build()
+	File_plantcare_v1_cares_proto = out.File
+	file_plantcare_v1_cares_proto_rawDesc = nil
+	file_plantcare_v1_cares_proto_goTypes = nil
+	file_plantcare_v1_cares_proto_depIdxs = nil
+}
@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v4.25.3
+// source: plantcare/v1/cares.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CareService_ListCares_FullMethodName     = "/plantcare.v1.CareService/ListCares"
+	CareService_GetCare_FullMethodName       = "/plantcare.v1.CareService/GetCare"
+	CareService_WatchDueCares_FullMethodName = "/plantcare.v1.CareService/WatchDueCares"
+)
+
+// CareServiceClient is the client API for CareService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CareServiceClient interface {
+	// ListCares returns the cares of the authenticated user, or of one of
+	// their plants when plant_id is set.
+	ListCares(ctx context.Context, in *ListCaresRequest, opts ...grpc.CallOption) (*ListCaresResponse, error)
+	GetCare(ctx context.Context, in *GetCareRequest, opts ...grpc.CallOption) (*Care, error)
+	// WatchDueCares first sends the cares of the authenticated user that are
+	// due now, then every care as it comes due, until the client cancels.
+	WatchDueCares(ctx context.Context, in *WatchDueCaresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Care], error)
+}
+
+type careServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCareServiceClient(cc grpc.ClientConnInterface) CareServiceClient {
+	return &careServiceClient{cc}
+}
+
+func (c *careServiceClient) ListCares(ctx context.Context, in *ListCaresRequest, opts ...grpc.CallOption) (*ListCaresResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCaresResponse)
+	err := c.cc.Invoke(ctx, CareService_ListCares_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *careServiceClient) GetCare(ctx context.Context, in *GetCareRequest, opts ...grpc.CallOption) (*Care, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Care)
+	err := c.cc.Invoke(ctx, CareService_GetCare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *careServiceClient) WatchDueCares(ctx context.Context, in *WatchDueCaresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Care], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CareService_ServiceDesc.Streams[0], CareService_WatchDueCares_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchDueCaresRequest, Care]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CareService_WatchDueCaresClient = grpc.ServerStreamingClient[Care]
+
+// CareServiceServer is the server API for CareService service.
+// All implementations must embed UnimplementedCareServiceServer
+// for forward compatibility.
+type CareServiceServer interface {
+	// ListCares returns the cares of the authenticated user, or of one of
+	// their plants when plant_id is set.
+	ListCares(context.Context, *ListCaresRequest) (*ListCaresResponse, error)
+	GetCare(context.Context, *GetCareRequest) (*Care, error)
+	// WatchDueCares first sends the cares of the authenticated user that are
+	// due now, then every care as it comes due, until the client cancels.
+	WatchDueCares(*WatchDueCaresRequest, grpc.ServerStreamingServer[Care]) error
+	mustEmbedUnimplementedCareServiceServer()
+}
+
+// UnimplementedCareServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCareServiceServer struct{}
+
+func (UnimplementedCareServiceServer) ListCares(context.Context, *ListCaresRequest) (*ListCaresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCares not implemented")
+}
+func (UnimplementedCareServiceServer) GetCare(context.Context, *GetCareRequest) (*Care, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCare not implemented")
+}
+func (UnimplementedCareServiceServer) WatchDueCares(*WatchDueCaresRequest, grpc.ServerStreamingServer[Care]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDueCares not implemented")
+}
+func (UnimplementedCareServiceServer) mustEmbedUnimplementedCareServiceServer() {}
+func (UnimplementedCareServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeCareServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CareServiceServer will
+// result in compilation errors.
+type UnsafeCareServiceServer interface {
+	mustEmbedUnimplementedCareServiceServer()
+}
+
+func RegisterCareServiceServer(s grpc.ServiceRegistrar, srv CareServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCareServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CareService_ServiceDesc, srv)
+}
+
+func _CareService_ListCares_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCaresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CareServiceServer).ListCares(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CareService_ListCares_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CareServiceServer).ListCares(ctx, req.(*ListCaresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CareService_GetCare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CareServiceServer).GetCare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CareService_GetCare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CareServiceServer).GetCare(ctx, req.(*GetCareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CareService_WatchDueCares_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDueCaresRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CareServiceServer).WatchDueCares(m, &grpc.GenericServerStream[WatchDueCaresRequest, Care]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CareService_WatchDueCaresServer = grpc.ServerStreamingServer[Care]
+
+// CareService_ServiceDesc is the grpc.ServiceDesc for CareService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CareService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plantcare.v1.CareService",
+	HandlerType: (*CareServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListCares",
+			Handler:    _CareService_ListCares_Handler,
+		},
+		{
+			MethodName: "GetCare",
+			Handler:    _CareService_GetCare_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchDueCares",
+			Handler:       _CareService_WatchDueCares_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "plantcare/v1/cares.proto",
+}
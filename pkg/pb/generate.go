@@ -0,0 +1,5 @@
+// Package pb holds the code generated from the protobuf definitions of the
+// gRPC API, in proto/plantcare/v1.
+package pb
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=github.com/mathehluiz/plant-care-tracker --go-grpc_out=../.. --go-grpc_opt=module=github.com/mathehluiz/plant-care-tracker plantcare/v1/users.proto plantcare/v1/plants.proto plantcare/v1/cares.proto
@@ -0,0 +1,668 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v4.25.3
+// source: plantcare/v1/plants.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Plant struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Species         string                 `protobuf:"bytes,3,opt,name=species,proto3" json:"species,omitempty"`
+	Location        string                 `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
+	AcquisitionDate *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=acquisition_date,json=acquisitionDate,proto3" json:"acquisition_date,omitempty"`
+	CareFrequency   int32                  `protobuf:"varint,6,opt,name=care_frequency,json=careFrequency,proto3" json:"care_frequency,omitempty"`
+	Status          string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	StatusReason    string                 `protobuf:"bytes,8,opt,name=status_reason,json=statusReason,proto3" json:"status_reason,omitempty"`
+	StatusChangedAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=status_changed_at,json=statusChangedAt,proto3" json:"status_changed_at,omitempty"`
+	Version         int32                  `protobuf:"varint,10,opt,name=version,proto3" json:"version,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+}
+
+func (x *Plant) Reset() {
+	*x = Plant{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_plantcare_v1_plants_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Plant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Plant) ProtoMessage() {}
+
+func (x *Plant) ProtoReflect() protoreflect.Message {
+	mi := &file_plantcare_v1_plants_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Plant.ProtoReflect.Descriptor instead.
+func (*Plant) Descriptor() ([]byte, []int) {
+	return file_plantcare_v1_plants_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Plant) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Plant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Plant) GetSpecies() string {
+	if x != nil {
+		return x.Species
+	}
+	return ""
+}
+
+func (x *Plant) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Plant) GetAcquisitionDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AcquisitionDate
+	}
+	return nil
+}
+
+func (x *Plant) GetCareFrequency() int32 {
+	if x != nil {
+		return x.CareFrequency
+	}
+	return 0
+}
+
+func (x *Plant) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Plant) GetStatusReason() string {
+	if x != nil {
+		return x.StatusReason
+	}
+	return ""
+}
+
+func (x *Plant) GetStatusChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StatusChangedAt
+	}
+	return nil
+}
+
+func (x *Plant) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Plant) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Plant) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListPlantsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Statuses []string `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+}
+
+func (x *ListPlantsRequest) Reset() {
+	*x = ListPlantsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_plantcare_v1_plants_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPlantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPlantsRequest) ProtoMessage() {}
+
+func (x *ListPlantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plantcare_v1_plants_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPlantsRequest.ProtoReflect.Descriptor instead.
+func (*ListPlantsRequest) Descriptor() ([]byte, []int) {
+	return file_plantcare_v1_plants_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListPlantsRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+type ListPlantsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Plants []*Plant `protobuf:"bytes,1,rep,name=plants,proto3" json:"plants,omitempty"`
+}
+
+func (x *ListPlantsResponse) Reset() {
+	*x = ListPlantsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_plantcare_v1_plants_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPlantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPlantsResponse) ProtoMessage() {}
+
+func (x *ListPlantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plantcare_v1_plants_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPlantsResponse.ProtoReflect.Descriptor instead.
+func (*ListPlantsResponse) Descriptor() ([]byte, []int) {
+	return file_plantcare_v1_plants_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListPlantsResponse) GetPlants() []*Plant {
+	if x != nil {
+		return x.Plants
+	}
+	return nil
+}
+
+type GetPlantRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetPlantRequest) Reset() {
+	*x = GetPlantRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_plantcare_v1_plants_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPlantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPlantRequest) ProtoMessage() {}
+
+func (x *GetPlantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plantcare_v1_plants_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPlantRequest.ProtoReflect.Descriptor instead.
+func (*GetPlantRequest) Descriptor() ([]byte, []int) {
+	return file_plantcare_v1_plants_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetPlantRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CreatePlantRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Species         string                 `protobuf:"bytes,2,opt,name=species,proto3" json:"species,omitempty"`
+	Location        string                 `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	AcquisitionDate *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=acquisition_date,json=acquisitionDate,proto3" json:"acquisition_date,omitempty"`
+	CareFrequency   int32                  `protobuf:"varint,5,opt,name=care_frequency,json=careFrequency,proto3" json:"care_frequency,omitempty"`
+}
+
+func (x *CreatePlantRequest) Reset() {
+	*x = CreatePlantRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_plantcare_v1_plants_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreatePlantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePlantRequest) ProtoMessage() {}
+
+func (x *CreatePlantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plantcare_v1_plants_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePlantRequest.ProtoReflect.Descriptor instead.
+func (*CreatePlantRequest) Descriptor() ([]byte, []int) {
+	return file_plantcare_v1_plants_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreatePlantRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreatePlantRequest) GetSpecies() string {
+	if x != nil {
+		return x.Species
+	}
+	return ""
+}
+
+func (x *CreatePlantRequest) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *CreatePlantRequest) GetAcquisitionDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AcquisitionDate
+	}
+	return nil
+}
+
+func (x *CreatePlantRequest) GetCareFrequency() int32 {
+	if x != nil {
+		return x.CareFrequency
+	}
+	return 0
+}
+
+type DeletePlantRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version int32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *DeletePlantRequest) Reset() {
+	*x = DeletePlantRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_plantcare_v1_plants_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeletePlantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePlantRequest) ProtoMessage() {}
+
+func (x *DeletePlantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plantcare_v1_plants_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePlantRequest.ProtoReflect.Descriptor instead.
+func (*DeletePlantRequest) Descriptor() ([]byte, []int) {
+	return file_plantcare_v1_plants_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeletePlantRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeletePlantRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+var File_plantcare_v1_plants_proto protoreflect.FileDescriptor
+
+var file_plantcare_v1_plants_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x70,
+	0x6c, 0x61, 0x6e, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x70, 0x6c, 0x61,
+	0x6e, 0x74, 0x63, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xe4, 0x03, 0x0a, 0x05, 0x50, 0x6c, 0x61, 0x6e,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x70, 0x65, 0x63, 0x69, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x70, 0x65, 0x63, 0x69, 0x65, 0x73, 0x12,
+	0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x45, 0x0a, 0x10, 0x61,
+	0x63, 0x71, 0x75, 0x69, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0f, 0x61, 0x63, 0x71, 0x75, 0x69, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61,
+	0x74, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x61, 0x72, 0x65, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x63, 0x61, 0x72, 0x65,
+	0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x46, 0x0a, 0x11, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0f, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x41, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x2f,
+	0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6c, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x22,
+	0x41, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6c, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x74, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x6e,
+	0x74, 0x73, 0x22, 0x21, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x61, 0x6e, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0xcc, 0x01, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x50, 0x6c, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x70, 0x65, 0x63, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x73, 0x70, 0x65, 0x63, 0x69, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x45, 0x0a, 0x10, 0x61, 0x63, 0x71, 0x75, 0x69, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0f, 0x61, 0x63,
+	0x71, 0x75, 0x69, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61, 0x74, 0x65, 0x12, 0x25, 0x0a,
+	0x0e, 0x63, 0x61, 0x72, 0x65, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x63, 0x61, 0x72, 0x65, 0x46, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x79, 0x22, 0x3e, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x6c,
+	0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x32, 0xae, 0x02, 0x0a, 0x0c, 0x50, 0x6c, 0x61, 0x6e, 0x74, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4f, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6c, 0x61,
+	0x6e, 0x74, 0x73, 0x12, 0x1f, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6c, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6c, 0x61, 0x6e, 0x74, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x61,
+	0x6e, 0x74, 0x12, 0x1d, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x13, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x74, 0x12, 0x44, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x50, 0x6c, 0x61, 0x6e, 0x74, 0x12, 0x20, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63, 0x61, 0x72,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x70, 0x6c, 0x61, 0x6e, 0x74, 0x63,
+	0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x74, 0x12, 0x47, 0x0a, 0x0b,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x6e, 0x74, 0x12, 0x20, 0x2e, 0x70, 0x6c,
+	0x61, 0x6e, 0x74, 0x63, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x50, 0x6c, 0x61, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x61, 0x74, 0x68, 0x65, 0x68, 0x6c, 0x75, 0x69, 0x7a, 0x2f, 0x70,
+	0x6c, 0x61, 0x6e, 0x74, 0x2d, 0x63, 0x61, 0x72, 0x65, 0x2d, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65,
+	0x72, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_plantcare_v1_plants_proto_rawDescOnce sync.Once
+	file_plantcare_v1_plants_proto_rawDescData = file_plantcare_v1_plants_proto_rawDesc
+)
+
+func file_plantcare_v1_plants_proto_rawDescGZIP() []byte {
+	file_plantcare_v1_plants_proto_rawDescOnce.Do(func() {
+		file_plantcare_v1_plants_proto_rawDescData = protoimpl.X.CompressGZIP(file_plantcare_v1_plants_proto_rawDescData)
+	})
+	return file_plantcare_v1_plants_proto_rawDescData
+}
+
+var file_plantcare_v1_plants_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_plantcare_v1_plants_proto_goTypes = []any{
+	(*Plant)(nil),                 // 0: plantcare.v1.Plant
+	(*ListPlantsRequest)(nil),     // 1: plantcare.v1.ListPlantsRequest
+	(*ListPlantsResponse)(nil),    // 2: plantcare.v1.ListPlantsResponse
+	(*GetPlantRequest)(nil),       // 3: plantcare.v1.GetPlantRequest
+	(*CreatePlantRequest)(nil),    // 4: plantcare.v1.CreatePlantRequest
+	(*DeletePlantRequest)(nil),    // 5: plantcare.v1.DeletePlantRequest
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 7: google.protobuf.Empty
+}
+var file_plantcare_v1_plants_proto_depIdxs = []int32{
+	6,  // 0: plantcare.v1.Plant.acquisition_date:type_name -> google.protobuf.Timestamp
+	6,  // 1: plantcare.v1.Plant.status_changed_at:type_name -> google.protobuf.Timestamp
+	6,  // 2: plantcare.v1.Plant.created_at:type_name -> google.protobuf.Timestamp
+	6,  // 3: plantcare.v1.Plant.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: plantcare.v1.ListPlantsResponse.plants:type_name -> plantcare.v1.Plant
+	6,  // 5: plantcare.v1.CreatePlantRequest.acquisition_date:type_name -> google.protobuf.Timestamp
+	1,  // 6: plantcare.v1.PlantService.ListPlants:input_type -> plantcare.v1.ListPlantsRequest
+	3,  // 7: plantcare.v1.PlantService.GetPlant:input_type -> plantcare.v1.GetPlantRequest
+	4,  // 8: plantcare.v1.PlantService.CreatePlant:input_type -> plantcare.v1.CreatePlantRequest
+	5,  // 9: plantcare.v1.PlantService.DeletePlant:input_type -> plantcare.v1.DeletePlantRequest
+	2,  // 10: plantcare.v1.PlantService.ListPlants:output_type -> plantcare.v1.ListPlantsResponse
+	0,  // 11: plantcare.v1.PlantService.GetPlant:output_type -> plantcare.v1.Plant
+	0,  // 12: plantcare.v1.PlantService.CreatePlant:output_type -> plantcare.v1.Plant
+	7,  // 13: plantcare.v1.PlantService.DeletePlant:output_type -> google.protobuf.Empty
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_plantcare_v1_plants_proto_init() }
+func file_plantcare_v1_plants_proto_init() {
+	if File_plantcare_v1_plants_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_plantcare_v1_plants_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Plant); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_plantcare_v1_plants_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*ListPlantsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_plantcare_v1_plants_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*ListPlantsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_plantcare_v1_plants_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*GetPlantRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_plantcare_v1_plants_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*CreatePlantRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_plantcare_v1_plants_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*DeletePlantRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_plantcare_v1_plants_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_plantcare_v1_plants_proto_goTypes,
+		DependencyIndexes: file_plantcare_v1_plants_proto_depIdxs,
+		MessageInfos:      file_plantcare_v1_plants_proto_msgTypes,
+	}.Build()
+	File_plantcare_v1_plants_proto = out.File
+	file_plantcare_v1_plants_proto_rawDesc = nil
+	file_plantcare_v1_plants_proto_goTypes = nil
+	file_plantcare_v1_plants_proto_depIdxs = nil
+}
@@ -0,0 +1,244 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v4.25.3
+// source: plantcare/v1/plants.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PlantService_ListPlants_FullMethodName  = "/plantcare.v1.PlantService/ListPlants"
+	PlantService_GetPlant_FullMethodName    = "/plantcare.v1.PlantService/GetPlant"
+	PlantService_CreatePlant_FullMethodName = "/plantcare.v1.PlantService/CreatePlant"
+	PlantService_DeletePlant_FullMethodName = "/plantcare.v1.PlantService/DeletePlant"
+)
+
+// PlantServiceClient is the client API for PlantService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PlantServiceClient interface {
+	// ListPlants returns the plants of the authenticated user in the given
+	// statuses, or the active ones when none is given.
+	ListPlants(ctx context.Context, in *ListPlantsRequest, opts ...grpc.CallOption) (*ListPlantsResponse, error)
+	GetPlant(ctx context.Context, in *GetPlantRequest, opts ...grpc.CallOption) (*Plant, error)
+	CreatePlant(ctx context.Context, in *CreatePlantRequest, opts ...grpc.CallOption) (*Plant, error)
+	// DeletePlant fails with FAILED_PRECONDITION when version is set and the
+	// plant is no longer at it.
+	DeletePlant(ctx context.Context, in *DeletePlantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type plantServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPlantServiceClient(cc grpc.ClientConnInterface) PlantServiceClient {
+	return &plantServiceClient{cc}
+}
+
+func (c *plantServiceClient) ListPlants(ctx context.Context, in *ListPlantsRequest, opts ...grpc.CallOption) (*ListPlantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPlantsResponse)
+	err := c.cc.Invoke(ctx, PlantService_ListPlants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantServiceClient) GetPlant(ctx context.Context, in *GetPlantRequest, opts ...grpc.CallOption) (*Plant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Plant)
+	err := c.cc.Invoke(ctx, PlantService_GetPlant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantServiceClient) CreatePlant(ctx context.Context, in *CreatePlantRequest, opts ...grpc.CallOption) (*Plant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Plant)
+	err := c.cc.Invoke(ctx, PlantService_CreatePlant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *plantServiceClient) DeletePlant(ctx context.Context, in *DeletePlantRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PlantService_DeletePlant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PlantServiceServer is the server API for PlantService service.
+// All implementations must embed UnimplementedPlantServiceServer
+// for forward compatibility.
+type PlantServiceServer interface {
+	// ListPlants returns the plants of the authenticated user in the given
+	// statuses, or the active ones when none is given.
+	ListPlants(context.Context, *ListPlantsRequest) (*ListPlantsResponse, error)
+	GetPlant(context.Context, *GetPlantRequest) (*Plant, error)
+	CreatePlant(context.Context, *CreatePlantRequest) (*Plant, error)
+	// DeletePlant fails with FAILED_PRECONDITION when version is set and the
+	// plant is no longer at it.
+	DeletePlant(context.Context, *DeletePlantRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedPlantServiceServer()
+}
+
+// UnimplementedPlantServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPlantServiceServer struct{}
+
+func (UnimplementedPlantServiceServer) ListPlants(context.Context, *ListPlantsRequest) (*ListPlantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPlants not implemented")
+}
+func (UnimplementedPlantServiceServer) GetPlant(context.Context, *GetPlantRequest) (*Plant, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlant not implemented")
+}
+func (UnimplementedPlantServiceServer) CreatePlant(context.Context, *CreatePlantRequest) (*Plant, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePlant not implemented")
+}
+func (UnimplementedPlantServiceServer) DeletePlant(context.Context, *DeletePlantRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePlant not implemented")
+}
+func (UnimplementedPlantServiceServer) mustEmbedUnimplementedPlantServiceServer() {}
+func (UnimplementedPlantServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafePlantServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PlantServiceServer will
+// result in compilation errors.
+type UnsafePlantServiceServer interface {
+	mustEmbedUnimplementedPlantServiceServer()
+}
+
+func RegisterPlantServiceServer(s grpc.ServiceRegistrar, srv PlantServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPlantServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PlantService_ServiceDesc, srv)
+}
+
+func _PlantService_ListPlants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPlantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantServiceServer).ListPlants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantService_ListPlants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantServiceServer).ListPlants(ctx, req.(*ListPlantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantService_GetPlant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantServiceServer).GetPlant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantService_GetPlant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantServiceServer).GetPlant(ctx, req.(*GetPlantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantService_CreatePlant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePlantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantServiceServer).CreatePlant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantService_CreatePlant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantServiceServer).CreatePlant(ctx, req.(*CreatePlantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PlantService_DeletePlant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePlantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlantServiceServer).DeletePlant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PlantService_DeletePlant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlantServiceServer).DeletePlant(ctx, req.(*DeletePlantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PlantService_ServiceDesc is the grpc.ServiceDesc for PlantService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PlantService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plantcare.v1.PlantService",
+	HandlerType: (*PlantServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListPlants",
+			Handler:    _PlantService_ListPlants_Handler,
+		},
+		{
+			MethodName: "GetPlant",
+			Handler:    _PlantService_GetPlant_Handler,
+		},
+		{
+			MethodName: "CreatePlant",
+			Handler:    _PlantService_CreatePlant_Handler,
+		},
+		{
+			MethodName: "DeletePlant",
+			Handler:    _PlantService_DeletePlant_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plantcare/v1/plants.proto",
+}
@@ -0,0 +1,471 @@
+// Package rrule parses and expands the subset of RFC 5545 recurrence rules
+// used for care schedules: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL,
+// COUNT, UNTIL, BYDAY (with ordinals for MONTHLY and YEARLY), BYMONTHDAY,
+// BYMONTH and WKST.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+type Frequency int
+
+const (
+	Daily Frequency = iota
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencies = map[string]Frequency{
+	"DAILY":   Daily,
+	"WEEKLY":  Weekly,
+	"MONTHLY": Monthly,
+	"YEARLY":  Yearly,
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var allMonths = []time.Month{
+	time.January, time.February, time.March, time.April, time.May, time.June,
+	time.July, time.August, time.September, time.October, time.November, time.December,
+}
+
+// maxPeriods bounds how many periods are scanned so that rules that can
+// never match, like BYMONTH=2;BYMONTHDAY=30, do not loop forever.
+const maxPeriods = 100000
+
+// WeekdayNum is a BYDAY value. N is the optional ordinal, e.g. 1 for the
+// first or -1 for the last matching weekday of the period, and 0 for every
+// matching weekday.
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	// WeekStart is the first day of the weeks WEEKLY rules count their
+	// INTERVAL in, Monday unless WKST sets it.
+	WeekStart time.Weekday
+
+	until         time.Time
+	untilFloating bool
+	raw           string
+}
+
+// Parse parses an RRULE value such as "FREQ=MONTHLY;BYDAY=1SU". A leading
+// "RRULE:" is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "RRULE:"))
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	r := &Rule{Interval: 1, WeekStart: time.Monday, raw: s}
+	seen := map[string]bool{}
+	hasFreq := false
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+
+		key = strings.ToUpper(key)
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicated %s", ErrInvalidRule, key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.Freq, hasFreq = frequencies[strings.ToUpper(value)]
+			if !hasFreq {
+				err = fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = parsePositive(value)
+		case "COUNT":
+			r.Count, err = parsePositive(value)
+		case "UNTIL":
+			err = r.parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseByMonthDay(value)
+		case "BYMONTH":
+			r.ByMonth, err = parseByMonth(value)
+		case "WKST":
+			var ok bool
+			if r.WeekStart, ok = weekdays[strings.ToUpper(value)]; !ok {
+				err = fmt.Errorf("invalid WKST %q", value)
+			}
+		default:
+			err = fmt.Errorf("unsupported part %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRule, err)
+		}
+	}
+
+	if !hasFreq {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+
+	if seen["COUNT"] && seen["UNTIL"] {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL cannot be combined", ErrInvalidRule)
+	}
+
+	if r.Freq == Daily || r.Freq == Weekly {
+		for _, d := range r.ByDay {
+			if d.N != 0 {
+				return nil, fmt.Errorf("%w: BYDAY ordinals need a MONTHLY or YEARLY rule", ErrInvalidRule)
+			}
+		}
+	}
+
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return nil, fmt.Errorf("%w: BYMONTHDAY cannot be used with a WEEKLY rule", ErrInvalidRule)
+	}
+
+	return r, nil
+}
+
+func (r *Rule) String() string {
+	return r.raw
+}
+
+// Between returns the occurrences of the rule starting at start that fall in
+// [from, to), leaving out the excluded instants (EXDATE).
+func (r *Rule) Between(start, from, to time.Time, exclude []time.Time) []time.Time {
+	var occurrences []time.Time
+
+	r.iterate(start, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) && !excluded(t, exclude) {
+			occurrences = append(occurrences, t)
+		}
+		return true
+	})
+
+	return occurrences
+}
+
+// After returns the first occurrence strictly after t, leaving out the
+// excluded instants. The second result is false once the rule is exhausted.
+func (r *Rule) After(start, t time.Time, exclude []time.Time) (time.Time, bool) {
+	var next time.Time
+	found := false
+
+	r.iterate(start, func(o time.Time) bool {
+		if o.After(t) && !excluded(o, exclude) {
+			next, found = o, true
+			return false
+		}
+		return true
+	})
+
+	return next, found
+}
+
+// iterate calls fn with every occurrence in order until fn returns false or
+// the rule ends. The start is always the first candidate when it matches.
+func (r *Rule) iterate(start time.Time, fn func(time.Time) bool) {
+	until := r.until
+	if r.untilFloating {
+		hour, min, sec := until.Clock()
+		until = time.Date(until.Year(), until.Month(), until.Day(), hour, min, sec, 0, start.Location())
+	}
+
+	count := 0
+	for period := 0; period < maxPeriods; period++ {
+		for _, t := range r.candidates(start, period) {
+			if t.Before(start) {
+				continue
+			}
+			if !until.IsZero() && t.After(until) {
+				return
+			}
+			if !fn(t) {
+				return
+			}
+			count++
+			if r.Count > 0 && count >= r.Count {
+				return
+			}
+		}
+	}
+}
+
+// candidates returns the sorted occurrences of the n-th period after start.
+func (r *Rule) candidates(start time.Time, n int) []time.Time {
+	loc := start.Location()
+	hour, min, sec := start.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, min, sec, 0, loc)
+	}
+
+	var days []time.Time
+
+	switch r.Freq {
+	case Daily:
+		t := at(start.Year(), start.Month(), start.Day()+n*r.Interval)
+		if r.matchesMonth(t.Month()) && r.matchesMonthDay(t) && r.matchesWeekday(t.Weekday()) {
+			days = append(days, t)
+		}
+	case Weekly:
+		offset := r.weekOffset(start.Weekday())
+		weekStart := at(start.Year(), start.Month(), start.Day()-offset+7*n*r.Interval)
+		if t := weekStart.AddDate(0, 0, offset); len(r.ByDay) == 0 && r.matchesMonth(t.Month()) {
+			days = append(days, t)
+		}
+		for _, d := range r.ByDay {
+			t := weekStart.AddDate(0, 0, r.weekOffset(d.Day))
+			if r.matchesMonth(t.Month()) {
+				days = append(days, t)
+			}
+		}
+	case Monthly:
+		first := at(start.Year(), start.Month()+time.Month(n*r.Interval), 1)
+		if r.matchesMonth(first.Month()) {
+			days = r.monthDays(first, start.Day())
+		}
+	case Yearly:
+		year := start.Year() + n*r.Interval
+		if len(r.ByDay) > 0 && len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 {
+			days = r.yearWeekdays(at(year, time.January, 1))
+			break
+		}
+
+		// Without BYMONTH, BYMONTHDAY applies to every month of the year,
+		// and the start month is repeated otherwise.
+		months := r.ByMonth
+		if len(months) == 0 && len(r.ByMonthDay) > 0 {
+			months = allMonths
+		} else if len(months) == 0 {
+			months = []time.Month{start.Month()}
+		}
+		for _, m := range months {
+			days = append(days, r.monthDays(at(year, m, 1), start.Day())...)
+		}
+	}
+
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// weekOffset returns how many days after the start of the week w falls.
+func (r *Rule) weekOffset(w time.Weekday) int {
+	return (int(w) - int(r.WeekStart) + 7) % 7
+}
+
+// monthDays expands BYMONTHDAY and BYDAY within the month starting at
+// first, falling back to defaultDay when neither is set.
+func (r *Rule) monthDays(first time.Time, defaultDay int) []time.Time {
+	last := first.AddDate(0, 1, -1).Day()
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if defaultDay > last {
+			return nil
+		}
+		return []time.Time{first.AddDate(0, 0, defaultDay-1)}
+	}
+
+	var days []time.Time
+	for d := 1; d <= last; d++ {
+		t := first.AddDate(0, 0, d-1)
+
+		if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(t) {
+			continue
+		}
+
+		if len(r.ByDay) > 0 && !matchesOrdinal(r.ByDay, t.Weekday(), (d-1)/7+1, (last-d)/7+1) {
+			continue
+		}
+
+		days = append(days, t)
+	}
+
+	return days
+}
+
+// yearWeekdays expands BYDAY, with ordinals relative to the whole year.
+func (r *Rule) yearWeekdays(first time.Time) []time.Time {
+	last := first.AddDate(1, 0, -1).YearDay()
+
+	var days []time.Time
+	for d := 1; d <= last; d++ {
+		t := first.AddDate(0, 0, d-1)
+		if matchesOrdinal(r.ByDay, t.Weekday(), (d-1)/7+1, (last-d)/7+1) {
+			days = append(days, t)
+		}
+	}
+
+	return days
+}
+
+func (r *Rule) matchesMonth(m time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, bm := range r.ByMonth {
+		if bm == m {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+
+	last := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	for _, d := range r.ByMonthDay {
+		if d == t.Day() || (d < 0 && last+d+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekday(w time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d.Day == w {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesOrdinal reports whether a day with weekday w, being the nth such
+// weekday from the start and the fromEnd-th from the end of its period,
+// matches any of the BYDAY values.
+func matchesOrdinal(byDay []WeekdayNum, w time.Weekday, nth, fromEnd int) bool {
+	for _, d := range byDay {
+		if d.Day != w {
+			continue
+		}
+		if d.N == 0 || d.N == nth || d.N == -fromEnd {
+			return true
+		}
+	}
+	return false
+}
+
+func excluded(t time.Time, exclude []time.Time) bool {
+	for _, e := range exclude {
+		if e.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseUntil accepts a UTC date-time, or a local date-time or date that is
+// later interpreted in the timezone of the start. A date includes the
+// whole day.
+func (r *Rule) parseUntil(value string) error {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		r.until = t
+		return nil
+	}
+
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		r.until, r.untilFloating = t, true
+		return nil
+	}
+
+	if t, err := time.Parse("20060102", value); err == nil {
+		r.until, r.untilFloating = t.Add(24*time.Hour-time.Second), true
+		return nil
+	}
+
+	return fmt.Errorf("invalid UNTIL %q", value)
+}
+
+func parsePositive(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	return n, nil
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, v := range strings.Split(strings.ToUpper(value), ",") {
+		if len(v) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %q", v)
+		}
+
+		day, ok := weekdays[v[len(v)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %q", v)
+		}
+
+		n := 0
+		if ordinal := v[:len(v)-2]; ordinal != "" {
+			var err error
+			n, err = strconv.Atoi(ordinal)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid BYDAY %q", v)
+			}
+		}
+
+		days = append(days, WeekdayNum{N: n, Day: day})
+	}
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+	for _, v := range strings.Split(value, ",") {
+		d, err := strconv.Atoi(v)
+		if err != nil || d == 0 || d < -31 || d > 31 {
+			return nil, fmt.Errorf("invalid BYMONTHDAY %q", v)
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+func parseByMonth(value string) ([]time.Month, error) {
+	var months []time.Month
+	for _, v := range strings.Split(value, ",") {
+		m, err := strconv.Atoi(v)
+		if err != nil || m < 1 || m > 12 {
+			return nil, fmt.Errorf("invalid BYMONTH %q", v)
+		}
+		months = append(months, time.Month(m))
+	}
+	return months, nil
+}
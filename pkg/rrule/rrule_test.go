@@ -0,0 +1,190 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		purpose string
+		rule    string
+		wantErr error
+	}{
+		{"should parse a weekly rule", "FREQ=WEEKLY;BYDAY=MO,TH", nil},
+		{"should accept the RRULE prefix", "RRULE:FREQ=MONTHLY;BYDAY=1SU", nil},
+		{"should parse an until date", "FREQ=DAILY;INTERVAL=2;UNTIL=20261231", nil},
+		{"should require a frequency", "INTERVAL=2", ErrInvalidRule},
+		{"should reject unknown parts", "FREQ=DAILY;BYHOUR=9", ErrInvalidRule},
+		{"should reject count with until", "FREQ=DAILY;COUNT=2;UNTIL=20261231", ErrInvalidRule},
+		{"should reject ordinals on weekly rules", "FREQ=WEEKLY;BYDAY=1MO", ErrInvalidRule},
+		{"should reject invalid weekdays", "FREQ=WEEKLY;BYDAY=XX", ErrInvalidRule},
+		{"should parse the week start", "FREQ=WEEKLY;INTERVAL=2;WKST=SU", nil},
+		{"should reject an invalid week start", "FREQ=WEEKLY;WKST=XX", ErrInvalidRule},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			_, err := Parse(tt.rule)
+			assert.True(t, errors.Is(err, tt.wantErr), "want %v, got %v", tt.wantErr, err)
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	assert.NoError(t, err)
+
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 9, 0, 0, 0, saoPaulo)
+	}
+
+	cases := []struct {
+		purpose string
+		rule    string
+		start   time.Time
+		from    time.Time
+		to      time.Time
+		exclude []time.Time
+		want    []time.Time
+	}{
+		{
+			"should expand every Monday and Thursday",
+			"FREQ=WEEKLY;BYDAY=MO,TH",
+			day(2026, 11, 2),
+			day(2026, 11, 1),
+			day(2026, 11, 15),
+			nil,
+			[]time.Time{day(2026, 11, 2), day(2026, 11, 5), day(2026, 11, 9), day(2026, 11, 12)},
+		},
+		{
+			"should expand the first Sunday of every month",
+			"FREQ=MONTHLY;BYDAY=1SU",
+			day(2026, 11, 1),
+			day(2026, 11, 1),
+			day(2027, 2, 1),
+			nil,
+			[]time.Time{day(2026, 11, 1), day(2026, 12, 6), day(2027, 1, 3)},
+		},
+		{
+			"should expand the last day of the month",
+			"FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+			day(2026, 12, 1),
+			day(2026, 1, 1),
+			day(2028, 1, 1),
+			nil,
+			[]time.Time{day(2026, 12, 31), day(2027, 1, 31), day(2027, 2, 28)},
+		},
+		{
+			"should leave out the excluded dates",
+			"FREQ=DAILY;INTERVAL=2",
+			day(2026, 11, 1),
+			day(2026, 11, 1),
+			day(2026, 11, 8),
+			[]time.Time{day(2026, 11, 3)},
+			[]time.Time{day(2026, 11, 1), day(2026, 11, 5), day(2026, 11, 7)},
+		},
+		{
+			"should stop at the until date",
+			"FREQ=YEARLY;BYMONTH=3,9;UNTIL=20270301",
+			day(2026, 3, 1),
+			day(2026, 1, 1),
+			day(2030, 1, 1),
+			nil,
+			[]time.Time{day(2026, 3, 1), day(2026, 9, 1), day(2027, 3, 1)},
+		},
+		{
+			"should skip months without the start day",
+			"FREQ=MONTHLY",
+			day(2027, 1, 31),
+			day(2027, 1, 1),
+			day(2027, 5, 1),
+			nil,
+			[]time.Time{day(2027, 1, 31), day(2027, 3, 31)},
+		},
+		{
+			"should expand BYMONTHDAY in every month of a yearly rule without BYMONTH",
+			"FREQ=YEARLY;BYMONTHDAY=1,15",
+			day(2026, 11, 1),
+			day(2026, 11, 1),
+			day(2027, 2, 1),
+			nil,
+			[]time.Time{day(2026, 11, 1), day(2026, 11, 15), day(2026, 12, 1), day(2026, 12, 15), day(2027, 1, 1), day(2027, 1, 15)},
+		},
+		{
+			"should count the weeks of a weekly rule from Monday by default",
+			"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,SU;COUNT=4",
+			day(2026, 11, 3),
+			day(2026, 11, 1),
+			day(2027, 1, 1),
+			nil,
+			[]time.Time{day(2026, 11, 3), day(2026, 11, 8), day(2026, 11, 17), day(2026, 11, 22)},
+		},
+		{
+			"should count the weeks of a weekly rule from WKST",
+			"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,SU;WKST=SU;COUNT=4",
+			day(2026, 11, 3),
+			day(2026, 11, 1),
+			day(2027, 1, 1),
+			nil,
+			[]time.Time{day(2026, 11, 3), day(2026, 11, 15), day(2026, 11, 17), day(2026, 11, 29)},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.want, r.Between(tt.start, tt.from, tt.to, tt.exclude))
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	start := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		purpose   string
+		rule      string
+		after     time.Time
+		want      time.Time
+		wantFound bool
+	}{
+		{
+			"should return the next occurrence",
+			"FREQ=WEEKLY;BYDAY=MO,TH",
+			start,
+			time.Date(2026, 11, 5, 9, 0, 0, 0, time.UTC),
+			true,
+		},
+		{
+			"should report an exhausted rule",
+			"FREQ=DAILY;COUNT=2",
+			start.AddDate(0, 0, 1),
+			time.Time{},
+			false,
+		},
+		{
+			"should give up on rules that never match",
+			"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			start,
+			time.Time{},
+			false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			assert.NoError(t, err)
+
+			got, found := r.After(start, tt.after, nil)
+			assert.Equal(t, tt.wantFound, found)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,40 +1,126 @@
 package validate
 
 import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
 	brazilian_portuguese "github.com/go-playground/locales/pt_BR"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	es_translations "github.com/go-playground/validator/v10/translations/es"
 	br_translations "github.com/go-playground/validator/v10/translations/pt_BR"
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/i18n"
 )
 
 var Validate *validator.Validate
-var Translate ut.Translator
+
+// translators translate the messages of the rules, by locale.
+var translators = make(map[string]ut.Translator)
+
+var defaultTranslations = map[string]func(*validator.Validate, ut.Translator) error{
+	"en":    en_translations.RegisterDefaultTranslations,
+	"pt_BR": br_translations.RegisterDefaultTranslations,
+	"es":    es_translations.RegisterDefaultTranslations,
+}
 
 func init() {
-	ptbr := brazilian_portuguese.New()
-	uni := ut.New(ptbr, ptbr)
-	Translate, _ = uni.GetTranslator("pt_BR")
+	uni := ut.New(en.New(), en.New(), brazilian_portuguese.New(), es.New())
 
 	Validate = validator.New()
-	br_translations.RegisterDefaultTranslations(Validate, Translate)
+	Validate.RegisterTagNameFunc(jsonName)
+
+	for _, locale := range i18n.Supported {
+		translator, _ := uni.GetTranslator(locale)
+		defaultTranslations[locale](Validate, translator)
+		translators[locale] = translator
+	}
+
+	// Rules without a default translation take theirs from the catalogs.
+	for _, tag := range []string{"timezone", "http_url", "datetime"} {
+		registerMessages(tag)
+	}
+}
+
+// Register adds a rule that can be used in validate tags. Its message is
+// validation.<tag> in the i18n catalogs, in which {0} stands for the field
+// name and {1} for the parameter of the rule.
+func Register(tag string, fn func(value string) bool) {
+	Validate.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+		return fn(fl.Field().String())
+	})
+	registerMessages(tag)
 }
 
-func Struct(str interface{}) []string {
-	var errors []string
+func registerMessages(tag string) {
+	for locale, translator := range translators {
+		message := i18n.T(locale, "validation."+tag)
+		Validate.RegisterTranslation(tag, translator, func(ut ut.Translator) error {
+			return ut.Add(tag, message, true)
+		}, func(ut ut.Translator, fe validator.FieldError) string {
+			t, _ := ut.T(tag, fe.Field(), fe.Param())
+			return t
+		})
+	}
+}
 
+// jsonName names fields as they are sent by clients.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+var index = regexp.MustCompile(`\[([^\]]*)\]`)
+
+// Struct checks the fields of str against their validate tags. Every field
+// that fails is reported in the returned errs.FieldErrors, named by its path
+// as sent by clients, such as items.0.name, with its message in every
+// supported locale.
+func Struct(str interface{}) error {
 	err := Validate.Struct(str)
+	if err == nil {
+		return nil
+	}
 
-	if err != nil {
-		errs, ok := err.(validator.ValidationErrors)
-		if !ok {
-			errors = append(errors, "Erro interno ao validar campos")
-			return errors
-		}
+	validations, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+
+	root := reflect.Indirect(reflect.ValueOf(str)).Type().Name()
 
-		for _, e := range errs {
-			errors = append(errors, e.Translate(Translate))
+	fields := make(errs.FieldErrors, 0, len(validations))
+	for _, e := range validations {
+		messages := make(map[string]string, len(translators))
+		for locale, translator := range translators {
+			messages[locale] = e.Translate(translator)
 		}
+
+		fields = append(fields, errs.FieldError{
+			Field:    field(root, e.Namespace()),
+			Rule:     e.Tag(),
+			Message:  messages[i18n.Default],
+			Messages: messages,
+		})
 	}
+	return fields
+}
 
-	return errors
+// field drops the name of the root struct, which anonymous structs do not
+// have, from a namespace and writes indexes as path segments.
+func field(root, namespace string) string {
+	if root != "" {
+		namespace = strings.TrimPrefix(namespace, root+".")
+	}
+	return index.ReplaceAllString(namespace, ".$1")
 }
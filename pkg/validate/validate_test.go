@@ -0,0 +1,76 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/mathehluiz/plant-care-tracker/internal/errs"
+	"github.com/mathehluiz/plant-care-tracker/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+type item struct {
+	Name     string `json:"name" validate:"required"`
+	Interval int    `json:"interval" validate:"min=1"`
+}
+
+func TestStruct(t *testing.T) {
+	cases := []struct {
+		purpose    string
+		value      any
+		wantFields []string
+		wantRules  []string
+	}{
+		{
+			"should accept valid values",
+			struct {
+				Name string `json:"name" validate:"required"`
+			}{"Fern"},
+			nil,
+			nil,
+		},
+		{
+			"should report every invalid field by its json name",
+			struct {
+				Name     string `json:"name" validate:"required"`
+				Email    string `json:"email" validate:"email"`
+				Timezone string `json:"timezone,omitempty" validate:"timezone"`
+			}{"", "fern", "Mars/Olympus"},
+			[]string{"name", "email", "timezone"},
+			[]string{"required", "email", "timezone"},
+		},
+		{
+			"should name nested fields by their path",
+			struct {
+				Items []item `json:"items" validate:"dive"`
+			}{[]item{{"Water", 7}, {"", 0}}},
+			[]string{"items.1.name", "items.1.interval"},
+			[]string{"required", "min"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.purpose, func(t *testing.T) {
+			err := Struct(tt.value)
+			if tt.wantFields == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var fields errs.FieldErrors
+			assert.ErrorAs(t, err, &fields)
+
+			var gotFields, gotRules []string
+			for _, f := range fields {
+				gotFields = append(gotFields, f.Field)
+				gotRules = append(gotRules, f.Rule)
+				assert.NotEmpty(t, f.Message)
+				for _, locale := range i18n.Supported {
+					assert.NotEmpty(t, f.Messages[locale], locale)
+					assert.NotContains(t, f.Messages[locale], "Error:Field validation", locale)
+				}
+			}
+			assert.Equal(t, tt.wantFields, gotFields)
+			assert.Equal(t, tt.wantRules, gotRules)
+		})
+	}
+}